@@ -1,5 +1,7 @@
 package consts
 
+import "time"
+
 const (
 	ApiPrefix            = "/ps"
 	GoRoutineCount       = 100_000 //最大的协程池数量
@@ -14,6 +16,24 @@ const (
 	ScriptHistoryCount   = 3          //script最大的历史版本数量
 	MaxLogReplicaCount   = 32         //运行日志表最大的副本数量
 	PSResponseKey        = "response"
+	IdempotentReplay     = "Idempotent-Replay"
+	RunModeAsync         = "async"          //异步执行模式
+	RunStatusExpire      = 24 * time.Hour   //异步流程执行进度的保存时长
+	WaitScanInterval     = 10 * time.Second //等待信号超时的扫描间隔
+	LeaseExpire          = 10 * time.Minute //流程执行期间持有的许可、锁的默认有效期
+	IdempotentExpire     = 24 * time.Hour   //幂等请求执行结果的默认保存时长
+	IdempotentWait       = 30 * time.Second //等待执行中的相同幂等请求的默认时长
+	CacheExpire          = 5 * time.Minute  //组件执行结果的默认缓存时长
+	CacheLRUSize         = 10000            //进程内缓存的最大条目数量
+	CachePurgeChannel    = "ps_cache_purge" //缓存清理通知的redis频道
+	StoreChangeChannel   = "ps_store_sync"  //规则、脚本变更通知的redis频道
+	StoreCacheExpire     = time.Minute      //进程内规则、脚本缓存的最长有效期，防止变更通知丢失
+	RuleHistoryMinCount  = 2                //单个规则可设置的最小历史版本数量
+	RuleHistoryMaxCount  = 100              //单个规则可设置的最大历史版本数量
+	DiffMaxLines         = 2000             //脚本逐行对比支持的最大行数
+	BundleFormat         = "ps.bundle.v1"   //规则导出包的格式版本
+	AuditBeforeKey       = "audit_before"   //审计日志中变更前的数据
+	AuditAfterKey        = "audit_after"    //审计日志中变更后的数据
 )
 
 const (
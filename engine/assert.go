@@ -0,0 +1,173 @@
+package engine
+
+import (
+	"fmt"
+	json "github.com/json-iterator/go"
+	"ps-go/tools"
+	"reflect"
+	"strings"
+)
+
+const (
+	AssertOpEq        = "eq"        //等于
+	AssertOpNe        = "ne"        //不等于
+	AssertOpGt        = "gt"        //大于
+	AssertOpGte       = "gte"       //大于等于
+	AssertOpLt        = "lt"        //小于
+	AssertOpLte       = "lte"       //小于等于
+	AssertOpContains  = "contains"  //字符串包含，或者数组包含指定元素
+	AssertOpExists    = "exists"    //值存在
+	AssertOpNotExists = "notExists" //值不存在
+
+	assertResponse = "response" //流程的返回数据
+	assertStore    = "store"    //运行存储器中的数据
+	assertStatus   = "status"   //流程的执行状态
+)
+
+// Assertion 测试用例的断言
+type Assertion struct {
+	Path  string `json:"path"`  //取值路径 [response.xxx|store.xxx|status]
+	Op    string `json:"op"`    //比较方式 [eq|ne|gt|gte|lt|lte|contains|exists|notExists]，默认为eq
+	Value any    `json:"value"` //期望值
+}
+
+// AssertResult 流程执行结果，用于断言取值
+type AssertResult struct {
+	Response any
+	Status   string
+	Store    RunStore
+}
+
+// Validate 校验断言配置
+func (a *Assertion) Validate() error {
+	root := strings.SplitN(a.Path, ".", 2)[0]
+	if root != assertResponse && root != assertStore && root != assertStatus {
+		return fmt.Errorf("断言路径%v必须以response、store或者status开头", a.Path)
+	}
+	if root == assertStore && a.Path == assertStore {
+		return fmt.Errorf("断言路径%v需要指定存储的数据名", a.Path)
+	}
+
+	switch a.Op {
+	case "", AssertOpEq, AssertOpNe, AssertOpContains, AssertOpExists, AssertOpNotExists:
+	case AssertOpGt, AssertOpGte, AssertOpLt, AssertOpLte:
+		if _, ok := assertNumber(a.Value); !ok {
+			return fmt.Errorf("断言%v %v的期望值必须为数字", a.Path, a.Op)
+		}
+	default:
+		return fmt.Errorf("不支持的断言比较方式：%v", a.Op)
+	}
+	return nil
+}
+
+// Check 对执行结果进行断言，不通过时返回原因
+func (a *Assertion) Check(res *AssertResult) error {
+	actual, exist := a.actual(res)
+
+	var ok bool
+	switch a.Op {
+	case AssertOpExists:
+		ok = exist
+	case AssertOpNotExists:
+		ok = !exist
+	case AssertOpNe:
+		ok = !reflect.DeepEqual(assertNormalize(actual), assertNormalize(a.Value))
+	case AssertOpContains:
+		ok = assertContains(actual, a.Value)
+	case AssertOpGt, AssertOpGte, AssertOpLt, AssertOpLte:
+		ok = assertCompare(a.Op, actual, a.Value)
+	default:
+		ok = reflect.DeepEqual(assertNormalize(actual), assertNormalize(a.Value))
+	}
+
+	if ok {
+		return nil
+	}
+	op := a.Op
+	if op == "" {
+		op = AssertOpEq
+	}
+	str, _ := json.MarshalToString(actual)
+	expect, _ := json.MarshalToString(a.Value)
+	return fmt.Errorf("断言失败：%v %v %v，实际值为%v", a.Path, op, expect, str)
+}
+
+// actual 获取断言路径对应的实际值
+func (a *Assertion) actual(res *AssertResult) (any, bool) {
+	keys := strings.SplitN(a.Path, ".", 2)
+	var value any
+
+	switch keys[0] {
+	case assertStatus:
+		return res.Status, res.Status != ""
+	case assertResponse:
+		value = assertNormalize(res.Response)
+		if len(keys) == 2 {
+			data, _ := value.(map[string]any)
+			value = tools.GetMapData(keys[1], data)
+		}
+	case assertStore:
+		if res.Store != nil {
+			value = assertNormalize(res.Store.GetData(keys[1]))
+		}
+	}
+	return value, value != nil
+}
+
+// assertNormalize 统一数据格式，数字统一转换为float64，便于与json中的期望值进行比较
+func assertNormalize(data any) any {
+	if data == nil {
+		return nil
+	}
+	str, err := json.MarshalToString(data)
+	if err != nil {
+		return data
+	}
+	var value any
+	if json.UnmarshalFromString(str, &value) != nil {
+		return data
+	}
+	return value
+}
+
+func assertNumber(data any) (float64, bool) {
+	value, ok := assertNormalize(data).(float64)
+	return value, ok
+}
+
+func assertCompare(op string, actual, expect any) bool {
+	a, ok := assertNumber(actual)
+	if !ok {
+		return false
+	}
+	e, ok := assertNumber(expect)
+	if !ok {
+		return false
+	}
+
+	switch op {
+	case AssertOpGt:
+		return a > e
+	case AssertOpGte:
+		return a >= e
+	case AssertOpLt:
+		return a < e
+	default:
+		return a <= e
+	}
+}
+
+func assertContains(actual, expect any) bool {
+	switch value := assertNormalize(actual).(type) {
+	case string:
+		return strings.Contains(value, fmt.Sprint(expect))
+	case []any:
+		expect = assertNormalize(expect)
+		for _, item := range value {
+			if reflect.DeepEqual(item, expect) {
+				return true
+			}
+		}
+	}
+	return false
+}
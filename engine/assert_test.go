@@ -0,0 +1,117 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAssertionValidate(t *testing.T) {
+	cases := []struct {
+		assertion Assertion
+		err       string
+	}{
+		{Assertion{Path: "response.code"}, ""},
+		{Assertion{Path: "response"}, ""},
+		{Assertion{Path: "status", Op: AssertOpEq, Value: "success"}, ""},
+		{Assertion{Path: "store.user.id", Op: AssertOpExists}, ""},
+		{Assertion{Path: "response.total", Op: AssertOpGte, Value: 1}, ""},
+		{Assertion{Path: "body.code"}, "必须以response、store或者status开头"},
+		{Assertion{Path: "responses.code"}, "必须以response、store或者status开头"},
+		{Assertion{Path: "store"}, "需要指定存储的数据名"},
+		{Assertion{Path: "response.total", Op: AssertOpGt, Value: "a"}, "必须为数字"},
+		{Assertion{Path: "response.code", Op: "like"}, "不支持的断言比较方式"},
+	}
+
+	for _, c := range cases {
+		err := c.assertion.Validate()
+		if c.err == "" && err != nil {
+			t.Errorf("Validate(%+v) error: %v", c.assertion, err)
+		}
+		if c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)) {
+			t.Errorf("Validate(%+v) = %v, want %v", c.assertion, err, c.err)
+		}
+	}
+}
+
+func TestAssertionCheck(t *testing.T) {
+	res := &AssertResult{
+		Status: "success",
+		Response: map[string]any{
+			"code":  0,
+			"msg":   "hello world",
+			"total": int64(12),
+			"ids":   []int{1, 2, 3},
+			"user":  map[string]any{"name": "lime", "tags": []string{"a", "b"}},
+			"empty": nil,
+		},
+		Store: &runStore{data: map[string]any{
+			"login": map[string]any{"token": "abc", "expire": 3600},
+		}},
+	}
+
+	cases := []struct {
+		assertion Assertion
+		ok        bool
+	}{
+		// eq，数字类型不同时统一比较
+		{Assertion{Path: "response.code", Value: 0}, true},
+		{Assertion{Path: "response.code", Value: float64(0)}, true},
+		{Assertion{Path: "response.code", Value: "0"}, false},
+		{Assertion{Path: "response.user.name", Op: AssertOpEq, Value: "lime"}, true},
+		{Assertion{Path: "response.ids", Value: []any{1, 2, 3}}, true},
+		{Assertion{Path: "response.user", Value: map[string]any{"name": "lime", "tags": []any{"a", "b"}}}, true},
+		{Assertion{Path: "response.ids", Value: []any{1, 2}}, false},
+		{Assertion{Path: "status", Value: "success"}, true},
+
+		// ne
+		{Assertion{Path: "response.code", Op: AssertOpNe, Value: 1}, true},
+		{Assertion{Path: "response.code", Op: AssertOpNe, Value: 0}, false},
+
+		// 数字比较
+		{Assertion{Path: "response.total", Op: AssertOpGt, Value: 10}, true},
+		{Assertion{Path: "response.total", Op: AssertOpGt, Value: 12}, false},
+		{Assertion{Path: "response.total", Op: AssertOpGte, Value: 12}, true},
+		{Assertion{Path: "response.total", Op: AssertOpLt, Value: 12.5}, true},
+		{Assertion{Path: "response.total", Op: AssertOpLte, Value: 11}, false},
+		{Assertion{Path: "response.msg", Op: AssertOpGt, Value: 1}, false},
+		{Assertion{Path: "response.none", Op: AssertOpLt, Value: 1}, false},
+
+		// contains
+		{Assertion{Path: "response.msg", Op: AssertOpContains, Value: "world"}, true},
+		{Assertion{Path: "response.msg", Op: AssertOpContains, Value: "lime"}, false},
+		{Assertion{Path: "response.ids", Op: AssertOpContains, Value: 2}, true},
+		{Assertion{Path: "response.ids", Op: AssertOpContains, Value: 4}, false},
+		{Assertion{Path: "response.user.tags", Op: AssertOpContains, Value: "b"}, true},
+		{Assertion{Path: "response.code", Op: AssertOpContains, Value: 0}, false},
+
+		// exists，值为null时视为不存在
+		{Assertion{Path: "response.user.name", Op: AssertOpExists}, true},
+		{Assertion{Path: "response.none", Op: AssertOpExists}, false},
+		{Assertion{Path: "response.empty", Op: AssertOpExists}, false},
+		{Assertion{Path: "response.none", Op: AssertOpNotExists}, true},
+		{Assertion{Path: "response.code", Op: AssertOpNotExists}, false},
+
+		// store
+		{Assertion{Path: "store.login.token", Value: "abc"}, true},
+		{Assertion{Path: "store.login.expire", Op: AssertOpGte, Value: 3600}, true},
+		{Assertion{Path: "store.logout", Op: AssertOpNotExists}, true},
+	}
+
+	for _, c := range cases {
+		err := c.assertion.Check(res)
+		if (err == nil) != c.ok {
+			t.Errorf("Check(%+v) = %v, want ok %v", c.assertion, err, c.ok)
+		}
+	}
+
+	// 断言失败时返回期望值与实际值
+	err := (&Assertion{Path: "response.code", Value: 1}).Check(res)
+	if err == nil || err.Error() != "断言失败：response.code eq 1，实际值为0" {
+		t.Errorf("error message = %v", err)
+	}
+
+	// 没有存储器时store取值为空
+	if err = (&Assertion{Path: "store.login", Op: AssertOpNotExists}).Check(&AssertResult{}); err != nil {
+		t.Errorf("nil store: %v", err)
+	}
+}
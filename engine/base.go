@@ -8,9 +8,13 @@ const (
 	Bool   = "bool"
 	Map    = "object"
 
-	ComponentTypeApi    = "api"
-	ComponentTypeScript = "script"
-	LogDatetimeFormat   = "2006-01-02 15:04:05.000"
+	ComponentTypeApi     = "api"
+	ComponentTypeScript  = "script"
+	ComponentTypeSwitch  = "switch"
+	ComponentTypeForeach = "foreach"
+	ComponentTypeRule    = "rule"
+	ComponentTypeWait    = "wait"
+	LogDatetimeFormat    = "2006-01-02 15:04:05.000"
 )
 
 const (
@@ -19,4 +23,10 @@ const (
 	RunBreak         = "错误中断" //ErrorBreak
 	RunActiveBreak   = "主动中断" //ActiveBreak
 	RunSuccess       = "成功执行" //Success
+	RunWait          = "等待信号" //Wait
+)
+
+const (
+	CompensateSuccess = "补偿成功" //CompensateSuccess
+	CompensateFail    = "补偿失败" //CompensateFail
 )
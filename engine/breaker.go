@@ -0,0 +1,67 @@
+package engine
+
+import (
+	"fmt"
+	"github.com/limeschool/gin"
+	"ps-go/tools"
+	"ps-go/tools/breaker"
+)
+
+// Breaker 组件熔断配置，熔断状态按照上游服务的host统计，多个组件请求同一个上游服务时共享熔断状态
+type Breaker struct {
+	breaker.Config
+	Fallback any `json:"fallback"` //熔断时组件的输出数据，未设置时返回熔断错误
+}
+
+// doRequest 发送请求，配置了熔断时先判断上游服务是否已经熔断，请求结束之后上报请求结果
+func (r *runtime) doRequest(request *tools.HttpRequest, conf *Breaker) error {
+	host := breaker.Host(request.Url)
+	if conf == nil || host == "" {
+		return request.Do()
+	}
+
+	allowed, probe := breaker.Allow(r.ctx, host, conf.Config)
+	if !allowed {
+		return NewBreakerOpenError(fmt.Sprintf("上游服务%v已熔断", host))
+	}
+
+	err := request.Do()
+
+	// 流程中断导致的请求取消不计入上游服务的失败，探测请求需要归还探测名额，否则熔断会一直处于半开状态
+	if r.runCtx.Err() != nil {
+		if probe {
+			breaker.Release(r.ctx, host)
+		}
+		return err
+	}
+
+	// 探测请求出现非上游服务的错误时，无法判断上游服务是否已经恢复，同样归还探测名额
+	failure := isUpstreamFailure(err, request.ResponseCode())
+	if probe && err != nil && !failure {
+		breaker.Release(r.ctx, host)
+		return err
+	}
+	breaker.Report(r.ctx, host, conf.Config, !failure)
+	return err
+}
+
+// fallbackData 上游服务熔断时获取降级数据
+func (r *runtime) fallbackData(err error, conf *Breaker) (any, bool) {
+	if e, ok := err.(*Error); !ok || e.Code != BreakerOpenErrorCode || conf == nil || conf.Fallback == nil {
+		return nil, false
+	}
+	r.componentLog.SetFallback(true)
+	return tools.CopyData(conf.Fallback), true
+}
+
+// isUpstreamFailure 网络错误以及5xx状态码视为上游服务异常，数据解析等错误不计入
+func isUpstreamFailure(err error, code int) bool {
+	if code >= 500 {
+		return true
+	}
+	if err == nil {
+		return false
+	}
+	_, ok := err.(*gin.CustomError)
+	return !ok
+}
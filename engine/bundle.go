@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"github.com/limeschool/gin"
+	"regexp"
+	"sort"
+)
+
+var (
+	// 脚本中通过rsa模块使用的密钥，只能识别字面量形式的密钥名
+	rsaSecretReg = regexp.MustCompile(`rsa\.\w+\(\s*["'\x60]([^"'\x60]+)["'\x60]`)
+	// 脚本中通过request模块的tls配置使用的密钥
+	tlsBlockReg  = regexp.MustCompile(`tls\s*:\s*\{([^}]*)}`)
+	tlsSecretReg = regexp.MustCompile(`\b(?:ca|key)\s*:\s*["'\x60]([^"'\x60]+)["'\x60]`)
+)
+
+// RuleReferences 获取规则中引用的脚本名以及密钥名，包括foreach以及补偿组件中的引用，结果去重并排序
+func RuleReferences(rule *Rule) ([]string, []string) {
+	scripts, secrets := map[string]bool{}, map[string]bool{}
+
+	var walk func(com *Component)
+	walk = func(com *Component) {
+		if com == nil {
+			return
+		}
+		switch com.Type {
+		case ComponentTypeApi:
+			if com.Tls != nil {
+				secrets[com.Tls.Ca] = true
+				secrets[com.Tls.Key] = true
+			}
+		case ComponentTypeSwitch, ComponentTypeForeach, ComponentTypeRule, ComponentTypeWait:
+		default:
+			scripts[com.Url] = true
+		}
+		walk(com.Component)
+		walk(com.Compensate)
+	}
+
+	for _, step := range rule.Components {
+		for index := range step {
+			walk(&step[index])
+		}
+	}
+	for _, item := range rule.Notify {
+		secrets[item.Secret] = true
+	}
+	return sortedKeys(scripts), sortedKeys(secrets)
+}
+
+// ScriptSecrets 获取脚本中通过rsa模块以及request模块的tls配置引用的密钥名
+func ScriptSecrets(src string) []string {
+	secrets := map[string]bool{}
+	for _, match := range rsaSecretReg.FindAllStringSubmatch(src, -1) {
+		secrets[match[1]] = true
+	}
+	for _, block := range tlsBlockReg.FindAllStringSubmatch(src, -1) {
+		for _, match := range tlsSecretReg.FindAllStringSubmatch(block[1], -1) {
+			secrets[match[1]] = true
+		}
+	}
+	return sortedKeys(secrets)
+}
+
+// LintRuleWithScripts 对规则进行静态校验，引用的脚本优先从scripts中加载，用于导入规则前校验尚未创建的脚本
+func (e engine) LintRuleWithScripts(ctx *gin.Context, data string, scripts map[string]string) []Diagnostic {
+	return lintRule(ctx, &overlayStore{Store: e.manageStore(), scripts: scripts}, data)
+}
+
+// overlayStore 优先从给定的脚本中加载脚本，其他数据使用原存储器
+type overlayStore struct {
+	Store
+	scripts map[string]string
+}
+
+func (s *overlayStore) LoadScript(ctx *gin.Context, name string) (string, string, error) {
+	if src, ok := s.scripts[name]; ok {
+		return src, "", nil
+	}
+	return s.Store.LoadScript(ctx, name)
+}
+
+func sortedKeys(m map[string]bool) []string {
+	list := make([]string, 0, len(m))
+	for key := range m {
+		if key != "" {
+			list = append(list, key)
+		}
+	}
+	sort.Strings(list)
+	return list
+}
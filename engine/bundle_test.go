@@ -0,0 +1,64 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRuleReferences(t *testing.T) {
+	comp := Component{Type: ComponentTypeScript, Url: "compensate.js"}
+	rule := &Rule{
+		Components: Components{
+			{
+				{Type: ComponentTypeScript, Url: "b.js"},
+				{Url: "a.js"}, // 未设置类型时为脚本组件
+				{Type: ComponentTypeApi, Url: "http://127.0.0.1", Tls: &tls{Ca: "ca", Key: "key"}},
+				{Type: ComponentTypeApi, Url: "http://127.0.0.1"},
+			},
+			{
+				{Type: ComponentTypeForeach, Component: &Component{Type: ComponentTypeScript, Url: "item.js"}},
+				{Type: ComponentTypeRule, Url: "/sub"},
+				{Type: ComponentTypeSwitch},
+				{Type: ComponentTypeWait},
+				{Type: ComponentTypeScript, Url: "b.js", Compensate: &comp},
+			},
+		},
+		Notify: []Notify{{Secret: "notify"}, {}},
+	}
+
+	scripts, secrets := RuleReferences(rule)
+	if want := []string{"a.js", "b.js", "compensate.js", "item.js"}; !reflect.DeepEqual(scripts, want) {
+		t.Errorf("scripts = %v, want %v", scripts, want)
+	}
+	if want := []string{"ca", "key", "notify"}; !reflect.DeepEqual(secrets, want) {
+		t.Errorf("secrets = %v, want %v", secrets, want)
+	}
+}
+
+func TestScriptSecrets(t *testing.T) {
+	src := `
+	var sign = rsa.sign("sign_key", data)
+	var plain = rsa.decrypt( 'decrypt_key' , data)
+	var name = "dynamic"; rsa.sign(name, data)
+	request({url: "https://127.0.0.1", tls: {ca: "ca_key", key: ` + "`client_key`" + `}})
+	var other = {ca: "not_tls"}
+	`
+	if got, want := ScriptSecrets(src), []string{"ca_key", "client_key", "decrypt_key", "sign_key"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("ScriptSecrets = %v, want %v", got, want)
+	}
+}
+
+func TestOverlayStore(t *testing.T) {
+	s := &overlayStore{Store: &lintStore{scripts: []string{"old.js"}}, scripts: map[string]string{"new.js": "var a = 1"}}
+
+	// 导入包中的脚本优先，其他脚本从原存储器中加载
+	if src, _, err := s.LoadScript(nil, "new.js"); err != nil || src != "var a = 1" {
+		t.Fatalf("new.js = %v, %v", src, err)
+	}
+	if _, _, err := s.LoadScript(nil, "old.js"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.LoadScript(nil, "missing.js"); err == nil {
+		t.Fatal("expected missing script error")
+	}
+}
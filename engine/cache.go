@@ -0,0 +1,334 @@
+package engine
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	json "github.com/json-iterator/go"
+	"github.com/limeschool/gin"
+	"go.uber.org/zap"
+	"ps-go/consts"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	CacheBackendLRU    = "lru"    //进程内缓存，只在当前实例生效
+	CacheBackendRedis  = "redis"  //redis缓存，多实例共享
+	CacheBackendTiered = "tiered" //两级缓存，优先读取进程内缓存，未命中时读取redis并回写
+
+	cacheKeyPrefix = "cache|"
+)
+
+// Cache 组件执行结果的缓存
+type Cache interface {
+	Get(ctx *gin.Context, key string) (any, bool)
+	Set(ctx *gin.Context, key string, value any, ttl time.Duration)
+	Keys(ctx *gin.Context, prefix string) ([]CacheEntry, error)
+	Purge(ctx *gin.Context, prefix string) (int, error)
+}
+
+// CacheEntry 缓存条目信息
+type CacheEntry struct {
+	Key     string `json:"key"`
+	Backend string `json:"backend"`
+	TTL     int64  `json:"ttl"` //剩余的有效时长/s
+}
+
+// ComponentCache 组件的缓存配置
+type ComponentCache struct {
+	TTL     int    `json:"ttl"`     //缓存时长/s，默认为300
+	Key     string `json:"key"`     //缓存key模板，如 user_{request.body.userId}，默认使用组件配置的md5
+	Backend string `json:"backend"` //缓存方式 [lru|redis|tiered]，默认为redis
+}
+
+// Expire 获取缓存时长
+func (c *ComponentCache) Expire() time.Duration {
+	if c == nil || c.TTL <= 0 {
+		return consts.CacheExpire
+	}
+	return time.Duration(c.TTL) * time.Second
+}
+
+var (
+	lruStore   = newLRUCache(consts.CacheLRUSize)
+	redisStore = &redisCache{}
+	caches     = map[string]Cache{
+		CacheBackendLRU:    lruStore,
+		CacheBackendRedis:  redisStore,
+		CacheBackendTiered: &tieredCache{l1: lruStore, l2: redisStore},
+	}
+)
+
+// GetCache 获取指定的缓存方式，默认为redis
+func GetCache(backend string) Cache {
+	if c, ok := caches[backend]; ok {
+		return c
+	}
+	return redisStore
+}
+
+// CachePrefix 获取流程中组件的缓存key前缀，用于查询以及清理缓存，component为空时为整个流程的前缀
+func CachePrefix(ruleKey, component string) string {
+	if component == "" {
+		return fmt.Sprintf("%v%v|", cacheKeyPrefix, ruleKey)
+	}
+	return fmt.Sprintf("%v%v|%v|", cacheKeyPrefix, ruleKey, component)
+}
+
+// ListCache 查询指定前缀的全部缓存条目
+func ListCache(ctx *gin.Context, prefix string) ([]CacheEntry, error) {
+	list, err := lruStore.Keys(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := redisStore.Keys(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return append(list, entries...), nil
+}
+
+// PurgeCache 清理指定前缀的全部缓存，并通知其他实例清理进程内缓存
+func PurgeCache(ctx *gin.Context, prefix string) (int, error) {
+	count, _ := lruStore.Purge(ctx, prefix)
+	n, err := redisStore.Purge(ctx, prefix)
+	if err != nil {
+		return count, err
+	}
+	if client := ctx.Redis(consts.ProcessScheduleCache); client != nil {
+		client.Publish(context.TODO(), consts.CachePurgeChannel, prefix)
+	}
+	return count + n, nil
+}
+
+// encodeCache 缓存数据统一序列化存储，读取时反序列化，防止多个流程共用同一份数据
+func encodeCache(value any) string {
+	str, _ := json.MarshalToString(cacheData{Data: value})
+	return str
+}
+
+func decodeCache(str string) (any, bool) {
+	data := cacheData{}
+	if str == "" || json.UnmarshalFromString(str, &data) != nil {
+		return nil, false
+	}
+	return data.Data, true
+}
+
+type redisCache struct {
+}
+
+func (c *redisCache) Get(ctx *gin.Context, key string) (any, bool) {
+	client := ctx.Redis(consts.ProcessScheduleCache)
+	if client == nil {
+		return nil, false
+	}
+	str, err := client.Get(context.TODO(), key).Result()
+	if err != nil {
+		return nil, false
+	}
+	return decodeCache(str)
+}
+
+func (c *redisCache) Set(ctx *gin.Context, key string, value any, ttl time.Duration) {
+	if client := ctx.Redis(consts.ProcessScheduleCache); client != nil {
+		client.Set(context.TODO(), key, encodeCache(value), ttl)
+	}
+}
+
+func (c *redisCache) Keys(ctx *gin.Context, prefix string) ([]CacheEntry, error) {
+	list := make([]CacheEntry, 0)
+	client := ctx.Redis(consts.ProcessScheduleCache)
+	if client == nil {
+		return list, nil
+	}
+
+	iter := client.Scan(context.TODO(), 0, escapePattern(prefix)+"*", 100).Iterator()
+	for iter.Next(context.TODO()) {
+		ttl, _ := client.TTL(context.TODO(), iter.Val()).Result()
+		list = append(list, CacheEntry{
+			Key:     iter.Val(),
+			Backend: CacheBackendRedis,
+			TTL:     int64(ttl.Seconds()),
+		})
+	}
+	return list, iter.Err()
+}
+
+func (c *redisCache) Purge(ctx *gin.Context, prefix string) (int, error) {
+	list, err := c.Keys(ctx, prefix)
+	if err != nil || len(list) == 0 {
+		return 0, err
+	}
+
+	keys := make([]string, 0, len(list))
+	for _, item := range list {
+		keys = append(keys, item.Key)
+	}
+	n, err := ctx.Redis(consts.ProcessScheduleCache).Del(context.TODO(), keys...).Result()
+	return int(n), err
+}
+
+// escapePattern 转义redis scan匹配中的特殊字符
+func escapePattern(str string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+	return replacer.Replace(str)
+}
+
+type lruEntry struct {
+	key      string
+	value    string
+	expireAt time.Time
+}
+
+// lruCache 进程内的lru缓存，超过容量之后淘汰最久未使用的条目
+type lruCache struct {
+	lock  sync.Mutex
+	size  int
+	items map[string]*list.Element
+	list  *list.List
+}
+
+func newLRUCache(size int) *lruCache {
+	return &lruCache{
+		size:  size,
+		items: map[string]*list.Element{},
+		list:  list.New(),
+	}
+}
+
+func (c *lruCache) Get(ctx *gin.Context, key string) (any, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*lruEntry)
+	if time.Now().After(entry.expireAt) {
+		c.remove(elem)
+		return nil, false
+	}
+	c.list.MoveToFront(elem)
+	return decodeCache(entry.value)
+}
+
+func (c *lruCache) Set(ctx *gin.Context, key string, value any, ttl time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry := &lruEntry{key: key, value: encodeCache(value), expireAt: time.Now().Add(ttl)}
+	if elem, ok := c.items[key]; ok {
+		elem.Value = entry
+		c.list.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.list.PushFront(entry)
+	for c.list.Len() > c.size {
+		c.remove(c.list.Back())
+	}
+}
+
+func (c *lruCache) Keys(ctx *gin.Context, prefix string) ([]CacheEntry, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := time.Now()
+	list := make([]CacheEntry, 0)
+	for key, elem := range c.items {
+		entry := elem.Value.(*lruEntry)
+		if !strings.HasPrefix(key, prefix) || now.After(entry.expireAt) {
+			continue
+		}
+		list = append(list, CacheEntry{
+			Key:     key,
+			Backend: CacheBackendLRU,
+			TTL:     int64(entry.expireAt.Sub(now).Seconds()),
+		})
+	}
+	return list, nil
+}
+
+func (c *lruCache) Purge(ctx *gin.Context, prefix string) (int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	count := 0
+	for key, elem := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			c.remove(elem)
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (c *lruCache) remove(elem *list.Element) {
+	c.list.Remove(elem)
+	delete(c.items, elem.Value.(*lruEntry).key)
+}
+
+// listenPurge 监听其他实例发出的缓存清理通知，调度引擎初始化时启动，防止首次使用进程内缓存之前的通知丢失
+func (c *lruCache) listenPurge(ctx *gin.Context) {
+	client := ctx.Redis(consts.ProcessScheduleCache)
+	if client == nil {
+		return
+	}
+
+	sub := client.Subscribe(context.Background(), consts.CachePurgeChannel)
+	for msg := range sub.Channel() {
+		if _, err := c.Purge(ctx, msg.Payload); err != nil {
+			ctx.Log.Error("进程内缓存清理失败", zap.Any("prefix", msg.Payload), zap.Any("err", err))
+		}
+	}
+}
+
+// tieredCache 两级缓存
+type tieredCache struct {
+	l1 Cache
+	l2 Cache
+}
+
+func (c *tieredCache) Get(ctx *gin.Context, key string) (any, bool) {
+	if value, ok := c.l1.Get(ctx, key); ok {
+		return value, true
+	}
+
+	value, ok := c.l2.Get(ctx, key)
+	if !ok {
+		return nil, false
+	}
+
+	// 回写进程内缓存，有效期不超过redis中的剩余时长
+	if client := ctx.Redis(consts.ProcessScheduleCache); client != nil {
+		if ttl, err := client.TTL(context.TODO(), key).Result(); err == nil && ttl > 0 {
+			c.l1.Set(ctx, key, value, ttl)
+		}
+	}
+	return value, true
+}
+
+func (c *tieredCache) Set(ctx *gin.Context, key string, value any, ttl time.Duration) {
+	c.l2.Set(ctx, key, value, ttl)
+	c.l1.Set(ctx, key, value, ttl)
+}
+
+func (c *tieredCache) Keys(ctx *gin.Context, prefix string) ([]CacheEntry, error) {
+	list, err := c.l1.Keys(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := c.l2.Keys(ctx, prefix)
+	return append(list, entries...), err
+}
+
+func (c *tieredCache) Purge(ctx *gin.Context, prefix string) (int, error) {
+	count, _ := c.l1.Purge(ctx, prefix)
+	n, err := c.l2.Purge(ctx, prefix)
+	return count + n, err
+}
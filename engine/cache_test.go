@@ -0,0 +1,212 @@
+package engine
+
+import (
+	"github.com/limeschool/gin"
+	"ps-go/consts"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newCacheRuntime(com Component, data map[string]any) *runCache {
+	return &runCache{&runtime{
+		stack:     []string{"POST:/test"},
+		component: com,
+		runStore:  &runStore{data: data},
+	}}
+}
+
+func TestCacheKeyTemplate(t *testing.T) {
+	com := Component{Name: "user", Cache: &ComponentCache{Key: "user_{request.body.id}_{request.body.type}"}}
+	r := newCacheRuntime(com, map[string]any{
+		"request": map[string]any{"body": map[string]any{"id": 10, "type": "vip"}},
+	})
+	if key := r.cacheKey(); key != "cache|POST:/test|user|user_{10}_{vip}" {
+		t.Fatalf("key = %v", key)
+	}
+
+	// 取值不存在时同样替换，不同的请求不会共用缓存
+	r = newCacheRuntime(com, map[string]any{"request": map[string]any{"body": map[string]any{"id": 10}}})
+	if key := r.cacheKey(); key != "cache|POST:/test|user|user_{10}_{%3Cnil%3E}" {
+		t.Fatalf("missing value key = %v", key)
+	}
+}
+
+func TestCacheKeyEscape(t *testing.T) {
+	com := Component{Name: "user", Cache: &ComponentCache{Key: "{request.a}{request.b}"}}
+	key := func(a, b string) string {
+		return newCacheRuntime(com, map[string]any{"request": map[string]any{"a": a, "b": b}}).cacheKey()
+	}
+
+	// 值中包含大括号、分隔符时不同的请求不能得到相同的key
+	if k1, k2 := key("x}{y", "z"), key("x", "y}{z"); k1 == k2 {
+		t.Fatalf("keys collide: %v", k1)
+	}
+	if k := key("a|b", "c*"); k != "cache|POST:/test|user|{a%7Cb}{c%2A}" {
+		t.Fatalf("key = %v", k)
+	}
+
+	cases := map[string]string{
+		"abc_XYZ-1.2": "abc_XYZ-1.2",
+		"a b":         "a%20b",
+		"中":           "%E4%B8%AD",
+		"{}|%":        "%7B%7D%7C%25",
+	}
+	for in, want := range cases {
+		if got := escapeCacheValue(in); got != want {
+			t.Errorf("escapeCacheValue(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCacheKeyDefault(t *testing.T) {
+	com := Component{Name: "user", Url: "http://127.0.0.1/user", Input: map[string]any{"id": "{body.id}"}}
+	key := newCacheRuntime(com, nil).cacheKey()
+	if !strings.HasPrefix(key, "cache|POST:/test|user|") || len(key) != len("cache|POST:/test|user|")+32 {
+		t.Fatalf("key = %v", key)
+	}
+
+	// 未配置key模板时使用组件配置的md5，配置不变时key不变
+	if again := newCacheRuntime(com, nil).cacheKey(); again != key {
+		t.Fatalf("key changed: %v, %v", key, again)
+	}
+	com.Url = "http://127.0.0.1/user2"
+	if other := newCacheRuntime(com, nil).cacheKey(); other == key {
+		t.Fatal("different component got the same key")
+	}
+}
+
+func TestLRUCache(t *testing.T) {
+	ctx := &gin.Context{}
+	c := newLRUCache(2)
+
+	c.Set(ctx, "a", map[string]any{"v": 1}, time.Minute)
+	c.Set(ctx, "b", 2, time.Minute)
+	// 读取之后a变为最近使用，超过容量时淘汰b
+	if _, ok := c.Get(ctx, "a"); !ok {
+		t.Fatal("a not found")
+	}
+	c.Set(ctx, "c", 3, time.Minute)
+	if _, ok := c.Get(ctx, "b"); ok {
+		t.Fatal("b not evicted")
+	}
+	if _, ok := c.Get(ctx, "c"); !ok {
+		t.Fatal("c not found")
+	}
+
+	// 读取的数据为反序列化之后的副本，修改不影响缓存
+	value, _ := c.Get(ctx, "a")
+	value.(map[string]any)["v"] = 2
+	if value, _ = c.Get(ctx, "a"); value.(map[string]any)["v"] != float64(1) {
+		t.Fatalf("cached value changed: %v", value)
+	}
+
+	// 重复设置时覆盖原有的值，不增加条目
+	c.Set(ctx, "c", 4, time.Minute)
+	if value, _ := c.Get(ctx, "c"); value != float64(4) || c.list.Len() != 2 {
+		t.Fatalf("value = %v, len = %v", value, c.list.Len())
+	}
+}
+
+func TestLRUCacheExpire(t *testing.T) {
+	ctx := &gin.Context{}
+	c := newLRUCache(10)
+	c.Set(ctx, "a", 1, -time.Second)
+	c.Set(ctx, "b", 1, time.Minute)
+
+	if keys, _ := c.Keys(ctx, ""); len(keys) != 1 || keys[0].Key != "b" || keys[0].Backend != CacheBackendLRU {
+		t.Fatalf("keys = %+v", keys)
+	}
+	if _, ok := c.Get(ctx, "a"); ok {
+		t.Fatal("expired entry returned")
+	}
+	if _, ok := c.items["a"]; ok {
+		t.Fatal("expired entry not removed")
+	}
+}
+
+func TestLRUCachePurge(t *testing.T) {
+	ctx := &gin.Context{}
+	c := newLRUCache(10)
+	for _, key := range []string{
+		CachePrefix("POST:/a", "user") + "1",
+		CachePrefix("POST:/a", "user") + "2",
+		CachePrefix("POST:/a", "user2") + "1",
+		CachePrefix("POST:/b", "user") + "1",
+	} {
+		c.Set(ctx, key, 1, time.Minute)
+	}
+
+	keys := func() []string {
+		list, _ := c.Keys(ctx, "")
+		var keys []string
+		for _, item := range list {
+			keys = append(keys, item.Key)
+		}
+		sort.Strings(keys)
+		return keys
+	}
+
+	// 组件前缀以分隔符结尾，不会清理名称以其开头的其他组件
+	if n, _ := c.Purge(ctx, CachePrefix("POST:/a", "user")); n != 2 {
+		t.Fatalf("purged %v, want 2", n)
+	}
+	if got := keys(); len(got) != 2 || got[0] != "cache|POST:/a|user2|1" || got[1] != "cache|POST:/b|user|1" {
+		t.Fatalf("keys = %v", got)
+	}
+
+	// 流程前缀清理流程下的全部组件
+	if n, _ := c.Purge(ctx, CachePrefix("POST:/a", "")); n != 1 {
+		t.Fatalf("purged %v, want 1", n)
+	}
+	if got := keys(); len(got) != 1 || got[0] != "cache|POST:/b|user|1" {
+		t.Fatalf("keys = %v", got)
+	}
+	if c.list.Len() != len(c.items) {
+		t.Fatalf("list len = %v, items = %v", c.list.Len(), len(c.items))
+	}
+}
+
+func TestTieredCache(t *testing.T) {
+	ctx := &gin.Context{}
+	l1, l2 := newLRUCache(10), newLRUCache(10)
+	c := &tieredCache{l1: l1, l2: l2}
+
+	c.Set(ctx, "a", 1, time.Minute)
+	if _, ok := l1.Get(ctx, "a"); !ok {
+		t.Fatal("l1 not set")
+	}
+	if _, ok := l2.Get(ctx, "a"); !ok {
+		t.Fatal("l2 not set")
+	}
+
+	// 进程内缓存未命中时读取二级缓存
+	l1.Purge(ctx, "a")
+	if value, ok := c.Get(ctx, "a"); !ok || value != float64(1) {
+		t.Fatalf("Get = %v, %v", value, ok)
+	}
+
+	if keys, _ := c.Keys(ctx, "a"); len(keys) != 1 {
+		t.Fatalf("keys = %+v", keys)
+	}
+	c.Set(ctx, "a", 1, time.Minute)
+	if n, _ := c.Purge(ctx, "a"); n != 2 {
+		t.Fatalf("purged %v, want 2", n)
+	}
+}
+
+func TestCacheConfig(t *testing.T) {
+	if (*ComponentCache)(nil).Expire() != consts.CacheExpire || (&ComponentCache{}).Expire() != consts.CacheExpire {
+		t.Fatal("default expire")
+	}
+	if (&ComponentCache{TTL: 10}).Expire() != 10*time.Second {
+		t.Fatal("ttl expire")
+	}
+	if GetCache("") != redisStore || GetCache("unknown") != redisStore || GetCache(CacheBackendLRU) != lruStore {
+		t.Fatal("GetCache backend")
+	}
+	if got := escapePattern(`a*b?[c]\`); got != `a\*b\?\[c\]\\` {
+		t.Fatalf("escapePattern = %v", got)
+	}
+}
@@ -0,0 +1,177 @@
+package engine
+
+import (
+	"fmt"
+	json "github.com/json-iterator/go"
+	"github.com/limeschool/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"hash/crc32"
+	"math/rand"
+	"ps-go/errors"
+	"ps-go/model"
+	"ps-go/tools"
+	"strings"
+)
+
+const (
+	CanaryKeyHeader = "header" //从请求header中取值
+	CanaryKeyQuery  = "query"  //从请求query中取值
+)
+
+// Canary 规则的灰度发布配置
+type Canary struct {
+	Version   string        `json:"version"`   //灰度版本
+	Weight    int           `json:"weight"`    //灰度版本的流量百分比 0-100
+	Matches   []CanaryMatch `json:"matches"`   //满足全部条件的请求直接使用灰度版本，不参与流量分配
+	StickyKey string        `json:"stickyKey"` //流量分配的粘性键 [header.xxx|query.xxx]，相同取值始终命中同一版本，为空或者取值为空时随机分配
+}
+
+// CanaryMatch 灰度条件
+type CanaryMatch struct {
+	Key    string   `json:"key"`    //取值 [header.xxx|query.xxx]
+	Values []string `json:"values"` //取值在列表中时满足条件
+}
+
+// Validate 校验灰度配置
+func (c *Canary) Validate() error {
+	if c.Version == "" {
+		return errors.New("灰度版本不能为空")
+	}
+	if c.Weight < 0 || c.Weight > 100 {
+		return errors.New("灰度流量百分比必须在0-100之间")
+	}
+	if c.StickyKey != "" && !isCanaryKey(c.StickyKey) {
+		return errors.NewF("粘性键%v必须为header.xxx或者query.xxx", c.StickyKey)
+	}
+	for _, item := range c.Matches {
+		if !isCanaryKey(item.Key) {
+			return errors.NewF("灰度条件%v必须为header.xxx或者query.xxx", item.Key)
+		}
+		if len(item.Values) == 0 {
+			return errors.NewF("灰度条件%v的取值不能为空", item.Key)
+		}
+	}
+	return nil
+}
+
+// Hit 判断请求是否使用灰度版本，ruleKey用于不同规则之间独立分配流量
+func (c *Canary) Hit(ctx *gin.Context, ruleKey string) bool {
+	if len(c.Matches) != 0 && c.match(ctx) {
+		return true
+	}
+
+	if c.Weight <= 0 {
+		return false
+	}
+	if c.Weight >= 100 {
+		return true
+	}
+
+	// 按照粘性键的取值分桶，调整流量比例时已经命中灰度的请求保持不变
+	bucket := rand.Intn(100)
+	if value := canaryValue(ctx, c.StickyKey); value != "" {
+		bucket = int(crc32.ChecksumIEEE([]byte(ruleKey+":"+value)) % 100)
+	}
+	return bucket < c.Weight
+}
+
+// match 请求是否满足全部灰度条件
+func (c *Canary) match(ctx *gin.Context) bool {
+	for _, item := range c.Matches {
+		value := canaryValue(ctx, item.Key)
+		if value == "" || !tools.InList(item.Values, value) {
+			return false
+		}
+	}
+	return true
+}
+
+func isCanaryKey(key string) bool {
+	keys := strings.SplitN(key, ".", 2)
+	return len(keys) == 2 && keys[1] != "" && (keys[0] == CanaryKeyHeader || keys[0] == CanaryKeyQuery)
+}
+
+// canaryValue 获取请求中的取值，定时任务等非http请求触发时取值为空
+func canaryValue(ctx *gin.Context, key string) string {
+	if ctx.Request == nil || key == "" {
+		return ""
+	}
+	switch keys := strings.SplitN(key, ".", 2); keys[0] {
+	case CanaryKeyHeader:
+		return ctx.GetHeader(keys[1])
+	case CanaryKeyQuery:
+		return ctx.Query(keys[1])
+	}
+	return ""
+}
+
+// canarySource 支持灰度发布的存储器
+type canarySource interface {
+	LoadCanary(ctx *gin.Context, method, path string) (*Canary, error)
+	LoadRuleByVersion(ctx *gin.Context, version string) (*Rule, error)
+}
+
+// canaryStore 按照灰度配置为每个请求选择规则版本，未命中灰度或者灰度版本加载失败时使用启用中的版本
+type canaryStore struct {
+	Store
+}
+
+// NewCanaryStore 为存储器增加灰度发布，存储器不支持灰度发布时直接使用启用中的版本
+func NewCanaryStore(st Store) *canaryStore {
+	return &canaryStore{Store: st}
+}
+
+// LoadRule 获取请求使用的规则版本
+func (s *canaryStore) LoadRule(ctx *gin.Context, method, path string) (*Rule, error) {
+	src, ok := s.Store.(canarySource)
+	if !ok {
+		return s.Store.LoadRule(ctx, method, path)
+	}
+
+	canary, err := src.LoadCanary(ctx, method, path)
+	if err != nil {
+		ctx.Log.Error("加载灰度配置失败", zap.Any("path", path), zap.Any("err", err))
+		return s.Store.LoadRule(ctx, method, path)
+	}
+	if canary == nil || !canary.Hit(ctx, RuleKey(method, path)) {
+		return s.Store.LoadRule(ctx, method, path)
+	}
+
+	rule, err := src.LoadRuleByVersion(ctx, canary.Version)
+	if err != nil {
+		ctx.Log.Error("加载灰度版本失败", zap.Any("version", canary.Version), zap.Any("err", err))
+		return s.Store.LoadRule(ctx, method, path)
+	}
+	return rule, nil
+}
+
+// LoadCanary 获取规则的灰度配置，不存在时返回nil
+func (s *store) LoadCanary(ctx *gin.Context, method, path string) (*Canary, error) {
+	rc := model.RuleCanary{}
+	if err := rc.OneByNameMethod(ctx, path, method); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	canary := &Canary{Version: rc.Version, Weight: rc.Weight, StickyKey: rc.StickyKey}
+	if rc.Matches != "" {
+		if err := json.UnmarshalFromString(rc.Matches, &canary.Matches); err != nil {
+			return nil, fmt.Errorf("灰度条件格式错误：%v", err.Error())
+		}
+	}
+	return canary, nil
+}
+
+// LoadRuleByVersion 获取指定版本的规则
+func (s *store) LoadRuleByVersion(ctx *gin.Context, version string) (*Rule, error) {
+	rule := model.Rule{}
+	if err := rule.OneByVersion(ctx, version); err != nil {
+		return nil, errors.NewF("不存在规则版本：%v", version)
+	}
+
+	er := Rule{Version: rule.Version}
+	return &er, json.Unmarshal([]byte(rule.Rule), &er)
+}
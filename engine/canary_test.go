@@ -0,0 +1,184 @@
+package engine
+
+import (
+	"fmt"
+	"github.com/limeschool/gin"
+	"go.uber.org/zap"
+	"net/http/httptest"
+	"ps-go/errors"
+	"testing"
+)
+
+func canaryCtx(target string, header map[string]string) *gin.Context {
+	ctx := &gin.Context{Log: zap.NewNop(), Request: httptest.NewRequest("GET", target, nil)}
+	for key, val := range header {
+		ctx.Request.Header.Set(key, val)
+	}
+	return ctx
+}
+
+func TestCanaryValidate(t *testing.T) {
+	cases := []struct {
+		canary Canary
+		ok     bool
+	}{
+		{Canary{Version: "v2", Weight: 10}, true},
+		{Canary{Version: "v2", StickyKey: "header.X-User", Matches: []CanaryMatch{{Key: "query.uid", Values: []string{"1"}}}}, true},
+		{Canary{Weight: 10}, false},
+		{Canary{Version: "v2", Weight: -1}, false},
+		{Canary{Version: "v2", Weight: 101}, false},
+		{Canary{Version: "v2", StickyKey: "body.uid"}, false},
+		{Canary{Version: "v2", StickyKey: "header."}, false},
+		{Canary{Version: "v2", Matches: []CanaryMatch{{Key: "uid", Values: []string{"1"}}}}, false},
+		{Canary{Version: "v2", Matches: []CanaryMatch{{Key: "query.uid"}}}, false},
+	}
+	for index, c := range cases {
+		if err := c.canary.Validate(); (err == nil) != c.ok {
+			t.Errorf("case %v: Validate() = %v, want ok = %v", index, err, c.ok)
+		}
+	}
+}
+
+func TestCanaryMatch(t *testing.T) {
+	canary := Canary{Version: "v2", Matches: []CanaryMatch{
+		{Key: "header.X-Env", Values: []string{"gray", "test"}},
+		{Key: "query.uid", Values: []string{"1", "2"}},
+	}}
+
+	// 满足全部条件时使用灰度版本，不参与流量分配
+	cases := []struct {
+		target string
+		header map[string]string
+		hit    bool
+	}{
+		{"/test?uid=1", map[string]string{"X-Env": "gray"}, true},
+		{"/test?uid=2", map[string]string{"X-Env": "test"}, true},
+		{"/test?uid=3", map[string]string{"X-Env": "gray"}, false},
+		{"/test?uid=1", nil, false},
+		{"/test", map[string]string{"X-Env": "gray"}, false},
+	}
+	for _, c := range cases {
+		if hit := canary.Hit(canaryCtx(c.target, c.header), "GET:test"); hit != c.hit {
+			t.Errorf("Hit(%v, %v) = %v, want %v", c.target, c.header, hit, c.hit)
+		}
+	}
+
+	// 定时任务等非http请求触发时不满足条件
+	if canary.Hit(&gin.Context{}, "GET:test") {
+		t.Fatal("request without http hit canary")
+	}
+}
+
+func TestCanaryWeight(t *testing.T) {
+	ctx := canaryCtx("/test", nil)
+	for i := 0; i < 100; i++ {
+		if (&Canary{Weight: 0}).Hit(ctx, "GET:test") {
+			t.Fatal("weight 0 hit")
+		}
+		if !(&Canary{Weight: 100}).Hit(ctx, "GET:test") {
+			t.Fatal("weight 100 missed")
+		}
+	}
+
+	// 未设置粘性键时随机分配
+	hits := 0
+	for i := 0; i < 2000; i++ {
+		if (&Canary{Weight: 30}).Hit(ctx, "GET:test") {
+			hits++
+		}
+	}
+	if hits < 450 || hits > 750 {
+		t.Fatalf("random hits = %v/2000, want about 30%%", hits)
+	}
+}
+
+func TestCanarySticky(t *testing.T) {
+	hit := func(weight int, ruleKey, uid string) bool {
+		canary := Canary{Weight: weight, StickyKey: "query.uid"}
+		return canary.Hit(canaryCtx("/test?uid="+uid, nil), ruleKey)
+	}
+
+	hits, changed := 0, 0
+	for i := 0; i < 2000; i++ {
+		uid := fmt.Sprint(i)
+		first := hit(30, "GET:a", uid)
+		// 相同取值始终命中同一版本
+		for j := 0; j < 3; j++ {
+			if hit(30, "GET:a", uid) != first {
+				t.Fatalf("uid %v not sticky", uid)
+			}
+		}
+		// 调大流量比例时已经命中灰度的请求保持不变
+		if first && !hit(60, "GET:a", uid) {
+			t.Fatalf("uid %v left canary after weight increased", uid)
+		}
+		if first {
+			hits++
+		}
+		// 不同规则之间独立分配流量
+		if hit(30, "GET:b", uid) != first {
+			changed++
+		}
+	}
+	if hits < 450 || hits > 750 {
+		t.Fatalf("sticky hits = %v/2000, want about 30%%", hits)
+	}
+	if changed == 0 {
+		t.Fatal("different rules share the same buckets")
+	}
+}
+
+// canarySourceStore 支持灰度发布的存储器，启用中的版本为active
+type canarySourceStore struct {
+	Store
+	canary     *Canary
+	canaryErr  error
+	versionErr error
+}
+
+func (s *canarySourceStore) LoadRule(ctx *gin.Context, method, path string) (*Rule, error) {
+	return &Rule{Version: "active"}, nil
+}
+
+func (s *canarySourceStore) LoadCanary(ctx *gin.Context, method, path string) (*Canary, error) {
+	return s.canary, s.canaryErr
+}
+
+func (s *canarySourceStore) LoadRuleByVersion(ctx *gin.Context, version string) (*Rule, error) {
+	if s.versionErr != nil {
+		return nil, s.versionErr
+	}
+	return &Rule{Version: version}, nil
+}
+
+// plainStore 不支持灰度发布的存储器
+type plainStore struct {
+	Store
+}
+
+func (s *plainStore) LoadRule(ctx *gin.Context, method, path string) (*Rule, error) {
+	return &Rule{Version: "active"}, nil
+}
+
+func TestCanaryStore(t *testing.T) {
+	canary := &Canary{Version: "v2", Weight: 100}
+	cases := []struct {
+		name    string
+		store   Store
+		version string
+	}{
+		{"命中灰度", &canarySourceStore{canary: canary}, "v2"},
+		{"未命中灰度", &canarySourceStore{canary: &Canary{Version: "v2"}}, "active"},
+		{"不存在灰度配置", &canarySourceStore{}, "active"},
+		{"灰度配置加载失败", &canarySourceStore{canary: canary, canaryErr: errors.New("db error")}, "active"},
+		{"灰度版本加载失败", &canarySourceStore{canary: canary, versionErr: errors.New("not found")}, "active"},
+		{"不支持灰度的存储器", &plainStore{}, "active"},
+	}
+
+	for _, c := range cases {
+		rule, err := NewCanaryStore(c.store).LoadRule(canaryCtx("/test", nil), "GET", "test")
+		if err != nil || rule.Version != c.version {
+			t.Errorf("%v: LoadRule = %+v, %v, want version %v", c.name, rule, err, c.version)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	json "github.com/json-iterator/go"
+	"go.uber.org/zap"
+	"ps-go/model"
+)
+
+const compensateOutputKey = "output" //补偿组件获取原组件输出的取值名
+
+// CompensateKeys 获取需要补偿的组件标志，按照完成顺序逆序排列
+func (r *runner) CompensateKeys() []string {
+	var keys []string
+	for i := len(r.finished) - 1; i >= 0; i-- {
+		step, action, ok := r.componentIndex(r.finished[i])
+		if ok && r.rule.Components[step][action].Compensate != nil {
+			keys = append(keys, r.finished[i])
+		}
+	}
+	return keys
+}
+
+// componentIndex 通过组件标志获取组件所在的层以及位置
+func (r *runner) componentIndex(key string) (int, int, bool) {
+	for step, list := range r.rule.Components {
+		for action, com := range list {
+			if NodeKey(step, com.Name) == key {
+				return step, action, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// Compensate 按顺序执行组件的补偿动作，补偿失败时停止执行，并将剩余的补偿存储为挂起任务
+func (r *runner) Compensate(keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	for index, key := range keys {
+		step, action, ok := r.componentIndex(key)
+		if !ok || r.rule.Components[step][action].Compensate == nil {
+			continue
+		}
+
+		if _, err := r.NewCompensateRuntime(step, action).runSync(); err != nil {
+			err = fmt.Errorf("组件%v补偿失败：%v", key, err.Error())
+			r.logger.SetCompensateStatus(CompensateFail)
+			r.SuspendCompensate(keys[index:], err)
+			return err
+		}
+	}
+
+	r.logger.SetCompensateStatus(CompensateSuccess)
+	return nil
+}
+
+// NewCompensateRuntime 创建补偿组件的运行时，补偿组件可以通过{output}获取原组件的输出
+func (r *runner) NewCompensateRuntime(step, action int) *runtime {
+	origin := r.rule.Components[step][action]
+	com := origin.Compensate.Copy()
+
+	var output any
+	if origin.OutputName != "" {
+		output = r.runStore.GetData(origin.OutputName)
+	}
+
+	return &runtime{
+		component:    com,
+		ctx:          r.ctx,
+		trx:          r.trx,
+		step:         step,
+		action:       action,
+		maxRetry:     com.MaxRetry(),
+		retryMaxWait: com.RetryMaxWait,
+		response:     r.response,
+		err:          r.err,
+		store:        r.store,
+		stack:        append(append([]string{}, r.parents...), RuleKey(r.method, r.path)),
+		runCtx:       context.Background(), // 补偿在流程中断之后执行，不受流程取消的影响
+		dryRun:       r.dryRun,
+		componentLog: r.logger.NewCompensateLog(),
+		runStore:     newScopeStore(r.runStore, map[string]any{compensateOutputKey: output}),
+	}
+}
+
+// SuspendCompensate 存储补偿失败的挂起任务，恢复时从失败的组件开始继续补偿
+func (r *runner) SuspendCompensate(keys []string, err error) {
+	// 子流程的补偿失败只记录在日志中，试运行不存储补偿任务
+	if len(r.parents) != 0 || r.dryRun != nil {
+		return
+	}
+
+	finish, _ := json.MarshalToString(keys)
+	errNames, _ := json.MarshalToString(keys[:1])
+	suspendLog := model.SuspendLog{
+		Type:            model.SuspendTypeCompensate,
+		Trx:             r.trx,
+		LogID:           r.ctx.TraceID,
+		Method:          r.method,
+		Path:            r.path,
+		Version:         r.version,
+		Step:            r.count,
+		CurStep:         r.curIndex + 1,
+		ErrCode:         DefaultErrorCode,
+		ErrMsg:          err.Error(),
+		Rule:            r.GetRuleToString(),
+		Data:            r.GetDataToString(),
+		ErrComponent:    errNames,
+		FinishComponent: finish,
+	}
+	if err = suspendLog.Save(r.ctx); err != nil {
+		r.ctx.Log.Error("补偿任务存储失败", zap.Any("trx", r.trx), zap.Any("err", err))
+	}
+}
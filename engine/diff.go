@@ -0,0 +1,168 @@
+package engine
+
+import (
+	"fmt"
+	json "github.com/json-iterator/go"
+	"reflect"
+	"sort"
+)
+
+const (
+	DiffAdded    = "added"    //新增
+	DiffRemoved  = "removed"  //删除
+	DiffModified = "modified" //修改
+)
+
+// FieldChange 字段变更，path为字段在规则中的路径，如 body.userId.required
+type FieldChange struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+	From any    `json:"from,omitempty"`
+	To   any    `json:"to,omitempty"`
+}
+
+// ComponentChange 组件变更，同一层中通过组件名进行匹配
+type ComponentChange struct {
+	Step    int           `json:"step"`
+	Name    string        `json:"name"`
+	Type    string        `json:"type"`
+	Changes []FieldChange `json:"changes,omitempty"` //修改的字段，仅type为modified时存在
+}
+
+// RuleDiff 两个规则版本之间的差异
+type RuleDiff struct {
+	Fields     []FieldChange     `json:"fields"`     //规则基础配置的变更，如mode、timeout、limit等
+	Request    []FieldChange     `json:"request"`    //请求参数定义的变更
+	Response   []FieldChange     `json:"response"`   //返回数据定义的变更
+	Components []ComponentChange `json:"components"` //组件的变更
+}
+
+// DiffRule 对比两个规则版本，返回从from到to的差异
+func DiffRule(from, to *Rule) *RuleDiff {
+	diff := &RuleDiff{
+		Fields:     make([]FieldChange, 0),
+		Request:    make([]FieldChange, 0),
+		Response:   make([]FieldChange, 0),
+		Components: make([]ComponentChange, 0),
+	}
+
+	fromMap, toMap := diffData(from), diffData(to)
+	for _, key := range []string{"version", "request", "response", "components"} {
+		delete(fromMap, key)
+		delete(toMap, key)
+	}
+	diffValue("", fromMap, toMap, &diff.Fields)
+	diffValue("", diffData(from.Request), diffData(to.Request), &diff.Request)
+	diffValue("", diffData(from.Response), diffData(to.Response), &diff.Response)
+
+	steps := len(from.Components)
+	if len(to.Components) > steps {
+		steps = len(to.Components)
+	}
+	for step := 0; step < steps; step++ {
+		diff.Components = append(diff.Components, diffStep(step, from.Components, to.Components)...)
+	}
+	return diff
+}
+
+// diffStep 对比同一层中的组件，新增、删除的组件按照在层中的顺序返回
+func diffStep(step int, from, to Components) []ComponentChange {
+	var fromList, toList []Component
+	if step < len(from) {
+		fromList = from[step]
+	}
+	if step < len(to) {
+		toList = to[step]
+	}
+
+	toIndex := map[string]Component{}
+	for _, item := range toList {
+		toIndex[item.Name] = item
+	}
+
+	list := make([]ComponentChange, 0)
+	fromIndex := map[string]bool{}
+	for _, item := range fromList {
+		fromIndex[item.Name] = true
+		target, ok := toIndex[item.Name]
+		if !ok {
+			list = append(list, ComponentChange{Step: step, Name: item.Name, Type: DiffRemoved})
+			continue
+		}
+
+		changes := make([]FieldChange, 0)
+		diffValue("", diffData(item), diffData(target), &changes)
+		if len(changes) != 0 {
+			list = append(list, ComponentChange{Step: step, Name: item.Name, Type: DiffModified, Changes: changes})
+		}
+	}
+
+	for _, item := range toList {
+		if !fromIndex[item.Name] {
+			list = append(list, ComponentChange{Step: step, Name: item.Name, Type: DiffAdded})
+		}
+	}
+	return list
+}
+
+// diffData 将结构转换为通用的map结构，保证对比的字段与规则json中的字段名一致
+func diffData(value any) map[string]any {
+	data := map[string]any{}
+	str, _ := json.MarshalToString(value)
+	_ = json.UnmarshalFromString(str, &data)
+	return data
+}
+
+// diffValue 递归对比两个值，object逐个字段对比，其他类型整体对比
+func diffValue(path string, from, to any, changes *[]FieldChange) {
+	fromMap, fok := from.(map[string]any)
+	toMap, tok := to.(map[string]any)
+	if fok && tok {
+		keys := make([]string, 0, len(fromMap)+len(toMap))
+		for key := range fromMap {
+			keys = append(keys, key)
+		}
+		for key := range toMap {
+			if _, ok := fromMap[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			field := key
+			if path != "" {
+				field = fmt.Sprintf("%v.%v", path, key)
+			}
+			diffValue(field, fromMap[key], toMap[key], changes)
+		}
+		return
+	}
+
+	if diffEmpty(from) && diffEmpty(to) {
+		return
+	}
+
+	switch {
+	case reflect.DeepEqual(from, to):
+	case from == nil:
+		*changes = append(*changes, FieldChange{Path: path, Type: DiffAdded, To: to})
+	case to == nil:
+		*changes = append(*changes, FieldChange{Path: path, Type: DiffRemoved, From: from})
+	default:
+		*changes = append(*changes, FieldChange{Path: path, Type: DiffModified, From: from, To: to})
+	}
+}
+
+// diffEmpty null与空数组、空object视为相同，避免未设置与设置为空时产生无意义的变更
+func diffEmpty(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
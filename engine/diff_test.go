@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffRule(t *testing.T) {
+	from := &Rule{
+		Version: "v1",
+		Mode:    "sync",
+		Timeout: 10,
+		Request: Request{
+			Type: "json",
+			Body: map[string]FieldRule{
+				"userId": {Type: "int", Required: true},
+				"name":   {Type: "string"},
+			},
+		},
+		Response: Response{Type: "json", Body: map[string]any{"id": "{user.id}"}},
+		Components: Components{
+			{{Name: "user", Type: "api", Url: "http://a/user"}, {Name: "order", Type: "api", Url: "http://a/order"}},
+			{{Name: "check", Type: "script", Url: "check"}},
+		},
+	}
+	to := &Rule{
+		Version: "v2",
+		Mode:    "async",
+		Timeout: 10,
+		Limit:   &Limit{},
+		Request: Request{
+			Type: "json",
+			Body: map[string]FieldRule{
+				"userId": {Type: "int"},
+				"age":    {Type: "int"},
+			},
+		},
+		Response: Response{Type: "json", Body: map[string]any{"id": "{user.id}", "name": "{user.name}"}},
+		Components: Components{
+			{{Name: "user", Type: "api", Url: "http://b/user"}, {Name: "pay", Type: "api", Url: "http://a/pay"}},
+			{{Name: "check", Type: "script", Url: "check"}},
+			{{Name: "notify", Type: "api"}},
+		},
+	}
+
+	diff := DiffRule(from, to)
+
+	// 版本号不参与对比，limit由null变为对象视为新增
+	if len(diff.Fields) != 2 {
+		t.Fatalf("fields = %+v", diff.Fields)
+	}
+	if c := diff.Fields[0]; c.Path != "limit" || c.Type != DiffAdded || c.From != nil {
+		t.Errorf("fields[0] = %+v", c)
+	}
+	if c := diff.Fields[1]; c.Path != "mode" || c.Type != DiffModified || c.From != "sync" || c.To != "async" {
+		t.Errorf("fields[1] = %+v", c)
+	}
+
+	// 字段按照路径排序，逐层对比object中的字段
+	request := []FieldChange{
+		{Path: "body.age", Type: DiffAdded, To: map[string]any{"type": "int", "required": false}},
+		{Path: "body.name", Type: DiffRemoved, From: map[string]any{"type": "string", "required": false}},
+		{Path: "body.userId.required", Type: DiffModified, From: true, To: false},
+	}
+	if !reflect.DeepEqual(diff.Request, request) {
+		t.Errorf("request = %+v", diff.Request)
+	}
+
+	response := []FieldChange{{Path: "body.name", Type: DiffAdded, To: "{user.name}"}}
+	if !reflect.DeepEqual(diff.Response, response) {
+		t.Errorf("response = %+v", diff.Response)
+	}
+
+	// 同一层中按组件名匹配，新增的组件排在删除、修改之后
+	components := []ComponentChange{
+		{Step: 0, Name: "user", Type: DiffModified, Changes: []FieldChange{{Path: "url", Type: DiffModified, From: "http://a/user", To: "http://b/user"}}},
+		{Step: 0, Name: "order", Type: DiffRemoved},
+		{Step: 0, Name: "pay", Type: DiffAdded},
+		{Step: 2, Name: "notify", Type: DiffAdded},
+	}
+	if !reflect.DeepEqual(diff.Components, components) {
+		t.Errorf("components = %+v", diff.Components)
+	}
+}
+
+func TestDiffRuleSame(t *testing.T) {
+	rule := &Rule{
+		Version:    "v1",
+		Request:    Request{Body: map[string]FieldRule{"id": {Type: "int"}}},
+		Components: Components{{{Name: "a", Type: "api", Input: map[string]any{"id": 1}}}},
+	}
+	clone, err := rule.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clone.Version = "v2"
+
+	// 没有变更时返回空数组而不是null
+	diff := DiffRule(rule, clone)
+	if diff.Fields == nil || diff.Request == nil || diff.Response == nil || diff.Components == nil {
+		t.Fatalf("diff should use empty slices: %+v", diff)
+	}
+	if len(diff.Fields)+len(diff.Request)+len(diff.Response)+len(diff.Components) != 0 {
+		t.Errorf("diff = %+v", diff)
+	}
+}
+
+func TestDiffRuleRemoveStep(t *testing.T) {
+	from := &Rule{Components: Components{{{Name: "a"}}, {{Name: "b"}, {Name: "c"}}}}
+	to := &Rule{Components: Components{{{Name: "a"}}}}
+
+	components := []ComponentChange{
+		{Step: 1, Name: "b", Type: DiffRemoved},
+		{Step: 1, Name: "c", Type: DiffRemoved},
+	}
+	if diff := DiffRule(from, to); !reflect.DeepEqual(diff.Components, components) {
+		t.Errorf("components = %+v", diff.Components)
+	}
+}
+
+func TestDiffValue(t *testing.T) {
+	var changes []FieldChange
+	diffValue("", map[string]any{
+		"list": []any{1.0, 2.0},
+		"obj":  map[string]any{"a": 1.0, "b": map[string]any{"c": "x"}},
+		"same": "v",
+	}, map[string]any{
+		"list": []any{1.0, 3.0},
+		"obj":  map[string]any{"a": 1.0, "b": map[string]any{"c": "y"}},
+		"same": "v",
+		"type": map[string]any{"x": 1.0},
+	}, &changes)
+
+	// 数组整体对比，object递归对比
+	want := []FieldChange{
+		{Path: "list", Type: DiffModified, From: []any{1.0, 2.0}, To: []any{1.0, 3.0}},
+		{Path: "obj.b.c", Type: DiffModified, From: "x", To: "y"},
+		{Path: "type", Type: DiffAdded, To: map[string]any{"x": 1.0}},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("changes = %+v", changes)
+	}
+}
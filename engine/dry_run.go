@@ -0,0 +1,66 @@
+package engine
+
+import (
+	"fmt"
+	"ps-go/tools"
+)
+
+// dryRun 试运行配置，试运行时不发送api请求，组件使用模拟数据作为执行结果，并且不写入任何执行记录
+type dryRun struct {
+	mocks map[string]any //组件名对应的模拟数据
+	scope string         //子流程的规则标志，子流程中的组件使用 <规则标志>/<组件名> 获取模拟数据
+}
+
+// mock 获取组件的模拟数据，每次返回数据的副本，防止多个组件共用同一份数据
+func (d *dryRun) mock(name string) (any, bool) {
+	if d == nil {
+		return nil, false
+	}
+	if d.scope != "" {
+		name = d.scope + "/" + name
+	}
+	data, ok := d.mocks[name]
+	return tools.CopyData(data), ok
+}
+
+// sub 子流程的试运行配置，与上级流程共用模拟数据，按照子流程的规则标志区分组件，防止同名组件互相影响
+func (d *dryRun) sub(key string) *dryRun {
+	if d == nil {
+		return nil
+	}
+	return &dryRun{mocks: d.mocks, scope: key}
+}
+
+// SetDryRun 设置为试运行，mocks为组件名对应的模拟数据，子流程中的组件为 <METHOD>:<path>/<组件名>。
+// api组件必须配置模拟数据，script、rule、wait组件配置了模拟数据时直接作为执行结果，未配置时正常执行
+func (r *runner) SetDryRun(mocks map[string]any) {
+	if mocks == nil {
+		mocks = map[string]any{}
+	}
+	r.dryRun = &dryRun{mocks: mocks}
+}
+
+// Wait 等待流程执行完成
+func (r *runner) Wait() {
+	<-r.done
+}
+
+// Status 获取流程的执行状态
+func (r *runner) Status() string {
+	return r.logger.GetStatus()
+}
+
+// Log 获取流程的执行日志
+func (r *runner) Log() any {
+	return r.logger.Get()
+}
+
+// mockRequest 试运行时使用模拟数据作为api的返回数据
+func (r *runtime) mockRequest(request *tools.HttpRequest) error {
+	data, ok := r.dryRun.mock(r.component.Name)
+	if !ok {
+		return NewDryRunError(fmt.Sprintf("试运行时api组件%v未配置模拟数据", r.component.Name))
+	}
+	request.SetMockResponse(data)
+	return nil
+}
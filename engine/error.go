@@ -18,6 +18,11 @@ const (
 	ActiveSuspendErrorCode    = "110009"
 	BreakErrorCode            = "110010"
 	SuspendErrorCode          = "110011"
+	DeadlineErrorCode         = "110012"
+	CanceledErrorCode         = "110013"
+	StatusCodeErrorCode       = "110014"
+	BreakerOpenErrorCode      = "110015"
+	DryRunErrorCode           = "110016"
 )
 
 type Error struct {
@@ -127,3 +132,43 @@ func NewActiveSuspendError(code, msg string) error {
 		Msg:  msg,
 	}
 }
+
+// NewDeadlineError 流程执行超时
+func NewDeadlineError(msg string) error {
+	return &Error{
+		Code: DeadlineErrorCode,
+		Msg:  msg,
+	}
+}
+
+// NewCanceledError 流程中断之后，取消执行中的组件
+func NewCanceledError(msg string) error {
+	return &Error{
+		Code: CanceledErrorCode,
+		Msg:  msg,
+	}
+}
+
+// NewStatusCodeError 返回的状态码命中重试配置
+func NewStatusCodeError(msg string) error {
+	return &Error{
+		Code: StatusCodeErrorCode,
+		Msg:  msg,
+	}
+}
+
+// NewBreakerOpenError 上游服务已熔断
+func NewBreakerOpenError(msg string) error {
+	return &Error{
+		Code: BreakerOpenErrorCode,
+		Msg:  msg,
+	}
+}
+
+// NewDryRunError 试运行时组件缺少模拟数据，或者执行了试运行不支持的操作
+func NewDryRunError(msg string) error {
+	return &Error{
+		Code: DryRunErrorCode,
+		Msg:  msg,
+	}
+}
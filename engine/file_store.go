@@ -0,0 +1,238 @@
+package engine
+
+import (
+	"crypto/md5"
+	"fmt"
+	"github.com/fsnotify/fsnotify"
+	json "github.com/json-iterator/go"
+	"github.com/limeschool/gin"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"ps-go/errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	fileRuleDir   = "rule"   //规则目录，文件路径为 rule/<规则名>.<请求方法>.[json|yaml|yml]
+	fileScriptDir = "script" //脚本目录，文件路径为 script/<脚本名>，脚本名不以.js结尾时自动补全
+
+	fileReloadDelay = 200 * time.Millisecond
+)
+
+type fileEntry struct {
+	data    []byte
+	version string
+}
+
+// fileStore 从目录中加载规则以及脚本，用于本地开发以及通过git管理规则
+type fileStore struct {
+	ctx     *gin.Context
+	dir     string
+	lock    sync.RWMutex
+	rules   map[string]fileEntry
+	scripts map[string]fileEntry
+}
+
+// NewFileStore 创建基于文件目录的存储器，watch为true时监听文件变更并自动重新加载
+func NewFileStore(ctx *gin.Context, dir string, watch bool) (*fileStore, error) {
+	s := &fileStore{
+		ctx:     ctx,
+		dir:     filepath.Clean(dir),
+		rules:   map[string]fileEntry{},
+		scripts: map[string]fileEntry{},
+	}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	if watch {
+		if err := s.watch(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// LoadRule 获取指定规则
+func (s *fileStore) LoadRule(ctx *gin.Context, method, path string) (*Rule, error) {
+	s.lock.RLock()
+	entry, ok := s.rules[RuleKey(method, path)]
+	s.lock.RUnlock()
+	if !ok {
+		return nil, errors.NewF("不存在流程：%v->%v", method, path)
+	}
+
+	er := Rule{Version: entry.version}
+	return &er, json.Unmarshal(entry.data, &er)
+}
+
+// LoadScript 获取指定脚本
+func (s *fileStore) LoadScript(ctx *gin.Context, name string) (string, string, error) {
+	s.lock.RLock()
+	entry, ok := s.scripts[strings.TrimSuffix(name, ".js")]
+	s.lock.RUnlock()
+	if !ok {
+		return "", "", errors.NewF("加载脚本%v失败：脚本文件不存在", name)
+	}
+	return string(entry.data), entry.version, nil
+}
+
+// LoadLimit 获取规则的限流配置，文件存储不支持单独设置限流配置，直接使用规则中的配置
+func (s *fileStore) LoadLimit(ctx *gin.Context, method, path string, rule *Rule) (*Limit, error) {
+	return rule.Limit, nil
+}
+
+// reload 重新加载目录下的全部规则以及脚本。
+// 单个文件解析失败时记录错误日志，并保留该文件之前加载成功的内容，不影响其他文件
+func (s *fileStore) reload() error {
+	if _, err := os.Stat(s.dir); err != nil {
+		return errors.NewF("规则目录%v不可用：%v", s.dir, err.Error())
+	}
+
+	rules := map[string]fileEntry{}
+	scripts := map[string]fileEntry{}
+
+	s.lock.RLock()
+	oldRules, oldScripts := s.rules, s.scripts
+	s.lock.RUnlock()
+
+	s.walk(fileRuleDir, func(name string, path string) {
+		key, entry, err := s.loadRuleFile(name, path)
+		if err == nil {
+			rules[key] = entry
+			return
+		}
+		s.ctx.Log.Error("规则文件加载失败", zap.Any("file", path), zap.Any("err", err))
+		if key != "" {
+			if old, ok := oldRules[key]; ok {
+				rules[key] = old
+			}
+		}
+	})
+
+	s.walk(fileScriptDir, func(name string, path string) {
+		key := strings.TrimSuffix(name, ".js")
+		data, err := os.ReadFile(path)
+		if err == nil {
+			scripts[key] = fileEntry{data: data, version: fileVersion(data)}
+			return
+		}
+		s.ctx.Log.Error("脚本文件加载失败", zap.Any("file", path), zap.Any("err", err))
+		if old, ok := oldScripts[key]; ok {
+			scripts[key] = old
+		}
+	})
+
+	s.lock.Lock()
+	s.rules, s.scripts = rules, scripts
+	s.lock.Unlock()
+	return nil
+}
+
+// walk 遍历子目录下的全部文件，name为相对子目录的路径
+func (s *fileStore) walk(sub string, fn func(name string, path string)) {
+	root := filepath.Join(s.dir, sub)
+	_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			return nil
+		}
+		name, _ := filepath.Rel(root, path)
+		fn(filepath.ToSlash(name), path)
+		return nil
+	})
+}
+
+// loadRuleFile 加载规则文件，yaml格式的规则转换为json存储，与数据库中的规则格式保持一致
+func (s *fileStore) loadRuleFile(name, path string) (string, fileEntry, error) {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	index := strings.LastIndex(base, ".")
+	if index <= 0 {
+		return "", fileEntry{}, errors.NewF("规则文件名格式错误，应为<规则名>.<请求方法>%v", ext)
+	}
+	key := RuleKey(base[index+1:], base[:index])
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return key, fileEntry{}, err
+	}
+
+	switch ext {
+	case ".json":
+	case ".yaml", ".yml":
+		var value any
+		if err = yaml.Unmarshal(data, &value); err != nil {
+			return key, fileEntry{}, err
+		}
+		if data, err = json.Marshal(value); err != nil {
+			return key, fileEntry{}, err
+		}
+	default:
+		return "", fileEntry{}, errors.NewF("不支持的规则文件格式：%v", ext)
+	}
+
+	// 提前校验规则格式，防止错误的规则覆盖之前加载成功的内容
+	if err = json.Unmarshal(data, &Rule{}); err != nil {
+		return key, fileEntry{}, err
+	}
+	return key, fileEntry{data: data, version: fileVersion(data)}, nil
+}
+
+// watch 监听目录下的文件变更，变更之后延迟一段时间再重新加载，合并短时间内的多次变更
+func (s *fileStore) watch() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	// fsnotify不会监听子目录，需要逐个添加
+	addDir := func(root string) {
+		_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err == nil && info.IsDir() {
+				_ = watcher.Add(path)
+			}
+			return nil
+		})
+	}
+	addDir(s.dir)
+
+	go func() {
+		var timer *time.Timer
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Op&fsnotify.Create != 0 {
+					if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+						addDir(event.Name)
+					}
+				}
+				if timer != nil {
+					timer.Stop()
+				}
+				timer = time.AfterFunc(fileReloadDelay, func() {
+					if err := s.reload(); err != nil {
+						s.ctx.Log.Error("规则目录重新加载失败", zap.Any("err", err))
+						return
+					}
+					s.ctx.Log.Info("规则目录重新加载完成", zap.Any("dir", s.dir))
+				})
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				s.ctx.Log.Error("规则目录监听异常", zap.Any("err", err))
+			}
+		}
+	}()
+	return nil
+}
+
+// fileVersion 使用文件内容的md5作为版本号，相同内容在不同实例上的版本号一致
+func fileVersion(data []byte) string {
+	return strings.ToUpper(fmt.Sprintf("%x", md5.Sum(data)))
+}
@@ -0,0 +1,168 @@
+package engine
+
+import (
+	"github.com/limeschool/gin"
+	"go.uber.org/zap"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTestFileStore(t *testing.T, dir string, watch bool) *fileStore {
+	t.Helper()
+	s, err := NewFileStore(&gin.Context{Log: zap.NewNop()}, dir, watch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestFileStoreLoad(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "rule/api/v1/register.post.json", `{"mode":"async","limit":{"maxConcurrency":2}}`)
+	writeFile(t, dir, "rule/api/v1/user.get.yaml", "mode: sync\ntimeout: 5\n")
+	writeFile(t, dir, "rule/api/v1/bad_name.json", `{}`)
+	writeFile(t, dir, "rule/api/v1/user.post.txt", `{}`)
+	writeFile(t, dir, "rule/api/v1/.hidden.get.json", `{}`)
+	writeFile(t, dir, "script/rule/api/test.js", "function main(){}")
+	s := newTestFileStore(t, dir, false)
+
+	rule, err := s.LoadRule(nil, "post", "/api/v1/register")
+	if err != nil || rule.Mode != "async" || rule.Version == "" {
+		t.Fatalf("json rule = %+v, %v", rule, err)
+	}
+	if limit, _ := s.LoadLimit(nil, "POST", "/api/v1/register", rule); limit == nil || limit.MaxConcurrency != 2 {
+		t.Fatalf("limit = %+v", limit)
+	}
+
+	// yaml格式的规则转换为json
+	rule, err = s.LoadRule(nil, "GET", "api/v1/user")
+	if err != nil || rule.Mode != "sync" || rule.Timeout != 5 {
+		t.Fatalf("yaml rule = %+v, %v", rule, err)
+	}
+
+	// 文件名格式错误、不支持的格式以及隐藏文件不加载
+	if len(s.rules) != 2 {
+		t.Fatalf("rules = %v", len(s.rules))
+	}
+
+	// 脚本名可以省略.js后缀
+	script, version, err := s.LoadScript(nil, "rule/api/test")
+	if err != nil || script != "function main(){}" || version != fileVersion([]byte("function main(){}")) {
+		t.Fatalf("script = %v, %v, %v", script, version, err)
+	}
+	if _, _, err = s.LoadScript(nil, "rule/api/test.js"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = s.LoadScript(nil, "rule/api/missing"); err == nil {
+		t.Fatal("expected missing script error")
+	}
+	if _, err = s.LoadRule(nil, "GET", "/api/v1/missing"); err == nil {
+		t.Fatal("expected missing rule error")
+	}
+}
+
+func TestFileStoreReload(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "rule/a.get.json", `{"mode":"sync"}`)
+	writeFile(t, dir, "rule/b.get.json", `{"mode":"sync"}`)
+	writeFile(t, dir, "script/s.js", "v1")
+	s := newTestFileStore(t, dir, false)
+
+	before, _ := s.LoadRule(nil, "GET", "a")
+
+	// 解析失败的文件保留之前加载成功的内容
+	writeFile(t, dir, "rule/a.get.json", `{"mode":`)
+	// 内容变更之后版本号变更
+	writeFile(t, dir, "rule/b.get.json", `{"mode":"async"}`)
+	writeFile(t, dir, "script/s.js", "v2")
+	if err := s.reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := s.LoadRule(nil, "GET", "a")
+	if err != nil || after.Version != before.Version || after.Mode != "sync" {
+		t.Fatalf("invalid file replaced: %+v, %v", after, err)
+	}
+	if b, _ := s.LoadRule(nil, "GET", "b"); b.Mode != "async" {
+		t.Fatalf("b = %+v", b)
+	}
+	if script, _, _ := s.LoadScript(nil, "s"); script != "v2" {
+		t.Fatalf("script = %v", script)
+	}
+
+	// 删除的文件不再加载
+	if err = os.Remove(filepath.Join(dir, "rule/b.get.json")); err != nil {
+		t.Fatal(err)
+	}
+	if err = s.reload(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.LoadRule(nil, "GET", "b"); err == nil {
+		t.Fatal("deleted rule still loaded")
+	}
+
+	// 首次加载就失败的文件不存在之前的内容
+	writeFile(t, dir, "rule/c.get.yaml", "mode: [")
+	if err = s.reload(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.LoadRule(nil, "GET", "c"); err == nil {
+		t.Fatal("invalid rule loaded")
+	}
+}
+
+func TestFileStoreDirError(t *testing.T) {
+	if _, err := NewFileStore(&gin.Context{Log: zap.NewNop()}, filepath.Join(t.TempDir(), "missing"), false); err == nil {
+		t.Fatal("expected dir error")
+	}
+
+	// 目录被删除之后重新加载失败，继续使用已经加载的内容
+	dir := t.TempDir()
+	writeFile(t, dir, "rule/a.get.json", `{}`)
+	s := newTestFileStore(t, dir, false)
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.reload(); err == nil {
+		t.Fatal("expected reload error")
+	}
+	if _, err := s.LoadRule(nil, "GET", "a"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileStoreWatch(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "rule/a.get.json", `{"mode":"sync"}`)
+	s := newTestFileStore(t, dir, true)
+
+	// 新建子目录中的文件同样会被监听
+	writeFile(t, dir, "rule/sub/b.get.json", `{"mode":"async"}`)
+	writeFile(t, dir, "rule/a.get.json", `{"mode":"async"}`)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		a, err := s.LoadRule(nil, "GET", "a")
+		_, errB := s.LoadRule(nil, "GET", "sub/b")
+		if err == nil && a.Mode == "async" && errB == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("rules not reloaded: %+v, %v, %v", a, err, errB)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
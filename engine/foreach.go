@@ -0,0 +1,144 @@
+package engine
+
+import (
+	"fmt"
+	"ps-go/errors"
+	"ps-go/tools"
+	"ps-go/tools/pool"
+	"sync"
+)
+
+const (
+	foreachItemKey  = "item"  //foreach当前项的取值名
+	foreachIndexKey = "index" //foreach当前项索引的取值名
+)
+
+// foreachGroup foreach的执行控制，用于限制并发数以及记录第一个错误
+type foreachGroup struct {
+	wg    sync.WaitGroup
+	lock  sync.RWMutex
+	limit chan struct{}
+	err   error
+}
+
+func (g *foreachGroup) SetError(err error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if g.err == nil {
+		g.err = err
+	}
+}
+
+func (g *foreachGroup) Error() error {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.err
+}
+
+// foreachTask foreach中单项的执行任务
+type foreachTask struct {
+	runtime *runtime
+	group   *foreachGroup
+	resp    any
+}
+
+func (t *foreachTask) Run() {
+	defer func() {
+		if p := recover(); p != nil {
+			t.group.SetError(NewSystemPanicError(fmt.Sprint(p)))
+		}
+		<-t.group.limit
+		t.group.wg.Done()
+	}()
+
+	resp, err := t.runtime.runSync()
+	if err != nil {
+		t.group.SetError(err)
+		return
+	}
+	t.resp = resp
+}
+
+// runForeach 遍历数组执行子组件，并按顺序返回每一项的执行结果
+func (r *runtime) runForeach() (any, error) {
+	com := r.component.Component
+	if com == nil {
+		return nil, errors.NewF("foreach组件%v未配置component", r.component.Name)
+	}
+	if com.Type != ComponentTypeApi && com.Type != ComponentTypeScript && com.Type != ComponentTypeRule {
+		return nil, errors.NewF("foreach组件%v不支持%v类型的子组件", r.component.Name, com.Type)
+	}
+
+	items, err := tools.ToSlice(r.runStore.GetMatchData(r.component.Items))
+	if err != nil {
+		return nil, errors.NewF("foreach组件%v的items必须为数组：%v", r.component.Name, err.Error())
+	}
+
+	parallel := r.component.MaxParallel
+	if parallel <= 0 {
+		parallel = 1
+	}
+
+	group := &foreachGroup{limit: make(chan struct{}, parallel)}
+	tasks := make([]*foreachTask, 0, len(items))
+
+	for index, item := range items {
+		group.limit <- struct{}{}
+
+		// 已经出现错误则不再执行后续项
+		if group.Error() != nil {
+			<-group.limit
+			break
+		}
+
+		task := &foreachTask{
+			runtime: r.newItemRuntime(*com, index, item),
+			group:   group,
+		}
+		tasks = append(tasks, task)
+
+		group.wg.Add(1)
+		if err = pool.Get().Invoke(task); err != nil {
+			group.SetError(err)
+			<-group.limit
+			group.wg.Done()
+		}
+	}
+	group.wg.Wait()
+
+	if err = group.Error(); err != nil {
+		return nil, err
+	}
+
+	resp := make([]any, 0, len(tasks))
+	for _, task := range tasks {
+		resp = append(resp, task.resp)
+	}
+	return resp, nil
+}
+
+// newItemRuntime 创建foreach单项的运行时，每一项拥有独立的item、index数据
+func (r *runtime) newItemRuntime(com Component, index int, item any) *runtime {
+	com = com.Copy()
+	return &runtime{
+		component:    com,
+		ctx:          r.ctx,
+		trx:          r.trx,
+		step:         r.step,
+		action:       index,
+		maxRetry:     com.MaxRetry(),
+		retryMaxWait: com.RetryMaxWait,
+		response:     r.response,
+		err:          r.err,
+		store:        r.store,
+		stepLog:      r.stepLog,
+		stack:        r.stack,
+		runCtx:       r.runCtx,
+		dryRun:       r.dryRun,
+		componentLog: r.componentLog.NewChildLog(),
+		runStore: newScopeStore(r.runStore, map[string]any{
+			foreachItemKey:  item,
+			foreachIndexKey: index,
+		}),
+	}
+}
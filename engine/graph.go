@@ -0,0 +1,303 @@
+package engine
+
+import (
+	"fmt"
+	"ps-go/errors"
+	"sync"
+)
+
+const (
+	nodeWait   = iota // 等待执行
+	nodeRun           // 执行中
+	nodeFinish        // 执行完成
+)
+
+// node 组件依赖图节点
+type node struct {
+	key    string  // 节点唯一标志 step.name
+	step   int     // 所在层
+	action int     // 所在层中的位置
+	state  int     // 执行状态
+	skip   bool    // 是否因为分支未命中而跳过
+	wait   int     // 未完成的依赖数量
+	deps   []*node // 依赖的节点
+	next   []*node // 依赖当前节点的节点
+}
+
+// nodeResult 组件执行结果
+type nodeResult struct {
+	node   *node
+	ok     bool
+	branch string // 分支组件命中的目标
+	skip   bool   // 是否因为准入条件未通过而跳过
+	wait   bool   // 是否进入等待信号
+}
+
+// graph 组件依赖图
+type graph struct {
+	lock   sync.RWMutex
+	nodes  []*node
+	layers [][]*node          // 按层划分的节点
+	groups map[string][]*node // 按分组划分的节点
+	steps  map[string]int     // 层名称对应的层数
+}
+
+// NodeKey 组件在依赖图中的唯一标志，同一层中组件名不能重复
+func NodeKey(step int, name string) string {
+	return fmt.Sprintf("%v.%v", step+1, name)
+}
+
+// newGraph 通过组件配置创建依赖图，未设置dependsOn的组件依赖上一层的全部组件
+func newGraph(rule *Rule) (*graph, error) {
+	components := rule.Components
+	g := &graph{
+		groups: map[string][]*node{},
+		steps:  map[string]int{},
+	}
+
+	names := map[string]*node{}
+	dup := map[string]bool{}
+	layers := make([][]*node, len(components))
+
+	for step, name := range rule.StepNames {
+		if name != "" && step < len(components) {
+			g.steps[name] = step
+		}
+	}
+
+	for step, list := range components {
+		for action, com := range list {
+			n := &node{
+				key:    NodeKey(step, com.Name),
+				step:   step,
+				action: action,
+			}
+			if com.IsFinish {
+				n.state = nodeFinish
+			}
+			if _, ok := names[com.Name]; ok {
+				dup[com.Name] = true
+			}
+			names[com.Name] = n
+			layers[step] = append(layers[step], n)
+			g.nodes = append(g.nodes, n)
+			if com.Group != "" {
+				g.groups[com.Group] = append(g.groups[com.Group], n)
+			}
+		}
+	}
+	g.layers = layers
+
+	// 校验分支目标
+	for _, list := range components {
+		for _, com := range list {
+			if com.Type != ComponentTypeSwitch && com.Type != ComponentTypeWait {
+				continue
+			}
+			for _, target := range com.BranchTargets() {
+				if !g.HasTarget(target) {
+					return nil, errors.NewF("分支组件%v的目标%v不存在", com.Name, target)
+				}
+			}
+		}
+	}
+
+	// 建立依赖关系
+	var prev []*node
+	for step, list := range components {
+		for action, com := range list {
+			n := layers[step][action]
+
+			// 兼容分层格式，默认依赖上一层的组件
+			if com.DependsOn == nil {
+				for _, dep := range prev {
+					link(dep, n)
+				}
+				continue
+			}
+
+			for _, name := range com.DependsOn {
+				if dup[name] {
+					return nil, errors.NewF("组件%v依赖的组件名%v不唯一", com.Name, name)
+				}
+				dep, ok := names[name]
+				if !ok {
+					return nil, errors.NewF("组件%v依赖的组件%v不存在", com.Name, name)
+				}
+				if dep == n {
+					return nil, errors.NewF("组件%v不能依赖自身", com.Name)
+				}
+				link(dep, n)
+			}
+		}
+		if len(list) != 0 {
+			prev = layers[step]
+		}
+	}
+
+	if err := g.checkCycle(); err != nil {
+		return nil, err
+	}
+
+	// 计算未完成的依赖数量，异常恢复时已完成的组件不再执行
+	for _, n := range g.nodes {
+		for _, dep := range n.deps {
+			if dep.state != nodeFinish {
+				n.wait++
+			}
+		}
+	}
+	return g, nil
+}
+
+// link 设置节点依赖关系
+func link(dep, n *node) {
+	for _, item := range n.deps {
+		if item == dep {
+			return
+		}
+	}
+	n.deps = append(n.deps, dep)
+	dep.next = append(dep.next, n)
+}
+
+// checkCycle 检测是否存在循环依赖
+func (g *graph) checkCycle() error {
+	degree := make(map[*node]int, len(g.nodes))
+	var queue []*node
+	for _, n := range g.nodes {
+		degree[n] = len(n.deps)
+		if len(n.deps) == 0 {
+			queue = append(queue, n)
+		}
+	}
+
+	count := 0
+	for len(queue) != 0 {
+		n := queue[0]
+		queue = queue[1:]
+		count++
+		for _, next := range n.next {
+			degree[next]--
+			if degree[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	if count != len(g.nodes) {
+		var keys []string
+		for n, d := range degree {
+			if d != 0 {
+				keys = append(keys, n.key)
+			}
+		}
+		return errors.NewF("组件存在循环依赖:%v", keys)
+	}
+	return nil
+}
+
+// Ready 获取可以立即执行的节点
+func (g *graph) Ready() []*node {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	var list []*node
+	for _, n := range g.nodes {
+		if n.state == nodeWait && n.wait == 0 {
+			list = append(list, n)
+		}
+	}
+	return list
+}
+
+// Start 设置节点开始执行
+func (g *graph) Start(n *node) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	n.state = nodeRun
+}
+
+// Finish 设置节点执行完成，并返回因此可以执行的节点
+func (g *graph) Finish(n *node) []*node {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	n.state = nodeFinish
+
+	var list []*node
+	for _, next := range n.next {
+		next.wait--
+		if next.wait == 0 && next.state == nodeWait {
+			list = append(list, next)
+		}
+	}
+	return list
+}
+
+// HasTarget 判断分支目标是否存在
+func (g *graph) HasTarget(target string) bool {
+	_, ok := g.steps[target]
+	return ok || len(g.groups[target]) != 0
+}
+
+// Targets 获取分支目标对应的节点，目标为层名称时同时返回层数，否则层数为-1
+func (g *graph) Targets(target string) ([]*node, int) {
+	var list []*node
+	step := -1
+	if index, ok := g.steps[target]; ok {
+		step = index
+		list = append(list, g.layers[index]...)
+	}
+	list = append(list, g.groups[target]...)
+	return list, step
+}
+
+// Branch 分支组件命中目标之后，跳过其他分支目标的节点。
+// 目标为层时，跳转到该层，中间层未开始执行的节点也会被跳过
+func (g *graph) Branch(n *node, target string, targets []string) {
+	selected := map[*node]bool{}
+	list, step := g.Targets(target)
+	for _, item := range list {
+		selected[item] = true
+	}
+
+	var skips []*node
+	for _, name := range targets {
+		if name == target {
+			continue
+		}
+		list, _ = g.Targets(name)
+		skips = append(skips, list...)
+	}
+	for i := n.step + 1; i < step; i++ {
+		skips = append(skips, g.layers[i]...)
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	for _, item := range skips {
+		if !selected[item] && item != n && item.state == nodeWait {
+			item.skip = true
+		}
+	}
+}
+
+// FinishKeys 获取已经执行完成的节点标志，被分支跳过的节点同样视为完成
+func (g *graph) FinishKeys() []string {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	keys := make([]string, 0)
+	for _, n := range g.nodes {
+		if n.state == nodeFinish || n.skip {
+			keys = append(keys, n.key)
+		}
+	}
+	return keys
+}
+
+// Len 节点数量
+func (g *graph) Len() int {
+	return len(g.nodes)
+}
@@ -0,0 +1,206 @@
+package engine
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func nodeKeys(list []*node) []string {
+	keys := make([]string, 0, len(list))
+	for _, n := range list {
+		keys = append(keys, n.key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func graphNode(t *testing.T, g *graph, key string) *node {
+	t.Helper()
+	for _, n := range g.nodes {
+		if n.key == key {
+			return n
+		}
+	}
+	t.Fatalf("node %v not found", key)
+	return nil
+}
+
+func TestGraphLayers(t *testing.T) {
+	g, err := newGraph(&Rule{Components: Components{
+		{{Name: "a"}, {Name: "b"}},
+		{{Name: "c"}},
+		{{Name: "d"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if keys := nodeKeys(g.Ready()); !reflect.DeepEqual(keys, []string{"1.a", "1.b"}) {
+		t.Fatalf("ready = %v", keys)
+	}
+
+	// 未设置dependsOn时依赖上一层的全部组件
+	a, b := graphNode(t, g, "1.a"), graphNode(t, g, "1.b")
+	g.Start(a)
+	g.Start(b)
+	if next := g.Finish(a); len(next) != 0 {
+		t.Fatalf("c should wait for b, got %v", nodeKeys(next))
+	}
+	if next := nodeKeys(g.Finish(b)); !reflect.DeepEqual(next, []string{"2.c"}) {
+		t.Fatalf("next = %v", next)
+	}
+	if next := nodeKeys(g.Finish(graphNode(t, g, "2.c"))); !reflect.DeepEqual(next, []string{"3.d"}) {
+		t.Fatalf("next = %v", next)
+	}
+}
+
+func TestGraphDependsOn(t *testing.T) {
+	g, err := newGraph(&Rule{Components: Components{
+		{{Name: "a"}, {Name: "b", DependsOn: []string{"c"}}, {Name: "c", DependsOn: []string{}}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 同一层的组件默认没有依赖，dependsOn为[]时同样不依赖任何组件
+	if keys := nodeKeys(g.Ready()); !reflect.DeepEqual(keys, []string{"1.a", "1.c"}) {
+		t.Fatalf("ready = %v", keys)
+	}
+	if next := nodeKeys(g.Finish(graphNode(t, g, "1.c"))); !reflect.DeepEqual(next, []string{"1.b"}) {
+		t.Fatalf("next = %v", next)
+	}
+}
+
+func TestGraphError(t *testing.T) {
+	cases := []struct {
+		name string
+		rule *Rule
+		err  string
+	}{
+		{"循环依赖", &Rule{Components: Components{
+			{{Name: "a", DependsOn: []string{"c"}}, {Name: "b", DependsOn: []string{"a"}}, {Name: "c", DependsOn: []string{"b"}}},
+		}}, "循环依赖"},
+		{"跨层循环依赖", &Rule{Components: Components{
+			{{Name: "a", DependsOn: []string{"b"}}},
+			{{Name: "b"}},
+		}}, "循环依赖"},
+		{"依赖自身", &Rule{Components: Components{
+			{{Name: "a", DependsOn: []string{"a"}}},
+		}}, "不能依赖自身"},
+		{"依赖不存在", &Rule{Components: Components{
+			{{Name: "a", DependsOn: []string{"x"}}},
+		}}, "不存在"},
+		{"依赖的组件名不唯一", &Rule{Components: Components{
+			{{Name: "a"}},
+			{{Name: "a"}},
+			{{Name: "b", DependsOn: []string{"a"}}},
+		}}, "不唯一"},
+		{"分支目标不存在", &Rule{Components: Components{
+			{{Name: "a", Type: ComponentTypeSwitch, Default: "x"}},
+		}}, "目标x不存在"},
+		{"等待超时目标不存在", &Rule{Components: Components{
+			{{Name: "a", Type: ComponentTypeWait, TimeoutTarget: "x"}},
+		}}, "目标x不存在"},
+	}
+
+	for _, c := range cases {
+		_, err := newGraph(c.rule)
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%v: err = %v, want %v", c.name, err, c.err)
+		}
+	}
+}
+
+func TestGraphRecover(t *testing.T) {
+	g, err := newGraph(&Rule{Components: Components{
+		{{Name: "a", IsFinish: true}, {Name: "b"}},
+		{{Name: "c"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 异常恢复时已完成的组件不再执行，也不再阻塞依赖它的组件
+	if keys := nodeKeys(g.Ready()); !reflect.DeepEqual(keys, []string{"1.b"}) {
+		t.Fatalf("ready = %v", keys)
+	}
+	if next := nodeKeys(g.Finish(graphNode(t, g, "1.b"))); !reflect.DeepEqual(next, []string{"2.c"}) {
+		t.Fatalf("next = %v", next)
+	}
+}
+
+func TestGraphBranchGroup(t *testing.T) {
+	sw := Component{Name: "sw", Type: ComponentTypeSwitch, Cases: []Case{{Target: "g1"}, {Target: "g2"}}, Default: "g3"}
+	g, err := newGraph(&Rule{Components: Components{
+		{sw},
+		{{Name: "a", Group: "g1"}, {Name: "b", Group: "g2"}, {Name: "c", Group: "g2"}, {Name: "d", Group: "g3"}, {Name: "e"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := graphNode(t, g, "1.sw")
+	g.Start(n)
+	g.Branch(n, "g1", sw.BranchTargets())
+
+	// 只跳过其他分支目标中的组件，不属于任何分支的组件正常执行
+	for key, skip := range map[string]bool{"2.a": false, "2.b": true, "2.c": true, "2.d": true, "2.e": false} {
+		if graphNode(t, g, key).skip != skip {
+			t.Errorf("%v skip = %v, want %v", key, !skip, skip)
+		}
+	}
+
+	g.Finish(n)
+	if keys := g.FinishKeys(); !reflect.DeepEqual(keys, []string{"1.sw", "2.b", "2.c", "2.d"}) {
+		t.Fatalf("finish keys = %v", keys)
+	}
+}
+
+func TestGraphBranchStep(t *testing.T) {
+	sw := Component{Name: "sw", Type: ComponentTypeSwitch, Cases: []Case{{Target: "end"}}, Default: "mid"}
+	g, err := newGraph(&Rule{
+		StepNames: []string{"", "mid", "", "end"},
+		Components: Components{
+			{sw},
+			{{Name: "a"}},
+			{{Name: "b"}},
+			{{Name: "c"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 目标为层时，中间层未执行的组件全部跳过
+	n := graphNode(t, g, "1.sw")
+	g.Start(n)
+	g.Branch(n, "end", sw.BranchTargets())
+	for key, skip := range map[string]bool{"2.a": true, "3.b": true, "4.c": false} {
+		if graphNode(t, g, key).skip != skip {
+			t.Errorf("%v skip = %v, want %v", key, !skip, skip)
+		}
+	}
+
+	// 已经开始执行的组件不会被跳过
+	g, err = newGraph(&Rule{
+		StepNames: []string{"", "mid", "", "end"},
+		Components: Components{
+			{sw},
+			{{Name: "a", DependsOn: []string{}}, {Name: "b"}},
+			{{Name: "c"}},
+			{{Name: "d"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Start(graphNode(t, g, "2.a"))
+	g.Branch(graphNode(t, g, "1.sw"), "end", sw.BranchTargets())
+	for key, skip := range map[string]bool{"2.a": false, "2.b": true, "3.c": true, "4.d": false} {
+		if graphNode(t, g, key).skip != skip {
+			t.Errorf("%v skip = %v, want %v", key, !skip, skip)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package engine
+
+import (
+	"fmt"
+	"github.com/limeschool/gin"
+	"go.uber.org/zap"
+	"ps-go/consts"
+	"ps-go/errors"
+	"ps-go/model"
+	"ps-go/tools"
+	"ps-go/tools/lock"
+	"strings"
+	"time"
+)
+
+const (
+	IdempotentConflictWait   = "wait"   //等待执行中的请求完成之后返回其结果
+	IdempotentConflictReject = "reject" //立即返回冲突错误
+
+	idempotentPollInterval = 100 * time.Millisecond
+)
+
+// Idempotent 规则的幂等配置
+type Idempotent struct {
+	Key         string `json:"key"`         //幂等键 [body.xxx|query.xxx|header.xxx]，取值为空时不进行幂等控制
+	Expire      int    `json:"expire"`      //执行结果的保存时长/s，默认为86400
+	Conflict    string `json:"conflict"`    //相同幂等键的请求正在执行时的处理方式 [wait|reject]，默认为wait
+	WaitTimeout int    `json:"waitTimeout"` //等待执行中请求的最长时间/s，默认为30，超时之后返回冲突错误
+}
+
+// Idempotency 请求的幂等控制
+type Idempotency interface {
+	Begin() (*model.IdempotentRecord, error)
+	Finish(code int, responseType string, response any)
+	Accept()
+	Abort()
+	Forget(ctx *gin.Context)
+}
+
+type idempotency struct {
+	ctx    *gin.Context
+	conf   *Idempotent
+	key    string
+	trx    string
+	expire time.Duration
+	lock   lock.Lock
+	store  idempotentStore
+}
+
+// idempotentStore 幂等执行记录的存储
+type idempotentStore interface {
+	Save(ctx *gin.Context, key string, record *model.IdempotentRecord, expire time.Duration) error
+	One(ctx *gin.Context, key string) (*model.IdempotentRecord, error)
+	Delete(ctx *gin.Context, key string) error
+}
+
+// redisIdempotentStore 执行记录存储在redis中，多实例共享
+type redisIdempotentStore struct{}
+
+func (redisIdempotentStore) Save(ctx *gin.Context, key string, record *model.IdempotentRecord, expire time.Duration) error {
+	return record.Save(ctx, key, expire)
+}
+
+func (redisIdempotentStore) One(ctx *gin.Context, key string) (*model.IdempotentRecord, error) {
+	record := model.IdempotentRecord{}
+	return &record, record.OneByKey(ctx, key)
+}
+
+func (redisIdempotentStore) Delete(ctx *gin.Context, key string) error {
+	return (&model.IdempotentRecord{}).DeleteByKey(ctx, key)
+}
+
+// NewIdempotency 创建请求的幂等控制，规则未配置幂等或者幂等键取值为空时返回nil
+func (e engine) NewIdempotency(ctx *gin.Context, method, path string, rule *Rule, request map[string]any) Idempotency {
+	conf := rule.Idempotent
+	if conf == nil || conf.Key == "" {
+		return nil
+	}
+
+	value := conf.value(ctx, request)
+	if value == "" {
+		return nil
+	}
+
+	expire := time.Duration(conf.Expire) * time.Second
+	if expire <= 0 {
+		expire = consts.IdempotentExpire
+	}
+
+	key := fmt.Sprintf("%v:%v:%v", strings.ToUpper(method), path, value)
+	return &idempotency{
+		ctx:    ctx,
+		conf:   conf,
+		key:    key,
+		trx:    ctx.GetString(consts.ProcessScheduleTrx),
+		expire: expire,
+		lock:   lock.NewLockWithDuration(ctx, "idempotent_lock_"+key, rule.LeaseExpire()),
+		store:  redisIdempotentStore{},
+	}
+}
+
+// value 获取幂等键的值
+func (c *Idempotent) value(ctx *gin.Context, request map[string]any) string {
+	if name := strings.TrimPrefix(c.Key, "header."); name != c.Key {
+		return ctx.GetHeader(name)
+	}
+
+	value := tools.GetMapData(c.Key, request)
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// Begin 开始执行，返回的记录不为nil时表示重复请求，直接返回记录中的执行结果，异步执行时返回首次请求的trx。
+// 相同幂等键的请求正在执行时，按照conflict配置等待其执行完成或者直接返回冲突错误
+func (i *idempotency) Begin() (*model.IdempotentRecord, error) {
+	wait := time.Duration(i.conf.WaitTimeout) * time.Second
+	if wait <= 0 {
+		wait = consts.IdempotentWait
+	}
+	deadline := time.Now().Add(wait)
+
+	for {
+		if i.lock.TryAcquire() {
+			if record, err := i.store.One(i.ctx, i.key); err == nil && record.Replayable() {
+				i.lock.Release()
+				return record, nil
+			}
+
+			// 首次请求，或者之前的请求异常退出未完成
+			record := model.IdempotentRecord{Trx: i.trx, State: model.IdempotentRunning}
+			if err := i.store.Save(i.ctx, i.key, &record, i.expire); err != nil {
+				i.lock.Release()
+				return nil, err
+			}
+			return nil, nil
+		}
+
+		if i.conf.Conflict == IdempotentConflictReject || time.Now().After(deadline) {
+			return nil, errors.IdempotentError
+		}
+		time.Sleep(idempotentPollInterval)
+	}
+}
+
+// Finish 存储执行成功的结果，相同幂等键的请求直接返回该结果。流程中断或者执行失败时应调用Abort
+func (i *idempotency) Finish(code int, responseType string, response any) {
+	defer i.lock.Release()
+
+	record := model.IdempotentRecord{
+		Trx:          i.trx,
+		State:        model.IdempotentDone,
+		Code:         code,
+		ResponseType: responseType,
+		Response:     response,
+	}
+	if err := i.store.Save(i.ctx, i.key, &record, i.expire); err != nil {
+		i.ctx.Log.Error("幂等执行结果存储失败", zap.Any("trx", i.trx), zap.Any("err", err))
+	}
+}
+
+// Accept 异步执行已受理，只存储首次请求的trx，相同幂等键的请求通过trx查询执行进度
+func (i *idempotency) Accept() {
+	defer i.lock.Release()
+
+	record := model.IdempotentRecord{Trx: i.trx, State: model.IdempotentAccepted}
+	if err := i.store.Save(i.ctx, i.key, &record, i.expire); err != nil {
+		i.ctx.Log.Error("幂等执行记录存储失败", zap.Any("trx", i.trx), zap.Any("err", err))
+	}
+}
+
+// Abort 流程未执行、中断或者执行失败时放弃本次请求，相同幂等键的请求可以重新执行
+func (i *idempotency) Abort() {
+	defer i.lock.Release()
+
+	_ = i.store.Delete(i.ctx, i.key)
+}
+
+// Forget 异步流程执行失败之后删除受理记录，相同幂等键的请求可以重新执行。
+// 此时请求已经返回，需要使用流程自身的上下文
+func (i *idempotency) Forget(ctx *gin.Context) {
+	record, err := i.store.One(ctx, i.key)
+	if err != nil || record.Trx != i.trx {
+		return
+	}
+	if err := i.store.Delete(ctx, i.key); err != nil {
+		ctx.Log.Error("幂等执行记录删除失败", zap.Any("trx", i.trx), zap.Any("err", err))
+	}
+}
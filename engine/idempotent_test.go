@@ -0,0 +1,278 @@
+package engine
+
+import (
+	"github.com/limeschool/gin"
+	"go.uber.org/zap"
+	"net/http/httptest"
+	"ps-go/errors"
+	"ps-go/model"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryLock 进程内的锁，模拟相同幂等键的分布式锁
+type memoryLock struct {
+	mu   *sync.Mutex
+	held *bool
+}
+
+func (l memoryLock) Acquire() {
+	for !l.TryAcquire() {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func (l memoryLock) TryAcquire() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if *l.held {
+		return false
+	}
+	*l.held = true
+	return true
+}
+
+func (l memoryLock) Release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	*l.held = false
+}
+
+// memoryIdempotentStore 进程内的执行记录存储
+type memoryIdempotentStore struct {
+	mu      sync.Mutex
+	records map[string]model.IdempotentRecord
+	saveErr error
+}
+
+func (s *memoryIdempotentStore) Save(ctx *gin.Context, key string, record *model.IdempotentRecord, expire time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.saveErr != nil {
+		return s.saveErr
+	}
+	s.records[key] = *record
+	return nil
+}
+
+func (s *memoryIdempotentStore) One(ctx *gin.Context, key string) (*model.IdempotentRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[key]
+	if !ok {
+		return nil, errors.DBNotFoundError
+	}
+	return &record, nil
+}
+
+func (s *memoryIdempotentStore) Delete(ctx *gin.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+func (s *memoryIdempotentStore) get(key string) (model.IdempotentRecord, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[key]
+	return record, ok
+}
+
+// idempotentEnv 相同幂等键的请求共用锁以及执行记录
+type idempotentEnv struct {
+	mu    sync.Mutex
+	held  bool
+	store *memoryIdempotentStore
+}
+
+func newIdempotentEnv() *idempotentEnv {
+	return &idempotentEnv{store: &memoryIdempotentStore{records: map[string]model.IdempotentRecord{}}}
+}
+
+func (e *idempotentEnv) new(trx string, conf Idempotent) *idempotency {
+	return &idempotency{
+		ctx:    &gin.Context{Log: zap.NewNop()},
+		conf:   &conf,
+		key:    "POST:/test:1",
+		trx:    trx,
+		expire: time.Minute,
+		lock:   memoryLock{mu: &e.mu, held: &e.held},
+		store:  e.store,
+	}
+}
+
+func TestIdempotencyFinish(t *testing.T) {
+	env := newIdempotentEnv()
+	first := env.new("trx1", Idempotent{Conflict: IdempotentConflictReject})
+
+	record, err := first.Begin()
+	if err != nil || record != nil {
+		t.Fatalf("first Begin = %v, %v", record, err)
+	}
+	if r, _ := env.store.get(first.key); r.State != model.IdempotentRunning || r.Trx != "trx1" {
+		t.Fatalf("running record = %+v", r)
+	}
+
+	// 执行期间相同幂等键的请求直接返回冲突
+	if _, err = env.new("trx2", Idempotent{Conflict: IdempotentConflictReject}).Begin(); err != errors.IdempotentError {
+		t.Fatalf("conflict err = %v", err)
+	}
+
+	first.Finish(200, "json", map[string]any{"id": 1})
+	if env.held {
+		t.Fatal("lock not released after Finish")
+	}
+
+	// 执行完成之后直接返回首次请求的结果
+	record, err = env.new("trx3", Idempotent{}).Begin()
+	if err != nil || record == nil {
+		t.Fatalf("replay Begin = %v, %v", record, err)
+	}
+	if record.State != model.IdempotentDone || record.Trx != "trx1" || record.Code != 200 || record.ResponseType != "json" {
+		t.Fatalf("replay record = %+v", record)
+	}
+	if env.held {
+		t.Fatal("lock held after replay")
+	}
+}
+
+func TestIdempotencyAccept(t *testing.T) {
+	env := newIdempotentEnv()
+	first := env.new("trx1", Idempotent{})
+	if _, err := first.Begin(); err != nil {
+		t.Fatal(err)
+	}
+	first.Accept()
+
+	// 异步执行受理之后返回首次请求的trx
+	record, err := env.new("trx2", Idempotent{}).Begin()
+	if err != nil || record == nil || record.State != model.IdempotentAccepted || record.Trx != "trx1" {
+		t.Fatalf("replay Begin = %+v, %v", record, err)
+	}
+
+	// 只有首次请求可以删除受理记录
+	env.new("trx2", Idempotent{}).Forget(&gin.Context{Log: zap.NewNop()})
+	if _, ok := env.store.get(first.key); !ok {
+		t.Fatal("record deleted by other trx")
+	}
+	first.Forget(&gin.Context{Log: zap.NewNop()})
+	if _, ok := env.store.get(first.key); ok {
+		t.Fatal("record not deleted by Forget")
+	}
+
+	// 删除之后相同幂等键的请求重新执行
+	if record, err = env.new("trx3", Idempotent{}).Begin(); err != nil || record != nil {
+		t.Fatalf("Begin after Forget = %v, %v", record, err)
+	}
+}
+
+func TestIdempotencyAbort(t *testing.T) {
+	env := newIdempotentEnv()
+	first := env.new("trx1", Idempotent{})
+	if _, err := first.Begin(); err != nil {
+		t.Fatal(err)
+	}
+	first.Abort()
+
+	if _, ok := env.store.get(first.key); ok || env.held {
+		t.Fatalf("record kept = %v, lock held = %v", ok, env.held)
+	}
+	if record, err := env.new("trx2", Idempotent{}).Begin(); err != nil || record != nil {
+		t.Fatalf("Begin after Abort = %v, %v", record, err)
+	}
+}
+
+func TestIdempotencyWait(t *testing.T) {
+	env := newIdempotentEnv()
+	first := env.new("trx1", Idempotent{})
+	if _, err := first.Begin(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 等待执行中的请求完成之后返回其结果
+	time.AfterFunc(150*time.Millisecond, func() {
+		first.Finish(200, "json", "ok")
+	})
+	record, err := env.new("trx2", Idempotent{WaitTimeout: 5}).Begin()
+	if err != nil || record == nil || record.Trx != "trx1" || record.Response != "ok" {
+		t.Fatalf("wait Begin = %+v, %v", record, err)
+	}
+}
+
+func TestIdempotencyWaitTimeout(t *testing.T) {
+	env := newIdempotentEnv()
+	if _, err := env.new("trx1", Idempotent{}).Begin(); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if _, err := env.new("trx2", Idempotent{WaitTimeout: 1}).Begin(); err != errors.IdempotentError {
+		t.Fatalf("err = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Fatalf("returned after %v, want at least waitTimeout", elapsed)
+	}
+}
+
+func TestIdempotencyRecoverRunning(t *testing.T) {
+	env := newIdempotentEnv()
+	// 之前的请求异常退出，只留下执行中的记录，锁已经过期
+	env.store.records["POST:/test:1"] = model.IdempotentRecord{Trx: "trx1", State: model.IdempotentRunning}
+
+	retry := env.new("trx2", Idempotent{})
+	if record, err := retry.Begin(); err != nil || record != nil {
+		t.Fatalf("Begin = %v, %v", record, err)
+	}
+	if r, _ := env.store.get(retry.key); r.Trx != "trx2" {
+		t.Fatalf("record = %+v", r)
+	}
+}
+
+func TestIdempotencySaveError(t *testing.T) {
+	env := newIdempotentEnv()
+	env.store.saveErr = errors.New("redis unavailable")
+
+	// 记录存储失败时返回错误，并释放锁
+	if _, err := env.new("trx1", Idempotent{}).Begin(); err == nil {
+		t.Fatal("expected error")
+	}
+	if env.held {
+		t.Fatal("lock held after save error")
+	}
+
+	// 结果存储失败时同样释放锁
+	env.store.saveErr = nil
+	first := env.new("trx1", Idempotent{})
+	if _, err := first.Begin(); err != nil {
+		t.Fatal(err)
+	}
+	env.store.saveErr = errors.New("redis unavailable")
+	first.Finish(200, "json", "ok")
+	if env.held {
+		t.Fatal("lock held after Finish error")
+	}
+}
+
+func TestIdempotentValue(t *testing.T) {
+	request := map[string]any{
+		"body":  map[string]any{"order": map[string]any{"id": 10}},
+		"query": map[string]any{"token": "abc"},
+	}
+	ctx := &gin.Context{Request: httptest.NewRequest("POST", "/test", nil)}
+	ctx.Request.Header.Set("Idempotency-Key", "k1")
+
+	cases := map[string]string{
+		"body.order.id":          "10",
+		"query.token":            "abc",
+		"header.Idempotency-Key": "k1",
+		"body.missing":           "",
+		"header.missing":         "",
+	}
+	for key, want := range cases {
+		if got := (&Idempotent{Key: key}).value(ctx, request); got != want {
+			t.Errorf("value(%v) = %q, want %q", key, got, want)
+		}
+	}
+}
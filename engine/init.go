@@ -3,6 +3,7 @@ package engine
 import (
 	"github.com/limeschool/gin"
 	"ps-go/consts"
+	"ps-go/tools"
 	"sync"
 )
 
@@ -12,6 +13,11 @@ type Engine interface {
 	NewRunner(*gin.Context, *Rule, RunStore) Runner
 	NewRunStore() RunStore
 	NewRunStoreByData(data map[string]any) RunStore
+	AcquireLimit(ctx *gin.Context, method, path string, rule *Rule) (func(), *LimitReject)
+	NewIdempotency(ctx *gin.Context, method, path string, rule *Rule, request map[string]any) Idempotency
+	LintRule(ctx *gin.Context, rule string) []Diagnostic
+	LintRuleWithScripts(ctx *gin.Context, rule string, scripts map[string]string) []Diagnostic
+	Driver() string
 }
 
 var eg *engine
@@ -59,15 +65,19 @@ func (e engine) NewRunner(ctx *gin.Context, rule *Rule, rStore RunStore) Runner
 
 	var copyRule = new(Rule)
 	*copyRule = *rule
-	run.trx = ctx.Writer.Header().Get(consts.ProcessScheduleTrx)
+	run.trx = ctx.GetString(consts.ProcessScheduleTrx)
+	if run.trx == "" {
+		run.trx = ctx.Writer.Header().Get(consts.ProcessScheduleTrx)
+	}
 	run.version = rule.Version
 	run.rule = rule
 	run.copyRule = copyRule
 	run.count = len(rule.Components)
-	run.index = 0
 	run.curIndex = 0
 	run.runStore = rStore
 	run.wg = &sync.WaitGroup{}
+	run.done = make(chan struct{})
+	run.respDone = make(chan struct{})
 	run.store = e.Store
 	run.response = &responseChan{
 		response: make(chan map[string]any),
@@ -82,11 +92,17 @@ func (e engine) NewRunner(ctx *gin.Context, rule *Rule, rStore RunStore) Runner
 	return run
 }
 
-// Init 初始化调度引擎
+// Init 初始化调度引擎，需要在全局配置初始化之后调用
 func Init() {
+	ctx := tools.NewBackgroundContext()
+	st, err := NewStoreByConfig(ctx)
+	if err != nil {
+		panic("调度引擎存储器初始化失败：" + err.Error())
+	}
 	eg = &engine{
-		Store: NewStore(),
+		Store: st,
 	}
+	go lruStore.listenPurge(ctx)
 }
 
 // Get 获取调度引擎实例
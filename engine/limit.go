@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"fmt"
+	json "github.com/json-iterator/go"
+	"github.com/limeschool/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"net/http"
+	"ps-go/consts"
+	"ps-go/errors"
+	"ps-go/model"
+	"ps-go/tools"
+	"ps-go/tools/limiter"
+	"strings"
+)
+
+const (
+	LimitKeyGlobal = "global" //所有请求共用限额
+	LimitKeyIP     = "ip"     //按照客户端ip区分限额
+	LimitKeyHeader = "header" //按照请求头区分限额，格式为header.X-User-Id
+)
+
+// Limit 规则的限流配置
+type Limit struct {
+	RateLimit      *RateLimit   `json:"rateLimit,omitempty"` //令牌桶限流
+	MaxConcurrency int          `json:"maxConcurrency"`      //最大同时执行的流程数量，0为不限制
+	Key            string       `json:"key"`                 //限额维度 [global|ip|header.xxx]，默认为global
+	Reject         *LimitReject `json:"reject,omitempty"`    //被限流时的返回信息
+}
+
+type RateLimit struct {
+	Rate  float64 `json:"rate"`  //每秒允许的请求数量
+	Burst int     `json:"burst"` //允许的突发请求数量，默认与rate相同
+}
+
+type LimitReject struct {
+	Status int               `json:"status"` //http状态码，默认为429
+	Header map[string]string `json:"header"` //返回header
+	Body   any               `json:"body"`   //返回数据，默认为错误码以及错误信息
+}
+
+// LoadLimit 获取规则的限流配置，存在单独设置的限流配置时优先使用
+func (s *store) LoadLimit(ctx *gin.Context, method, path string, rule *Rule) (*Limit, error) {
+	rl := model.RuleLimit{}
+	if err := rl.OneByNameMethod(ctx, path, method); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return rule.Limit, nil
+		}
+		return nil, err
+	}
+
+	limit := &Limit{}
+	return limit, json.UnmarshalFromString(rl.Config, limit)
+}
+
+// AcquireLimit 按照限流配置获取执行许可，被限流时返回拒绝信息。
+// 获取成功之后返回的release需要在流程执行完成之后调用，用于释放并发许可
+func (e engine) AcquireLimit(ctx *gin.Context, method, path string, rule *Rule) (func(), *LimitReject) {
+	limit, err := e.LoadLimit(ctx, method, path, rule)
+	if err != nil {
+		ctx.Log.Error("加载限流配置失败", zap.Any("path", path), zap.Any("err", err))
+		limit = rule.Limit
+	}
+	if limit == nil {
+		return func() {}, nil
+	}
+
+	key := fmt.Sprintf("%v:%v:%v", strings.ToUpper(method), path, limit.keyValue(ctx))
+
+	if rate := limit.RateLimit; rate != nil && rate.Rate > 0 {
+		if !limiter.AllowRate(ctx, key, rate.Rate, rate.Burst) {
+			return nil, limit.reject(errors.RateLimitError)
+		}
+	}
+
+	if limit.MaxConcurrency <= 0 {
+		return func() {}, nil
+	}
+
+	id := ctx.GetString(consts.ProcessScheduleTrx)
+	if !limiter.Acquire(ctx, key, id, limit.MaxConcurrency, rule.LeaseExpire()) {
+		return nil, limit.reject(errors.ConcurrencyLimitError)
+	}
+
+	// 异步执行时请求上下文可能已经结束，使用独立的上下文释放
+	bg := tools.CopyContext(ctx)
+	return func() {
+		limiter.Release(bg, key, id)
+	}, nil
+}
+
+// keyValue 获取限额维度的值
+func (l *Limit) keyValue(ctx *gin.Context) string {
+	switch {
+	case l.Key == LimitKeyIP:
+		return ctx.ClientIP()
+	case strings.HasPrefix(l.Key, LimitKeyHeader+"."):
+		return ctx.GetHeader(strings.TrimPrefix(l.Key, LimitKeyHeader+"."))
+	default:
+		return LimitKeyGlobal
+	}
+}
+
+// reject 获取被限流时的返回信息
+func (l *Limit) reject(err *gin.CustomError) *LimitReject {
+	reject := LimitReject{}
+	if l.Reject != nil {
+		reject = *l.Reject
+	}
+	if reject.Status == 0 {
+		reject.Status = http.StatusTooManyRequests
+	}
+	if reject.Body == nil {
+		reject.Body = gin.H{"code": err.Code, "msg": err.Msg}
+	}
+	return &reject
+}
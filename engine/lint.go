@@ -0,0 +1,382 @@
+package engine
+
+import (
+	"fmt"
+	json "github.com/json-iterator/go"
+	"github.com/limeschool/gin"
+	"github.com/robertkrimen/otto/parser"
+	"ps-go/consts"
+	"ps-go/errors"
+	"ps-go/model"
+	"ps-go/tools"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	LintLevelError   = "error"   //规则无法正常执行，保存规则时拒绝
+	LintLevelWarning = "warning" //规则可能存在问题，不影响保存
+)
+
+var (
+	referenceReg = regexp.MustCompile(`\{(\w|\.)+}`)
+
+	fieldTypes     = []string{Int, Float, String, Slice, Bool, Map}
+	componentTypes = []string{ComponentTypeApi, ComponentTypeScript, ComponentTypeSwitch,
+		ComponentTypeForeach, ComponentTypeRule, ComponentTypeWait}
+	requestTypes  = []string{"", consts.RespJson, consts.RespXml}
+	responseTypes = []string{"", consts.RespJson, consts.RespXml, consts.RespText}
+	notifyEvents  = []string{NotifyEventSuccess, NotifyEventBreak, NotifyEventSuspend, NotifyEventWait}
+
+	// 流程中始终可以引用的数据
+	globalReferences = []string{"request", "global_store"}
+)
+
+// Diagnostic 规则校验发现的问题
+type Diagnostic struct {
+	Path    string `json:"path"`    //问题所在的json路径，组件统一按照分层格式定位，如 $.components[0][1].url
+	Level   string `json:"level"`   //问题级别 [error|warning]
+	Message string `json:"message"` //问题描述
+}
+
+type linter struct {
+	ctx         *gin.Context
+	store       Store
+	rule        *Rule
+	diagnostics []Diagnostic
+}
+
+// LintRule 对规则进行静态校验，返回发现的全部问题
+func (e engine) LintRule(ctx *gin.Context, data string) []Diagnostic {
+	return lintRule(ctx, e.manageStore(), data)
+}
+
+func lintRule(ctx *gin.Context, store Store, data string) []Diagnostic {
+	l := &linter{ctx: ctx, store: store, diagnostics: make([]Diagnostic, 0)}
+
+	rule := Rule{}
+	if err := json.UnmarshalFromString(data, &rule); err != nil {
+		l.error("$", "规则格式错误：%v", err.Error())
+		return l.diagnostics
+	}
+	l.rule = &rule
+
+	l.lintRequest()
+	l.lintComponents()
+	l.lintResponse()
+	l.lintNotify()
+	return l.diagnostics
+}
+
+// LintError 将校验结果中的错误转换为error，不存在错误时返回nil
+func LintError(list []Diagnostic) error {
+	var msg []string
+	for _, item := range list {
+		if item.Level == LintLevelError {
+			msg = append(msg, fmt.Sprintf("%v %v", item.Path, item.Message))
+		}
+	}
+	if len(msg) == 0 {
+		return nil
+	}
+	return errors.NewF("规则校验失败：%v", strings.Join(msg, "；"))
+}
+
+func (l *linter) error(path, format string, args ...any) {
+	l.diagnostics = append(l.diagnostics, Diagnostic{Path: path, Level: LintLevelError, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *linter) warning(path, format string, args ...any) {
+	l.diagnostics = append(l.diagnostics, Diagnostic{Path: path, Level: LintLevelWarning, Message: fmt.Sprintf(format, args...)})
+}
+
+// lintRequest 校验请求数据类型以及字段规则
+func (l *linter) lintRequest() {
+	req := l.rule.Request
+	if !tools.InList(requestTypes, req.Type) {
+		l.error("$.request.type", "不支持的请求数据类型%v，可选值为json、xml", req.Type)
+	}
+	l.lintFields("$.request.query", req.Query)
+	l.lintFields("$.request.header", req.Header)
+	l.lintFields("$.request.body", req.Body)
+}
+
+func (l *linter) lintFields(path string, fields map[string]FieldRule) {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field := fields[key]
+		fieldPath := fmt.Sprintf("%v.%v", path, key)
+		if !tools.InList(fieldTypes, field.Type) {
+			l.error(fieldPath+".type", "不支持的字段类型%v，可选值为%v", field.Type, strings.Join(fieldTypes, "、"))
+		}
+		if len(field.Attribute) != 0 {
+			if field.Type != Map {
+				l.warning(fieldPath+".attribute", "attribute仅%v类型生效", Map)
+			}
+			l.lintFields(fieldPath+".attribute", field.Attribute)
+		}
+	}
+}
+
+// lintComponents 校验组件配置以及组件之间的依赖关系
+func (l *linter) lintComponents() {
+	if len(l.rule.Components) == 0 {
+		l.error("$.components", "流程至少需要一个组件")
+		return
+	}
+
+	if _, err := newGraph(l.rule); err != nil {
+		l.error("$.components", "%v", err.Error())
+	}
+
+	for step, list := range l.rule.Components {
+		names := map[string]bool{}
+		for action, com := range list {
+			path := fmt.Sprintf("$.components[%v][%v]", step, action)
+			if com.Name == "" {
+				l.error(path+".name", "组件名不能为空")
+			} else if names[com.Name] {
+				l.error(path+".name", "同一层中组件名%v重复", com.Name)
+			}
+			names[com.Name] = true
+
+			l.lintComponent(path, com, l.scope(step, action), componentTypes)
+		}
+	}
+}
+
+// lintComponent 校验单个组件，scope为组件中可以引用的数据名
+func (l *linter) lintComponent(path string, com Component, scope []string, types []string) {
+	if com.Type != "" && !tools.InList(types, com.Type) {
+		l.error(path+".type", "不支持的组件类型%v，可选值为%v", com.Type, strings.Join(types, "、"))
+		return
+	}
+
+	l.lintCondition(path+".condition", com.Condition)
+	l.lintReferences(path+".condition", com.Condition, scope)
+	l.lintReferences(path+".input", com.Input, scope)
+	if com.Cache != nil {
+		l.lintReferences(path+".cache.key", com.Cache.Key, scope)
+	}
+
+	switch com.Type {
+	case ComponentTypeApi:
+		if com.Url == "" {
+			l.error(path+".url", "api组件的url不能为空")
+		}
+		if !tools.InList(responseTypes, com.RequestType) {
+			l.error(path+".requestType", "不支持的请求数据类型%v", com.RequestType)
+		}
+		if !tools.InList(responseTypes, com.ResponseType) {
+			l.error(path+".responseType", "不支持的返回数据类型%v", com.ResponseType)
+		}
+		if com.Tls != nil {
+			l.lintSecret(path+".tls.ca", com.Tls.Ca)
+			l.lintSecret(path+".tls.key", com.Tls.Key)
+		}
+		// 返回判断条件取值于接口的返回数据，不校验引用
+		l.lintCondition(path+".responseCondition", com.ResponseCondition)
+		l.lintReferences(path+".header", com.Header, scope)
+		l.lintReferences(path+".auth", com.Auth, scope)
+
+	case ComponentTypeSwitch:
+		for index, item := range com.Cases {
+			casePath := fmt.Sprintf("%v.cases[%v].condition", path, index)
+			l.lintCondition(casePath, item.Condition)
+			l.lintReferences(casePath, item.Condition, scope)
+		}
+
+	case ComponentTypeForeach:
+		if com.Items == "" {
+			l.error(path+".items", "foreach组件的items不能为空")
+		}
+		l.lintReferences(path+".items", com.Items, scope)
+		if com.Component == nil {
+			l.error(path+".component", "foreach组件需要设置每一项执行的组件")
+		} else {
+			itemScope := append([]string{foreachItemKey, foreachIndexKey}, scope...)
+			l.lintComponent(path+".component", *com.Component, itemScope,
+				[]string{ComponentTypeApi, ComponentTypeScript, ComponentTypeRule})
+		}
+
+	case ComponentTypeRule:
+		if com.Url == "" {
+			l.error(path+".url", "子流程组件的url不能为空")
+			break
+		}
+		method := com.Method
+		if method == "" {
+			method = "POST"
+		}
+		if _, err := l.store.LoadRule(l.ctx, method, strings.TrimLeft(com.Url, "/")); err != nil {
+			l.warning(path+".url", "子流程%v当前不存在", RuleKey(method, com.Url))
+		}
+
+	case ComponentTypeWait:
+
+	default:
+		if com.Url == "" {
+			l.error(path+".url", "脚本组件的url不能为空")
+			break
+		}
+		if _, _, err := l.store.LoadScript(l.ctx, com.Url); err != nil {
+			l.error(path+".url", "脚本%v不存在", com.Url)
+		}
+	}
+
+	if com.Compensate != nil {
+		compensateScope := append([]string{compensateOutputKey}, scope...)
+		l.lintComponent(path+".compensate", *com.Compensate, compensateScope,
+			[]string{ComponentTypeApi, ComponentTypeScript})
+	}
+}
+
+// lintResponse 校验返回配置
+func (l *linter) lintResponse() {
+	resp := l.rule.Response
+	if !tools.InList(responseTypes, resp.Type) {
+		l.error("$.response.type", "不支持的返回数据类型%v，可选值为json、xml、text", resp.Type)
+	}
+
+	scope := append([]string{consts.PSResponseKey}, l.outputs(nil)...)
+	l.lintReferences("$.response.body", resp.Body, scope)
+	l.lintReferences("$.response.header", resp.Header, scope)
+}
+
+// lintNotify 校验回调通知配置
+func (l *linter) lintNotify() {
+	scope := append([]string{"notify"}, l.outputs(nil)...)
+	for index, item := range l.rule.Notify {
+		path := fmt.Sprintf("$.notify[%v]", index)
+		if item.Url == "" {
+			l.error(path+".url", "回调地址不能为空")
+		}
+		for i, event := range item.Events {
+			if !tools.InList(notifyEvents, event) {
+				l.error(fmt.Sprintf("%v.events[%v]", path, i), "不支持的通知事件%v，可选值为%v", event, strings.Join(notifyEvents, "、"))
+			}
+		}
+		l.lintSecret(path+".secret", item.Secret)
+		if item.Secret != "" && item.method() == "GET" {
+			l.error(path+".method", "设置了签名密钥的回调通知不支持GET请求，签名需要覆盖请求body")
+		}
+		l.lintReferences(path+".header", item.Header, scope)
+		l.lintReferences(path+".body", item.Body, scope)
+	}
+}
+
+// lintCondition 校验表达式语法，表达式中的变量替换为占位变量之后按照js语法解析
+func (l *linter) lintCondition(path, cond string) {
+	if strings.TrimSpace(cond) == "" {
+		return
+	}
+
+	script := fmt.Sprintf("function condition(){return %v;}", referenceReg.ReplaceAllString(cond, "a"))
+	if _, err := parser.ParseFile(nil, "", script, 0); err != nil {
+		l.error(path, "表达式语法错误：%v", err.Error())
+	}
+}
+
+// lintReferences 校验数据中的变量引用，只能引用scope中的数据
+func (l *linter) lintReferences(path string, data any, scope []string) {
+	switch value := data.(type) {
+	case string:
+		for _, ref := range referenceReg.FindAllString(value, -1) {
+			name := strings.Split(ref[1:len(ref)-1], ".")[0]
+			if !tools.InList(scope, name) {
+				l.error(path, "引用的数据%v不存在，只能引用request或者依赖的组件的输出", ref)
+			}
+		}
+	case []any:
+		for index, item := range value {
+			l.lintReferences(fmt.Sprintf("%v[%v]", path, index), item, scope)
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			l.lintReferences(fmt.Sprintf("%v.%v", path, key), value[key], scope)
+		}
+	}
+}
+
+// lintSecret 校验密钥是否存在
+func (l *linter) lintSecret(path, name string) {
+	if name == "" {
+		return
+	}
+	secret := model.Secret{}
+	if err := secret.OneByName(l.ctx, name); err != nil {
+		l.error(path, "密钥%v不存在", name)
+	}
+}
+
+// scope 获取组件中可以引用的数据名，包括全局数据以及全部上游组件的输出
+func (l *linter) scope(step, action int) []string {
+	visited := map[[2]int]bool{}
+	var walk func(step, action int)
+	walk = func(step, action int) {
+		for _, dep := range l.deps(step, action) {
+			if !visited[dep] {
+				visited[dep] = true
+				walk(dep[0], dep[1])
+			}
+		}
+	}
+	walk(step, action)
+	return l.outputs(visited)
+}
+
+// deps 获取组件直接依赖的组件位置，与依赖图的建立规则保持一致
+func (l *linter) deps(step, action int) [][2]int {
+	components := l.rule.Components
+	com := components[step][action]
+
+	var list [][2]int
+	if com.DependsOn == nil {
+		for prev := step - 1; prev >= 0; prev-- {
+			if len(components[prev]) == 0 {
+				continue
+			}
+			for index := range components[prev] {
+				list = append(list, [2]int{prev, index})
+			}
+			break
+		}
+		return list
+	}
+
+	for _, name := range com.DependsOn {
+		for s, items := range components {
+			for a, item := range items {
+				if item.Name == name {
+					list = append(list, [2]int{s, a})
+				}
+			}
+		}
+	}
+	return list
+}
+
+// outputs 获取组件的输出名以及全局数据名，nodes为nil时返回全部组件的输出
+func (l *linter) outputs(nodes map[[2]int]bool) []string {
+	list := append([]string{}, globalReferences...)
+	for step, items := range l.rule.Components {
+		for action, item := range items {
+			if item.OutputName == "" || (nodes != nil && !nodes[[2]int{step, action}]) {
+				continue
+			}
+			list = append(list, item.OutputName)
+		}
+	}
+	return list
+}
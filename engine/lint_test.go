@@ -0,0 +1,196 @@
+package engine
+
+import (
+	json "github.com/json-iterator/go"
+	"github.com/limeschool/gin"
+	"ps-go/errors"
+	"strings"
+	"testing"
+)
+
+// lintStore 校验规则时使用的存储器，只包含指定的脚本以及子流程
+type lintStore struct {
+	Store
+	scripts []string
+	rules   []string
+}
+
+func (s *lintStore) LoadScript(ctx *gin.Context, name string) (string, string, error) {
+	for _, item := range s.scripts {
+		if item == name {
+			return "", "", nil
+		}
+	}
+	return "", "", errors.New("not found")
+}
+
+func (s *lintStore) LoadRule(ctx *gin.Context, method, path string) (*Rule, error) {
+	for _, item := range s.rules {
+		if item == RuleKey(method, path) {
+			return &Rule{}, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func lint(t *testing.T, rule *Rule) map[string]string {
+	t.Helper()
+	data, err := json.MarshalToString(rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	levels := map[string]string{}
+	for _, item := range lintRule(nil, &lintStore{scripts: []string{"s.js"}, rules: []string{"POST:sub"}}, data) {
+		levels[item.Path] = item.Level
+	}
+	return levels
+}
+
+func scriptComponent(name, output string, input any) Component {
+	return Component{Name: name, Type: ComponentTypeScript, Url: "s.js", OutputName: output, Input: input}
+}
+
+func TestLintScope(t *testing.T) {
+	c := scriptComponent("c", "", map[string]any{
+		"a": "{a_out.id}",       // 上一层的组件
+		"b": "{b_out.id}",       // 同一层的组件
+		"r": "{request.body.x}", // 全局数据
+		"g": "{global_store.x}",
+	})
+	d := scriptComponent("d", "", map[string]any{"a": "{a_out.id}"}) // 上游组件的上游组件
+	e := scriptComponent("e", "", map[string]any{"a": "{a_out.id}"}) // 设置了dependsOn时只能引用依赖的组件
+	e.DependsOn = []string{"x"}
+	f := scriptComponent("f", "", map[string]any{"c": "{c_out.id}"})
+	f.DependsOn = []string{"c2"}
+
+	levels := lint(t, &Rule{Components: Components{
+		{scriptComponent("a", "a_out", nil), scriptComponent("x", "", nil)},
+		{c, scriptComponent("b", "b_out", nil), scriptComponent("c2", "c_out", map[string]any{"b": "{b_out.id}"})},
+		{d, e, f},
+	}})
+
+	want := map[string]string{
+		"$.components[1][0].input.b": LintLevelError,
+		"$.components[1][2].input.b": LintLevelError,
+		"$.components[2][1].input.a": LintLevelError,
+	}
+	for path, level := range want {
+		if levels[path] != level {
+			t.Errorf("%v = %q, want %q", path, levels[path], level)
+		}
+	}
+	for _, path := range []string{
+		"$.components[1][0].input.a", "$.components[1][0].input.r", "$.components[1][0].input.g",
+		"$.components[2][0].input.a", "$.components[2][2].input.c",
+	} {
+		if levels[path] != "" {
+			t.Errorf("%v = %q, want no diagnostic", path, levels[path])
+		}
+	}
+}
+
+func TestLintNestedScope(t *testing.T) {
+	each := Component{Name: "each", Type: ComponentTypeForeach, Items: "{request.body.ids}",
+		Component: ptr(scriptComponent("item", "", map[string]any{"id": "{item}", "i": "{index}", "o": "{output}"}))}
+	comp := scriptComponent("comp", "", map[string]any{"o": "{output}", "i": "{item}"})
+	withCompensate := scriptComponent("main", "main_out", map[string]any{"i": "{item}"})
+	withCompensate.Compensate = &comp
+
+	levels := lint(t, &Rule{
+		Components: Components{{each, withCompensate}},
+		Response:   Response{Body: map[string]any{"a": "{main_out}", "r": "{response.x}", "x": "{notify.x}"}},
+		Notify:     []Notify{{Url: "http://127.0.0.1", Body: map[string]any{"n": "{notify.trx}", "a": "{main_out}"}}},
+	})
+
+	// foreach中可以引用{item}、{index}，补偿组件中可以引用{output}，其他位置不能引用
+	for path, level := range map[string]string{
+		"$.components[0][0].component.input.id": "",
+		"$.components[0][0].component.input.i":  "",
+		"$.components[0][0].component.input.o":  LintLevelError,
+		"$.components[0][1].compensate.input.o": "",
+		"$.components[0][1].compensate.input.i": LintLevelError,
+		"$.components[0][1].input.i":            LintLevelError,
+		"$.response.body.a":                     "",
+		"$.response.body.r":                     "",
+		"$.response.body.x":                     LintLevelError,
+		"$.notify[0].body.n":                    "",
+		"$.notify[0].body.a":                    "",
+	} {
+		if levels[path] != level {
+			t.Errorf("%v = %q, want %q", path, levels[path], level)
+		}
+	}
+}
+
+func TestLintComponent(t *testing.T) {
+	sw := Component{Name: "sw", Type: ComponentTypeSwitch, Cases: []Case{{Condition: "{request.a} == ", Target: "x"}}}
+	levels := lint(t, &Rule{
+		Request: Request{Type: "form", Body: map[string]FieldRule{"a": {Type: "number"}}},
+		Components: Components{{
+			{Name: "api", Type: ComponentTypeApi, ResponseType: "html"},
+			{Name: "bad", Type: "unknown"},
+			{Name: "missing", Type: ComponentTypeScript, Url: "missing.js"},
+			{Name: "sub", Type: ComponentTypeRule, Url: "/missing"},
+			{Name: "sub2", Type: ComponentTypeRule, Url: "/sub"},
+			{Name: "cond", Type: ComponentTypeScript, Url: "s.js", Condition: "{request.a} > 1 &&"},
+			{Name: "api", Type: ComponentTypeApi, Url: "http://127.0.0.1"},
+			{Name: "each", Type: ComponentTypeForeach},
+			sw,
+		}},
+		Notify: []Notify{{Events: []string{"unknown"}}},
+	})
+
+	for path, level := range map[string]string{
+		"$.request.type":                        LintLevelError,
+		"$.request.body.a.type":                 LintLevelError,
+		"$.components[0][0].url":                LintLevelError,
+		"$.components[0][0].responseType":       LintLevelError,
+		"$.components[0][1].type":               LintLevelError,
+		"$.components[0][2].url":                LintLevelError,
+		"$.components[0][3].url":                LintLevelWarning,
+		"$.components[0][4].url":                "",
+		"$.components[0][5].condition":          LintLevelError,
+		"$.components[0][6].name":               LintLevelError,
+		"$.components[0][7].items":              LintLevelError,
+		"$.components[0][7].component":          LintLevelError,
+		"$.components[0][8].cases[0].condition": LintLevelError,
+		"$.components":                          LintLevelError,
+		"$.notify[0].url":                       LintLevelError,
+		"$.notify[0].events[0]":                 LintLevelError,
+	} {
+		if levels[path] != level {
+			t.Errorf("%v = %q, want %q", path, levels[path], level)
+		}
+	}
+}
+
+func TestLintError(t *testing.T) {
+	data, _ := json.MarshalToString(&Rule{Components: Components{{
+		{Name: "sub", Type: ComponentTypeRule, Url: "/missing"},
+	}}})
+	list := lintRule(nil, &lintStore{}, data)
+	if len(list) != 1 || list[0].Level != LintLevelWarning {
+		t.Fatalf("diagnostics = %+v", list)
+	}
+	// 只有警告时允许保存
+	if err := LintError(list); err != nil {
+		t.Fatalf("LintError = %v", err)
+	}
+
+	list = lintRule(nil, &lintStore{}, "{")
+	if len(list) != 1 || list[0].Path != "$" {
+		t.Fatalf("diagnostics = %+v", list)
+	}
+	if err := LintError(list); err == nil || !strings.Contains(err.Error(), "规则格式错误") {
+		t.Fatalf("LintError = %v", err)
+	}
+
+	if list = lintRule(nil, &lintStore{}, "{}"); len(list) != 1 || list[0].Path != "$.components" {
+		t.Fatalf("empty components = %+v", list)
+	}
+}
+
+func ptr(c Component) *Component {
+	return &c
+}
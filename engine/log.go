@@ -22,8 +22,11 @@ type Logger interface {
 	Get() any
 	SetStartTime(time.Time)
 	GetStepLog(int) StepLog
+	GetComponentErrorNames() []string
 	SetVersion(version string)
 	GetStatus() string
+	NewCompensateLog() ComponentLog
+	SetCompensateStatus(status string)
 }
 
 type StepLog interface {
@@ -46,7 +49,20 @@ type ComponentLog interface {
 	SetStep(step int)
 	SetAction(c int)
 	SetSkip(is bool)
+	SetBranch(target string)
+	SetWait(is bool)
+	SetFallback(is bool)
 	SetOutputData(data any)
+	NewChildLog() ComponentLog
+	SetRunLog(log any)
+	NewAttemptLog(attempt int) AttemptLog
+}
+
+type AttemptLog interface {
+	SetRunTime(t time.Time)
+	SetStatusCode(code int)
+	SetError(err error)
+	SetWait(wait time.Duration)
 }
 
 type RequestLog interface {
@@ -74,6 +90,9 @@ type runLog struct {
 
 	StepLogs []*stepLog `json:"step_logs"` //层级日志
 
+	Compensations    []*componentLog `json:"compensations,omitempty"`     //补偿组件日志，按执行顺序排列
+	CompensateStatus string          `json:"compensate_status,omitempty"` //补偿状态
+
 	Error   string `json:"error,omitempty"` //错误原因
 	Status  string `json:"status"`          //运行状态 中断/错误挂起/主动挂起/成功
 	CurStep int    `json:"cur_step"`        //当前执行步数
@@ -97,6 +116,19 @@ func (r *runLog) NewStepLog(step, ac int) StepLog {
 	return &log
 }
 
+// NewCompensateLog 创建补偿组件日志
+func (r *runLog) NewCompensateLog() ComponentLog {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	log := componentLog{}
+	r.Compensations = append(r.Compensations, &log)
+	return &log
+}
+
+func (r *runLog) SetCompensateStatus(status string) {
+	r.CompensateStatus = status
+}
+
 func (r *runLog) SetRequest(data any) {
 	r.Request = data
 }
@@ -154,12 +186,25 @@ func (r *runLog) SetVersion(v string) {
 }
 
 func (r *runLog) GetStepLog(index int) StepLog {
-	if index > len(r.StepLogs) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if index < 0 || index >= len(r.StepLogs) {
 		return nil
 	}
 	return r.StepLogs[index]
 }
 
+// GetComponentErrorNames 获取所有层中执行错误的组件名
+func (r *runLog) GetComponentErrorNames() []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	var arr []string
+	for _, step := range r.StepLogs {
+		arr = append(arr, step.GetComponentErrorNames()...)
+	}
+	return arr
+}
+
 type stepLog struct {
 	lock sync.RWMutex
 
@@ -193,6 +238,8 @@ func (s *stepLog) GetComponentLog(index int) ComponentLog {
 }
 
 func (s *stepLog) GetComponentErrorNames() []string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 	var arr []string
 	for _, com := range s.ComponentLogs {
 		if com.Error != "" {
@@ -227,14 +274,18 @@ type componentLog struct {
 	StartDatetime string `json:"start_datetime"`
 	EndDatetime   string `json:"end_datetime"`
 
-	Input      any    `json:"input"`       //输入数据
-	Name       string `json:"name"`        //组件名
-	Desc       string `json:"desc"`        //组件描述
-	Type       string `json:"type"`        //组件类型 [api|script]
-	Url        string `json:"url"`         //地址
-	OutputName string `json:"output_name"` //输出对象名
-	IsCache    bool   `json:"is_cache"`    //是否启用缓存
-	IsSkip     bool   `json:"is_skip"`     //是否进入执行
+	Input      any      `json:"input"`                //输入数据
+	Name       string   `json:"name"`                 //组件名
+	Desc       string   `json:"desc"`                 //组件描述
+	DependsOn  []string `json:"depends_on,omitempty"` //依赖的组件名
+	Type       string   `json:"type"`                 //组件类型 [api|script]
+	Url        string   `json:"url"`                  //地址
+	OutputName string   `json:"output_name"`          //输出对象名
+	IsCache    bool     `json:"is_cache"`             //是否启用缓存
+	IsSkip     bool     `json:"is_skip"`              //是否进入执行
+	Branch     string   `json:"branch,omitempty"`     //分支组件命中的目标
+	IsWait     bool     `json:"is_wait,omitempty"`    //是否进入等待信号
+	IsFallback bool     `json:"fallback,omitempty"`   //是否因上游服务熔断返回了降级数据
 	// api 特有日志字段
 	Method       string            `json:"method,omitempty"`
 	Body         any               `json:"body,omitempty"`
@@ -248,6 +299,9 @@ type componentLog struct {
 	OutputData   any               `json:"output_data,omitempty"`
 	Response     any               `json:"response"`               //输出数据
 	RequestLogs  []*requestLog     `json:"request_logs,omitempty"` //使用脚本请求的数据
+	Children     []*componentLog   `json:"children,omitempty"`     //foreach每一项的执行日志
+	RunLog       any               `json:"run_log,omitempty"`      //子流程的执行日志
+	Attempts     []*attemptLog     `json:"attempts,omitempty"`     //配置了重试时每一次执行的日志
 }
 
 func (s *componentLog) SetStep(step int) {
@@ -258,6 +312,18 @@ func (s *componentLog) SetSkip(is bool) {
 	s.IsSkip = is
 }
 
+func (s *componentLog) SetBranch(target string) {
+	s.Branch = target
+}
+
+func (s *componentLog) SetWait(is bool) {
+	s.IsWait = is
+}
+
+func (s *componentLog) SetFallback(is bool) {
+	s.IsFallback = is
+}
+
 func (s *componentLog) SetVersion(v string) {
 	s.Version = v
 }
@@ -274,6 +340,28 @@ func (s *componentLog) NewRequestLog() RequestLog {
 	return &log
 }
 
+// NewChildLog 创建foreach每一项的执行日志
+func (s *componentLog) NewChildLog() ComponentLog {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	log := componentLog{}
+	s.Children = append(s.Children, &log)
+	return &log
+}
+
+// NewAttemptLog 创建单次执行的日志
+func (s *componentLog) NewAttemptLog(attempt int) AttemptLog {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	log := attemptLog{Attempt: attempt}
+	s.Attempts = append(s.Attempts, &log)
+	return &log
+}
+
+func (s *componentLog) SetRunLog(log any) {
+	s.RunLog = log
+}
+
 func (s *componentLog) SetResponse(resp any) {
 	s.Response = resp
 }
@@ -282,10 +370,11 @@ func (s *componentLog) SetRequest(com Component) {
 	s.Input = com.Input
 	s.Name = com.Name
 	s.Desc = com.Desc
+	s.DependsOn = com.DependsOn
 	s.Type = com.Type
 	s.Url = com.Url
 	s.OutputName = com.OutputName
-	s.IsCache = com.IsCache
+	s.IsCache = com.UseCache()
 	s.IgnoreError = com.IgnoreError
 }
 
@@ -385,3 +474,34 @@ func (s *requestLog) SetRunTime(t time.Time) {
 	s.EndDatetime = cur.Format(LogDatetimeFormat)
 	s.RunTime = fmt.Sprintf("%vs", float64(time.Now().UnixMilli()-t.UnixMilli())/1000)
 }
+
+type attemptLog struct {
+	Attempt       int    `json:"attempt"`               //第几次执行
+	StatusCode    int    `json:"status_code,omitempty"` //api返回的状态码
+	Error         string `json:"error,omitempty"`       //错误原因
+	Wait          string `json:"wait,omitempty"`        //下一次重试的等待时长
+	RunTime       string `json:"run_time"`
+	StartDatetime string `json:"start_datetime"`
+	EndDatetime   string `json:"end_datetime"`
+}
+
+func (s *attemptLog) SetStatusCode(code int) {
+	s.StatusCode = code
+}
+
+func (s *attemptLog) SetError(err error) {
+	if err != nil {
+		s.Error = err.Error()
+	}
+}
+
+func (s *attemptLog) SetWait(wait time.Duration) {
+	s.Wait = wait.String()
+}
+
+func (s *attemptLog) SetRunTime(t time.Time) {
+	cur := time.Now()
+	s.StartDatetime = t.Format(LogDatetimeFormat)
+	s.EndDatetime = cur.Format(LogDatetimeFormat)
+	s.RunTime = fmt.Sprintf("%vs", float64(time.Now().UnixMilli()-t.UnixMilli())/1000)
+}
@@ -3,16 +3,76 @@ package engine
 import (
 	"errors"
 	"fmt"
+	"github.com/jinzhu/copier"
+	json "github.com/json-iterator/go"
+	"ps-go/consts"
 	"ps-go/tools"
+	"time"
 )
 
 type Rule struct {
-	Version    string        `json:"version"`
-	Record     bool          `json:"record"`     //是否记录流程数据
-	Suspend    bool          `json:"suspend"`    //是否开启异常中断挂起 [脚本错误/异常捕捉错误]
-	Request    Request       `json:"request"`    //请求信息
-	Response   Response      `json:"response"`   //返回信息
-	Components [][]Component `json:"components"` //组件信息
+	Version    string     `json:"version"`
+	Record     bool       `json:"record"`     //是否记录流程数据
+	Suspend    bool       `json:"suspend"`    //是否开启异常中断挂起 [脚本错误/异常捕捉错误]
+	Mode       string     `json:"mode"`       //执行模式，async为异步执行，立即返回trx，通过接口查询执行进度
+	Timeout    int        `json:"timeout"`    //流程最大运行时间/s，超时之后取消执行中的组件并中断流程，0为不限制
+	Request    Request    `json:"request"`    //请求信息
+	Response   Response   `json:"response"`   //返回信息
+	Components Components `json:"components"` //组件信息
+	StepNames  []string   `json:"stepNames"`  //层名称，与components的层一一对应，可作为分支跳转的目标
+	Notify     []Notify   `json:"notify"`     //流程执行完成之后的回调通知
+	Limit      *Limit     `json:"limit"`      //限流配置，可以通过rule_limit单独修改
+
+	Idempotent *Idempotent `json:"idempotent,omitempty"` //幂等配置，相同幂等键的请求直接返回首次请求的执行结果
+}
+
+// Copy 复制流程执行时会修改的部分：规则本身以及组件列表，组件中可输入变量的字段在创建运行时时再复制。
+// 缓存中的规则需要保持不变，共用的规则需要复制之后再执行
+func (r *Rule) Copy() *Rule {
+	rule := *r
+	rule.Components = make(Components, len(r.Components))
+	for i, list := range r.Components {
+		rule.Components[i] = append([]Component(nil), list...)
+	}
+	return &rule
+}
+
+// Clone 深拷贝规则，流程执行时会修改规则中的数据，共用的规则需要复制之后再执行
+func (r *Rule) Clone() (*Rule, error) {
+	rule := &Rule{}
+	return rule, copier.CopyWithOption(rule, r, copier.Option{DeepCopy: true})
+}
+
+// IsAsync 是否为异步执行模式
+func (r *Rule) IsAsync() bool {
+	return r.Mode == consts.RunModeAsync
+}
+
+// LeaseExpire 流程执行期间持有的许可、锁的有效期，与流程的最大运行时间保持一致，防止实例异常退出时无法释放
+func (r *Rule) LeaseExpire() time.Duration {
+	if r.Timeout > 0 {
+		return time.Duration(r.Timeout)*time.Second + time.Minute
+	}
+	return consts.LeaseExpire
+}
+
+// Components 组件信息，兼容二维分层格式[][]component 与一维依赖格式[]component
+type Components [][]Component
+
+// UnmarshalJSON 一维数组格式时，所有组件视为同一层，由dependsOn决定执行顺序
+func (c *Components) UnmarshalJSON(data []byte) error {
+	var layers [][]Component
+	if err := json.Unmarshal(data, &layers); err == nil {
+		*c = layers
+		return nil
+	}
+
+	var list []Component
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*c = Components{list}
+	return nil
 }
 
 type Request struct {
@@ -42,6 +102,26 @@ type Response struct {
 	DefaultBody map[string]any `json:"defaultBody"` //默认返回值
 }
 
+// Notify 流程执行完成之后的回调通知
+type Notify struct {
+	Name          string         `json:"name"`          //通知名称
+	Url           string         `json:"url"`           //回调地址
+	Method        string         `json:"method"`        //请求方法，默认为POST
+	Events        []string       `json:"events"`        //通知的事件 [success|break|suspend|wait]，不设置时通知全部事件
+	Header        map[string]any `json:"header"`        //请求header
+	Body          any            `json:"body"`          //请求body模板，可通过{notify.xxx}获取通知信息，不设置时发送通知信息
+	Secret        string         `json:"secret"`        //签名使用的密钥名，对应密钥管理中的密钥，不设置时不签名
+	Timeout       int            `json:"timeout"`       //请求超时时间/s
+	RetryMaxCount int            `json:"retryMaxCount"` //最大重试次数
+	RetryMaxWait  int            `json:"retryMaxWait"`  //重试最大等待时长/s
+}
+
+// Case 分支条件
+type Case struct {
+	Condition string `json:"condition"` //分支条件，与准入条件语法一致
+	Target    string `json:"target"`    //命中后跳转的目标，层名称或者组件分组名
+}
+
 type tls struct {
 	Ca  string `json:"ca"`
 	Key string `json:"key"`
@@ -51,13 +131,31 @@ type Component struct {
 	IsFinish  bool   `json:"-"`                   //附加字段，恢复任务时用
 	Name      string `json:"name"`                //组件名,同一个step层下，name不能重复
 	Desc      string `json:"desc"`                //组件描述
-	Type      string `json:"type"`                //组件类型 [api|script]
+	Type      string `json:"type"`                //组件类型 [api|script|switch|foreach|rule|wait]
 	Input     any    `json:"input,omitempty"`     //输入参数
 	Condition string `json:"condition,omitempty"` //准入条件
-	Url       string `json:"url"`                 //组件地址|api接口
+	Url       string `json:"url"`                 //组件地址|api接口|子流程规则名
 	IsCache   bool   `json:"isCache"`             //是否启用缓存
 
-	Method            string         `json:"method,omitempty"`       //请求方法，仅api支持
+	// 依赖的组件名，依赖的组件全部执行完成后才会执行当前组件。
+	// 未设置时默认依赖上一层的全部组件，设置为[]时则不依赖任何组件
+	DependsOn []string `json:"dependsOn,omitempty"`
+	Group     string   `json:"group,omitempty"` //组件分组名，可作为分支跳转的目标
+
+	Cases   []Case `json:"cases,omitempty"`   //分支条件，按顺序匹配，仅switch支持
+	Default string `json:"default,omitempty"` //没有命中分支时跳转的目标，仅switch支持
+
+	Items       string     `json:"items,omitempty"`       //遍历的数组表达式，如{user.ids}，仅foreach支持
+	MaxParallel int        `json:"maxParallel,omitempty"` //最大并发执行数量，默认为1，仅foreach支持
+	Component   *Component `json:"component,omitempty"`   //每一项执行的组件[api|script|rule]，可通过{item}、{index}取值，仅foreach支持
+
+	Signal        string `json:"signal,omitempty"`        //等待的信号名，默认为组件名，仅wait支持
+	WaitTimeout   int    `json:"waitTimeout,omitempty"`   //等待信号的超时时间/s，0为不超时，仅wait支持
+	TimeoutTarget string `json:"timeoutTarget,omitempty"` //等待超时之后跳转的目标，未设置时中断流程，仅wait支持
+
+	Compensate *Component `json:"compensate,omitempty"` //补偿组件[api|script]，流程中断时逆序执行，可通过{output}获取当前组件的输出
+
+	Method            string         `json:"method,omitempty"`       //请求方法，仅api、rule支持
 	ContentType       string         `json:"contentType,omitempty"`  //数据类型，仅api支持
 	Auth              []any          `json:"auth,omitempty"`         //请求auth，仅api支持
 	Header            map[string]any `json:"header,omitempty"`       //请求header，仅api支持
@@ -75,6 +173,62 @@ type Component struct {
 	OutputName    string `json:"outputName"`    //返回数据名
 	RetryMaxCount int    `json:"retryMaxCount"` //最大重试次数
 	RetryMaxWait  int    `json:"retryMaxWait"`  //重试最大等待时长
+
+	Retry   *Retry   `json:"retry,omitempty"`   //重试策略，设置之后retryMaxCount、retryMaxWait不再生效
+	Breaker *Breaker `json:"breaker,omitempty"` //熔断配置，api组件以及脚本中的request生效
+
+	Cache *ComponentCache `json:"cache,omitempty"` //缓存配置，设置之后启用缓存
+}
+
+// Copy 复制组件，可输入变量的字段进行深拷贝，防止变量转换时修改原始配置
+func (c Component) Copy() Component {
+	c.Input = tools.CopyData(c.Input)
+	c.Auth, _ = tools.CopyData(c.Auth).([]any)
+	c.Header, _ = tools.CopyData(c.Header).(map[string]any)
+	c.OutputData = tools.CopyData(c.OutputData)
+	return c
+}
+
+// BranchTargets 获取分支组件的全部跳转目标，wait组件的跳转目标为超时目标
+func (c *Component) BranchTargets() []string {
+	var targets []string
+	if c.Type == ComponentTypeWait {
+		if c.TimeoutTarget != "" {
+			targets = append(targets, c.TimeoutTarget)
+		}
+		return targets
+	}
+
+	for _, item := range c.Cases {
+		if item.Target != "" && !tools.InList(targets, item.Target) {
+			targets = append(targets, item.Target)
+		}
+	}
+	if c.Default != "" && !tools.InList(targets, c.Default) {
+		targets = append(targets, c.Default)
+	}
+	return targets
+}
+
+// UseCache 是否启用缓存
+func (c *Component) UseCache() bool {
+	return c.IsCache || c.Cache != nil
+}
+
+// MaxRetry 获取组件的最大重试次数
+func (c *Component) MaxRetry() int {
+	if c.Retry != nil {
+		return c.Retry.MaxCount
+	}
+	return c.RetryMaxCount
+}
+
+// SignalName 获取wait组件等待的信号名
+func (c *Component) SignalName() string {
+	if c.Signal != "" {
+		return c.Signal
+	}
+	return c.Name
 }
 
 func (f *FieldRule) ValidateInt(val any, is bool) (resp int, ignore bool, err error) {
@@ -1,22 +1,18 @@
 package engine
 
 import (
-	"context"
 	"crypto/md5"
 	"encoding/base64"
 	"fmt"
-	"github.com/go-redis/redis/v8"
 	json "github.com/json-iterator/go"
 	"github.com/limeschool/gin"
 	"github.com/robertkrimen/otto"
 	"go.uber.org/zap"
-	"ps-go/consts"
 	"ps-go/model"
 	"ps-go/tools"
 	"ps-go/tools/aes"
 	"ps-go/tools/lock"
 	"ps-go/tools/rsa"
-	"time"
 	"unsafe"
 )
 
@@ -57,8 +53,10 @@ func RequestModule(r *runtime) func(call otto.FunctionCall) otto.Value {
 		Timeout      int               `json:"timeout"`      //超时时间
 		ResponseType string            `json:"responseType"` //返回类型
 		IsCache      bool              `json:"isCache"`      //是否缓存
+		Cache        *ComponentCache   `json:"cache"`        //缓存配置，设置之后启用缓存，key不设置时使用请求参数的md5
 		OnlyData     *bool             `json:"onlyData"`     //是否只返回data,不携带header等 默认true
 		Tls          *tls              `json:"tls"`          //请求需要携带证书时使用
+		Breaker      *Breaker          `json:"breaker"`      //熔断配置，未设置时使用组件的熔断配置
 	}
 
 	// 解析请求参数
@@ -89,8 +87,8 @@ func RequestModule(r *runtime) func(call otto.FunctionCall) otto.Value {
 		return arg
 	}
 
-	// 发起请求
-	handleRequest := func(arg *requestArg) *tools.HttpRequest {
+	// 发起请求，上游服务熔断并且配置了降级数据时返回降级数据
+	handleRequest := func(arg *requestArg) (*tools.HttpRequest, any) {
 		var err error
 		// 创建请求日志
 		log := r.componentLog.NewRequestLog()
@@ -105,6 +103,7 @@ func RequestModule(r *runtime) func(call otto.FunctionCall) otto.Value {
 			RequestType:  arg.RequestType,
 			Timeout:      arg.Timeout,
 			ResponseType: arg.ResponseType,
+			Ctx:          r.runCtx,
 		}
 
 		if arg.Tls != nil {
@@ -126,7 +125,27 @@ func RequestModule(r *runtime) func(call otto.FunctionCall) otto.Value {
 		// 设置请求参数
 		log.SetRequest(request)
 
-		if err = request.Do(); err != nil {
+		// 试运行时脚本不发送请求，需要为组件配置模拟数据
+		if r.dryRun != nil {
+			err = NewDryRunError(fmt.Sprintf("试运行时脚本不支持发送请求，请为组件%v配置模拟数据", r.component.Name))
+			log.SetError(err)
+			panic(err)
+		}
+
+		conf := arg.Breaker
+		if conf == nil {
+			conf = r.component.Breaker
+		}
+
+		if err = r.doRequest(&request, conf); err != nil {
+			if data, ok := r.fallbackData(err, conf); ok {
+				log.SetError(err)
+				return nil, data
+			}
+			if _, ok := err.(*Error); ok {
+				log.SetError(err)
+				panic(err)
+			}
 			if _, ok := err.(*gin.CustomError); ok {
 				err = NewRequestError(err.Error())
 			} else {
@@ -142,41 +161,47 @@ func RequestModule(r *runtime) func(call otto.FunctionCall) otto.Value {
 		log.SetRespHeader(request.ResponseHeader())
 		log.SetRespCookies(request.ResponseCookies())
 
-		return &request
+		return &request, nil
 	}
 
 	// 获取缓存
-	handleGetCache := func(client *redis.Client, key string) (otto.Value, bool) {
-		// 查询redis缓存
-		if str, err := client.Get(context.TODO(), key).Result(); err == nil && str != "" {
-			resp := map[string]any{}
-			if json.UnmarshalFromString(str, &resp) == nil {
-				value, _ := r.vm.ToValue(resp)
-				return value, true
-			}
+	handleGetCache := func(cache Cache, key string) (otto.Value, bool) {
+		if resp, ok := cache.Get(r.ctx, key); ok {
+			value, _ := r.vm.ToValue(resp)
+			return value, true
 		}
 		return otto.Value{}, false
 	}
 
-	// 获取缓存的key
-	getCacheKey := func(data any) []byte {
-		b, _ := json.Marshal(data)
-		return b
+	// 获取缓存的key，与组件缓存使用相同的前缀，可以按照流程以及组件清理
+	getCacheKey := func(arg *requestArg) string {
+		suffix := ""
+		if arg.Cache != nil && arg.Cache.Key != "" {
+			suffix = arg.Cache.Key
+		} else {
+			b, _ := json.Marshal(arg)
+			suffix = fmt.Sprintf("%x", md5.Sum(b))
+		}
+		return CachePrefix(r.ruleKey(), r.component.Name) + "request_" + suffix
 	}
 
 	// 导出函数
 	return func(call otto.FunctionCall) otto.Value {
 		arg := handleParseArg(call)
 
-		client := r.ctx.Redis(consts.ProcessScheduleCache)
+		var cache Cache
 		cacheKey := ""
 		// 开启了缓存，则查询缓存
-		if arg.IsCache {
-			byteData := getCacheKey(arg)
-			cacheKey = fmt.Sprintf("request_%x", md5.Sum(byteData))
+		if r.dryRun == nil && (arg.IsCache || arg.Cache != nil) {
+			cacheKey = getCacheKey(arg)
+			if arg.Cache != nil {
+				cache = GetCache(arg.Cache.Backend)
+			} else {
+				cache = GetCache("")
+			}
 
 			// 查询缓存
-			if value, ok := handleGetCache(client, cacheKey); ok {
+			if value, ok := handleGetCache(cache, cacheKey); ok {
 				return value
 			}
 
@@ -185,13 +210,17 @@ func RequestModule(r *runtime) func(call otto.FunctionCall) otto.Value {
 			lc.Acquire()
 			defer lc.Release()
 
-			if value, ok := handleGetCache(client, cacheKey); ok {
+			if value, ok := handleGetCache(cache, cacheKey); ok {
 				return value
 			}
 		}
 
 		// 缓存没有，进行实时请求
-		req := handleRequest(arg)
+		req, fallback := handleRequest(arg)
+		if fallback != nil {
+			value, _ := r.vm.ToValue(fallback)
+			return value
+		}
 
 		var respData any
 		if *arg.OnlyData {
@@ -205,10 +234,9 @@ func RequestModule(r *runtime) func(call otto.FunctionCall) otto.Value {
 			}
 		}
 
-		if arg.IsCache {
+		if cache != nil {
 			// 进行数据缓存
-			str, _ := json.MarshalToString(respData)
-			client.Set(context.TODO(), cacheKey, str, 5*time.Minute)
+			cache.Set(r.ctx, cacheKey, respData, arg.Cache.Expire())
 		}
 
 		// 返回数据
@@ -320,7 +348,6 @@ func ActiveBreakModule() func(call otto.FunctionCall) otto.Value {
 			panic(NewModuleArgError("break method argument must is string"))
 		}
 		panic(NewActiveBreakError(call.Argument(0).String()))
-		return otto.Value{}
 	}
 }
 
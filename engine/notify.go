@@ -0,0 +1,237 @@
+package engine
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/limeschool/gin"
+	"go.uber.org/zap"
+	"ps-go/consts"
+	"ps-go/errors"
+	"ps-go/model"
+	"ps-go/tools"
+	"strings"
+	"time"
+)
+
+const (
+	NotifyEventSuccess = "success" //成功执行
+	NotifyEventBreak   = "break"   //中断
+	NotifyEventSuspend = "suspend" //挂起
+	NotifyEventWait    = "wait"    //等待信号
+
+	NotifySignHeader      = "X-PS-Signature" //签名header，hex(hmac_sha256(secret, timestamp + "." + body))
+	NotifyTimestampHeader = "X-PS-Timestamp" //签名时间戳header
+	NotifyEventHeader     = "X-PS-Event"     //通知事件header
+)
+
+// notifyTask 待发送的回调通知
+type notifyTask struct {
+	notify Notify
+	trx    string
+	event  string
+	header map[string]string
+	body   any
+}
+
+// notifyEvent 获取执行状态对应的通知事件
+func notifyEvent(status string) string {
+	switch status {
+	case RunSuccess:
+		return NotifyEventSuccess
+	case RunBreak, RunActiveBreak:
+		return NotifyEventBreak
+	case RunSuspend, RunActiveSuspend:
+		return NotifyEventSuspend
+	case RunWait:
+		return NotifyEventWait
+	}
+	return ""
+}
+
+// Notify 流程执行完成之后，发送回调通知。
+// 通知数据在流程释放之前生成，发送以及重试在后台进行，不影响流程的释放
+func (r *runner) Notify() {
+	// 子流程由上级流程统一通知，试运行不发送通知
+	if len(r.rule.Notify) == 0 || len(r.parents) != 0 || r.dryRun != nil {
+		return
+	}
+
+	event := notifyEvent(r.logger.GetStatus())
+	if event == "" {
+		return
+	}
+
+	info := map[string]any{
+		"trx":     r.trx,
+		"method":  r.method,
+		"path":    r.path,
+		"version": r.version,
+		"event":   event,
+		"status":  r.logger.GetStatus(),
+	}
+	if r.runErr != nil {
+		info["error"] = r.runErr.Error()
+	}
+	store := newScopeStore(r.runStore, map[string]any{"notify": info})
+
+	ctx := tools.CopyContext(r.ctx)
+	for _, item := range r.rule.Notify {
+		if len(item.Events) != 0 && !tools.InList(item.Events, event) {
+			continue
+		}
+
+		task := &notifyTask{
+			notify: item,
+			trx:    r.trx,
+			event:  event,
+			header: map[string]string{},
+			body:   info,
+		}
+
+		if item.Body != nil {
+			task.body = store.GetMatchData(tools.CopyData(item.Body))
+		}
+
+		if len(item.Header) != 0 {
+			header, _ := store.GetMatchData(tools.CopyData(item.Header)).(map[string]any)
+			for key, val := range header {
+				task.header[key] = fmt.Sprint(val)
+			}
+		}
+
+		go task.send(ctx)
+	}
+}
+
+// send 发送通知，失败时按照重试配置进行重试，每次发送都会记录到notify_log
+func (t *notifyTask) send(ctx *gin.Context) {
+	defer func() { // 防止意外Panic
+		if p := recover(); p != nil {
+			ctx.Log.Error("recover", zap.Any("panic", p))
+		}
+	}()
+
+	body := tools.AnyToJsonString(t.body)
+	t.header[NotifyEventHeader] = t.event
+	t.header[consts.ProcessScheduleTrx] = t.trx
+
+	method := t.notify.method()
+
+	// 密钥加载失败时不进行发送，直接记录失败
+	var secret string
+	if t.notify.Secret != "" {
+		item := model.Secret{}
+		if err := item.OneByName(ctx, t.notify.Secret); err != nil {
+			t.saveLog(ctx, body, 1, 0, errors.NewF("加载签名密钥%v失败：%v", t.notify.Secret, err.Error()))
+			return
+		}
+		secret = item.Context
+		if method == "GET" {
+			t.saveLog(ctx, body, 1, 0, errors.New("签名的回调通知不支持GET请求"))
+			return
+		}
+	}
+
+	for attempt := 0; attempt <= t.notify.RetryMaxCount; attempt++ {
+		code, err := t.do(method, body, secret)
+		t.saveLog(ctx, body, attempt+1, code, err)
+		if err == nil {
+			return
+		}
+
+		if attempt < t.notify.RetryMaxCount {
+			time.Sleep(t.waitTime(attempt))
+		}
+	}
+}
+
+// saveLog 记录每一次的发送结果
+func (t *notifyTask) saveLog(ctx *gin.Context, body string, attempt, code int, err error) {
+	log := model.NotifyLog{
+		Trx:        t.trx,
+		Name:       t.notify.Name,
+		Url:        t.notify.Url,
+		Event:      t.event,
+		Attempt:    attempt,
+		Body:       body,
+		StatusCode: code,
+		Success:    err == nil,
+	}
+	if err != nil {
+		log.Error = err.Error()
+	}
+	if e := log.Create(ctx); e != nil {
+		ctx.Log.Error("回调通知记录存储失败", zap.Any("trx", t.trx), zap.Any("err", e))
+	}
+}
+
+// do 发送一次通知，返回状态码不为2xx时视为失败
+func (t *notifyTask) do(method, body, secret string) (int, error) {
+	header := make(map[string]string, len(t.header)+2)
+	for key, val := range t.header {
+		header[key] = val
+	}
+
+	// 签名，接收方通过相同的密钥校验数据来源以及完整性
+	if secret != "" {
+		timestamp := fmt.Sprint(time.Now().Unix())
+		header[NotifyTimestampHeader] = timestamp
+		header[NotifySignHeader] = sign(secret, timestamp+"."+body)
+	}
+
+	// 按原样发送签名的数据，保证接收方可以使用收到的body校验签名，GET请求时通知数据转换为query
+	request := tools.HttpRequest{
+		Url:          t.notify.Url,
+		Method:       method,
+		Header:       header,
+		Timeout:      t.notify.Timeout,
+		RequestType:  consts.RespJson,
+		ResponseType: consts.RespText,
+	}
+	if method == "GET" {
+		request.Body = t.body
+	} else {
+		request.RawBody = []byte(body)
+	}
+
+	if err := request.Do(); err != nil {
+		return request.ResponseCode(), err
+	}
+
+	code := request.ResponseCode()
+	if code < 200 || code >= 300 {
+		return code, errors.NewF("回调通知返回状态码：%v", code)
+	}
+	return code, nil
+}
+
+// method 获取通知的请求方法，默认为POST
+func (n *Notify) method() string {
+	if n.Method == "" {
+		return "POST"
+	}
+	return strings.ToUpper(n.Method)
+}
+
+// waitTime 计算下一次的重试时间，按照指数增长，不超过最大等待时长
+func (t *notifyTask) waitTime(attempt int) time.Duration {
+	max := t.notify.RetryMaxWait
+	if max <= 0 {
+		max = 10
+	}
+
+	wait := 1 << attempt
+	if wait > max {
+		wait = max
+	}
+	return time.Duration(wait) * time.Second
+}
+
+// sign 计算hmac_sha256签名
+func sign(secret, data string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(data))
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,138 @@
+package engine
+
+import (
+	"fmt"
+	"github.com/limeschool/gin"
+	"math/rand"
+	"time"
+)
+
+const (
+	RetryBackoffFixed       = "fixed"       //固定间隔
+	RetryBackoffExponential = "exponential" //指数增长
+	RetryBackoffJitter      = "jitter"      //去相关抖动，在初始间隔与上一次间隔的3倍之间随机
+
+	defaultRetryInterval    = 1000  //默认重试间隔/ms
+	defaultRetryMaxInterval = 30000 //默认最大重试间隔/ms
+)
+
+// Retry 组件重试策略
+type Retry struct {
+	MaxCount    int      `json:"maxCount"`              //最大重试次数
+	Backoff     string   `json:"backoff"`               //退避策略 [fixed|exponential|jitter]，默认为fixed
+	Interval    int      `json:"interval"`              //重试间隔/ms，exponential、jitter时为初始间隔，默认为1000
+	MaxInterval int      `json:"maxInterval"`           //最大重试间隔/ms，默认为30000
+	MaxElapsed  int      `json:"maxElapsed"`            //从第一次执行开始的最大重试时长/s，超过之后不再重试，0为不限制
+	StatusCodes []int    `json:"statusCodes,omitempty"` //需要重试的返回状态码，仅api支持
+	OnCondition bool     `json:"onCondition"`           //返回不满足responseCondition时是否重试，仅api支持
+	ErrorCodes  []string `json:"errorCodes,omitempty"`  //需要重试的错误码，如脚本执行错误110001
+}
+
+// Wait 计算第retry次重试的等待时长，last为上一次的等待时长
+func (p *Retry) Wait(retry int, last time.Duration) time.Duration {
+	interval := time.Duration(p.Interval) * time.Millisecond
+	if interval <= 0 {
+		interval = defaultRetryInterval * time.Millisecond
+	}
+
+	max := time.Duration(p.MaxInterval) * time.Millisecond
+	if max <= 0 {
+		max = defaultRetryMaxInterval * time.Millisecond
+	}
+
+	var wait time.Duration
+	switch p.Backoff {
+	case RetryBackoffExponential:
+		// 提前与最大间隔比较，避免位移溢出
+		wait = max
+		if retry < 32 && interval <= max>>uint(retry) {
+			wait = interval << retry
+		}
+	case RetryBackoffJitter:
+		if last < interval {
+			last = interval
+		}
+		wait = interval + time.Duration(rand.Int63n(int64(last*3-interval)+1))
+	default:
+		wait = interval
+	}
+
+	if wait <= 0 || wait > max {
+		wait = max
+	}
+	return wait
+}
+
+// attempt 执行一次组件，配置了重试时记录每一次执行的日志
+func (r *runtime) attempt() (any, error) {
+	start := time.Now()
+	if r.firstStart.IsZero() {
+		r.firstStart = start
+	}
+	r.statusCode = 0
+	r.unmatched = false
+	r.fallback = false
+
+	resp, err := r.invoke()
+
+	// 流程中断或者超时导致的错误
+	if err != nil && r.runCtx.Err() != nil {
+		err = r.contextError()
+	}
+
+	if r.maxRetry > 0 {
+		log := r.componentLog.NewAttemptLog(r.retry + 1)
+		log.SetRunTime(start)
+		log.SetStatusCode(r.statusCode)
+		log.SetError(err)
+		r.attemptLog = log
+	}
+	return resp, err
+}
+
+// nextRetry 判断是否需要重试，并计算重试的等待时长
+func (r *runtime) nextRetry(err error) (time.Duration, bool) {
+	if r.retry >= r.retryLimit() || !r.IsRetry(err) {
+		return 0, false
+	}
+
+	var wait time.Duration
+	policy := r.component.Retry
+	if policy == nil {
+		if r.retryMaxWait != 0 {
+			wait = r.getWaitTime(r.retry, r.maxRetry, r.retryMaxWait)
+		}
+	} else {
+		wait = policy.Wait(r.retry, r.lastWait)
+		// 超过最大重试时长之后不再重试
+		if policy.MaxElapsed > 0 && time.Since(r.firstStart)+wait > time.Duration(policy.MaxElapsed)*time.Second {
+			return 0, false
+		}
+	}
+
+	r.lastWait = wait
+	if r.attemptLog != nil {
+		r.attemptLog.SetWait(wait)
+	}
+	return wait, true
+}
+
+// retryLimit 最大重试次数，不包含首次执行。
+// 未配置重试策略时兼容已有规则的重试次数，retryMaxCount为n时最多重试n+1次
+func (r *runtime) retryLimit() int {
+	if r.component.Retry != nil {
+		return r.maxRetry
+	}
+	return r.maxRetry + 1
+}
+
+// errorCode 获取错误的错误码
+func errorCode(err error) string {
+	switch e := err.(type) {
+	case *Error:
+		return e.Code
+	case *gin.CustomError:
+		return fmt.Sprint(e.Code)
+	}
+	return ""
+}
@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRetryWait(t *testing.T) {
+	ms := time.Millisecond
+	cases := []struct {
+		name   string
+		policy Retry
+		retry  int
+		want   time.Duration
+	}{
+		{"默认固定间隔", Retry{}, 3, defaultRetryInterval * ms},
+		{"固定间隔", Retry{Interval: 200}, 5, 200 * ms},
+		{"固定间隔不超过最大间隔", Retry{Interval: 5000, MaxInterval: 1000}, 0, 1000 * ms},
+		{"指数首次重试", Retry{Backoff: RetryBackoffExponential, Interval: 100}, 0, 100 * ms},
+		{"指数增长", Retry{Backoff: RetryBackoffExponential, Interval: 100}, 3, 800 * ms},
+		{"指数增长不超过最大间隔", Retry{Backoff: RetryBackoffExponential, Interval: 100, MaxInterval: 500}, 3, 500 * ms},
+		{"指数增长默认最大间隔", Retry{Backoff: RetryBackoffExponential, Interval: 100}, 20, defaultRetryMaxInterval * ms},
+		{"指数增长溢出", Retry{Backoff: RetryBackoffExponential, Interval: 100}, 40, defaultRetryMaxInterval * ms},
+		{"位移溢出", Retry{Backoff: RetryBackoffExponential, Interval: 1 << 20, MaxInterval: 1 << 41}, 31, 1 << 41 * ms},
+	}
+
+	for _, c := range cases {
+		if got := c.policy.Wait(c.retry, 0); got != c.want {
+			t.Errorf("%v: Wait(%v) = %v, want %v", c.name, c.retry, got, c.want)
+		}
+	}
+}
+
+func TestRetryWaitJitter(t *testing.T) {
+	ms := time.Millisecond
+	policy := Retry{Backoff: RetryBackoffJitter, Interval: 100, MaxInterval: 2000}
+
+	cases := []struct {
+		last     time.Duration
+		min, max time.Duration
+	}{
+		{0, 100 * ms, 300 * ms},          // 首次重试按照初始间隔计算
+		{50 * ms, 100 * ms, 300 * ms},    // 上一次间隔小于初始间隔
+		{400 * ms, 100 * ms, 1200 * ms},  // 在初始间隔与上一次间隔的3倍之间
+		{1500 * ms, 100 * ms, 2000 * ms}, // 不超过最大间隔
+	}
+
+	for _, c := range cases {
+		for i := 0; i < 200; i++ {
+			got := policy.Wait(i, c.last)
+			if got < c.min || got > c.max {
+				t.Fatalf("Wait(last=%v) = %v, want in [%v, %v]", c.last, got, c.min, c.max)
+			}
+		}
+	}
+}
+
+// retryAttempts 模拟组件持续失败，返回总的执行次数
+func retryAttempts(r *runtime, err error) int {
+	attempts := 1
+	for {
+		if _, ok := r.nextRetry(err); !ok {
+			return attempts
+		}
+		r.retry++
+		attempts++
+	}
+}
+
+func TestNextRetryAttempts(t *testing.T) {
+	network := NewNetworkError("connection refused")
+	cases := []struct {
+		name      string
+		component Component
+		err       error
+		attempts  int
+	}{
+		// 未配置retry时兼容已有规则，retryMaxCount为n时最多重试n+1次
+		{"未配置重试时网络错误重试1次", Component{}, network, 2},
+		{"retryMaxCount", Component{RetryMaxCount: 2}, network, 4},
+		{"retryMaxCount设置等待时长", Component{RetryMaxCount: 1, RetryMaxWait: 1}, network, 3},
+		{"非网络错误不重试", Component{RetryMaxCount: 2}, NewRunScriptError("error"), 1},
+
+		// 配置了retry时按照maxCount重试
+		{"maxCount", Component{Retry: &Retry{MaxCount: 3, Interval: 1}}, network, 4},
+		{"maxCount为0不重试", Component{Retry: &Retry{}}, network, 1},
+		{"retry优先于retryMaxCount", Component{RetryMaxCount: 5, Retry: &Retry{MaxCount: 1, Interval: 1}}, network, 2},
+		{"错误码重试", Component{Retry: &Retry{MaxCount: 2, Interval: 1, ErrorCodes: []string{RunScriptErrorCode}}}, NewRunScriptError("error"), 3},
+		{"错误码不匹配", Component{Retry: &Retry{MaxCount: 2, Interval: 1, ErrorCodes: []string{"1"}}}, NewRunScriptError("error"), 1},
+		{"状态码重试", Component{Retry: &Retry{MaxCount: 1, Interval: 1}}, NewStatusCodeError("503"), 2},
+	}
+
+	for _, c := range cases {
+		r := &runtime{
+			component:    c.component,
+			maxRetry:     c.component.MaxRetry(),
+			retryMaxWait: c.component.RetryMaxWait,
+			runCtx:       context.Background(),
+			firstStart:   time.Now(),
+		}
+		if got := retryAttempts(r, c.err); got != c.attempts {
+			t.Errorf("%v: attempts = %v, want %v", c.name, got, c.attempts)
+		}
+	}
+}
+
+func TestNextRetryCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// 流程中断之后不再重试
+	r := &runtime{component: Component{RetryMaxCount: 3}, maxRetry: 3, runCtx: ctx}
+	if got := retryAttempts(r, NewNetworkError("timeout")); got != 1 {
+		t.Errorf("attempts = %v, want 1", got)
+	}
+}
+
+func TestNextRetryMaxElapsed(t *testing.T) {
+	// 超过最大重试时长之后不再重试
+	r := &runtime{
+		component:  Component{Retry: &Retry{MaxCount: 5, Interval: 1000, MaxElapsed: 1}},
+		maxRetry:   5,
+		runCtx:     context.Background(),
+		firstStart: time.Now().Add(-500 * time.Millisecond),
+	}
+	if got := retryAttempts(r, NewNetworkError("timeout")); got != 1 {
+		t.Errorf("attempts = %v, want 1", got)
+	}
+}
+
+func TestRuntimeSleepCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &runtime{runCtx: ctx}
+
+	// 等待重试期间流程中断时立即返回
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	r.sleep(time.Minute)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("sleep returned after %v", elapsed)
+	}
+}
@@ -1,15 +1,16 @@
 package engine
 
 import (
-	"context"
 	"crypto/md5"
 	"fmt"
 	json "github.com/json-iterator/go"
-	"ps-go/consts"
 	"ps-go/errors"
-	"time"
+	"regexp"
+	"strings"
 )
 
+var cacheKeyReg = regexp.MustCompile(`\{(\w|\.)+\}`)
+
 type runCache struct {
 	*runtime
 }
@@ -18,29 +19,58 @@ type cacheData struct {
 	Data any `json:"data"`
 }
 
+// ruleKey 获取当前流程的标志
+func (r *runtime) ruleKey() string {
+	if len(r.stack) == 0 {
+		return ""
+	}
+	return r.stack[len(r.stack)-1]
+}
+
+// cacheKey 获取组件的缓存key，配置了key模板时使用运行存储器中的数据替换模板中的表达式，否则使用组件配置的md5。
+// 替换之后的值经过转义并保留大括号，防止值中包含分隔符时不同的请求得到相同的key
 func (r *runCache) cacheKey() string {
-	byteData, _ := json.Marshal(r.component)
-	return fmt.Sprintf("runtime_%x", md5.Sum(byteData))
+	var suffix string
+	if conf := r.component.Cache; conf != nil && conf.Key != "" {
+		suffix = cacheKeyReg.ReplaceAllStringFunc(conf.Key, func(str string) string {
+			return "{" + escapeCacheValue(fmt.Sprint(r.runStore.GetData(str[1:len(str)-1]))) + "}"
+		})
+	} else {
+		byteData, _ := json.Marshal(r.component)
+		suffix = fmt.Sprintf("%x", md5.Sum(byteData))
+	}
+	return CachePrefix(r.ruleKey(), r.component.Name) + suffix
+}
+
+func (r *runCache) cache() Cache {
+	if r.component.Cache == nil {
+		return GetCache("")
+	}
+	return GetCache(r.component.Cache.Backend)
 }
 
 func (r *runCache) getCache() (any, error) {
-	key := r.cacheKey()
-	str, err := r.ctx.Redis(consts.ProcessScheduleCache).Get(context.TODO(), key).Result()
-	if err != nil || str == "" {
+	value, ok := r.cache().Get(r.ctx, r.cacheKey())
+	if !ok {
 		return nil, errors.New("获取缓存失败")
 	}
-	cache := cacheData{}
-	if json.UnmarshalFromString(str, &cache) != nil {
-		return nil, errors.New("缓存数据解析失败")
-	}
-	return cache.Data, nil
+	return value, nil
 }
 
 func (r *runCache) setCache(value any) {
-	key := r.cacheKey()
-	data := cacheData{
-		Data: value,
+	r.cache().Set(r.ctx, r.cacheKey(), value, r.component.Cache.Expire())
+}
+
+// escapeCacheValue 转义缓存key模板中替换的值，字母、数字以及_.-以外的字符编码为%XX
+func escapeCacheValue(str string) string {
+	var builder strings.Builder
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.' || c == '-' {
+			builder.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&builder, "%%%02X", c)
 	}
-	str, _ := json.MarshalToString(data)
-	r.ctx.Redis(consts.ProcessScheduleCache).Set(context.TODO(), key, str, 5*time.Minute)
+	return builder.String()
 }
@@ -114,13 +114,18 @@ func (r *runStore) get(key string, data any) any {
 
 // GetMatchData 获取存在表达式的数据
 func (r *runStore) GetMatchData(m any) any {
+	return getMatchData(r, m)
+}
+
+// getMatchData 将数据中的表达式替换为存储器中的值
+func getMatchData(r RunStore, m any) any {
 	reg := regexp.MustCompile(`\{(\w|\.)+\}`)
 
 	switch m.(type) {
 	case []any:
 		var resp = m.([]any)
 		for key, _ := range resp {
-			resp[key] = r.GetMatchData(resp[key])
+			resp[key] = getMatchData(r, resp[key])
 		}
 		return resp
 
@@ -132,10 +137,61 @@ func (r *runStore) GetMatchData(m any) any {
 	case map[string]any:
 		var resp = m.(map[string]any)
 		for key, _ := range resp {
-			resp[key] = r.GetMatchData(resp[key])
+			resp[key] = getMatchData(r, resp[key])
 		}
 		return resp
 	}
 
 	return m
 }
+
+// scopeStore 局部运行存储器，优先读取局部数据，局部不存在时读取上级存储器
+type scopeStore struct {
+	local  *runStore
+	parent RunStore
+}
+
+func newScopeStore(parent RunStore, data map[string]any) RunStore {
+	return &scopeStore{
+		local:  &runStore{data: data},
+		parent: parent,
+	}
+}
+
+// has 判断数据是否属于局部存储器
+func (s *scopeStore) has(key string) bool {
+	s.local.lock.RLock()
+	defer s.local.lock.RUnlock()
+	_, ok := s.local.data[strings.Split(key, ".")[0]]
+	return ok
+}
+
+func (s *scopeStore) SetData(key string, val any) {
+	if s.has(key) {
+		s.local.SetData(key, val)
+		return
+	}
+	s.parent.SetData(key, val)
+}
+
+func (s *scopeStore) GetData(key string) any {
+	if s.has(key) {
+		return s.local.GetData(key)
+	}
+	return s.parent.GetData(key)
+}
+
+func (s *scopeStore) GetMatchData(m any) any {
+	return getMatchData(s, m)
+}
+
+func (s *scopeStore) GetAll() map[string]any {
+	resp := map[string]any{}
+	for key, val := range s.parent.GetAll() {
+		resp[key] = val
+	}
+	for key, val := range s.local.GetAll() {
+		resp[key] = val
+	}
+	return resp
+}
@@ -1,6 +1,8 @@
 package engine
 
 import (
+	"context"
+	"fmt"
 	json "github.com/json-iterator/go"
 	"github.com/limeschool/gin"
 	"go.uber.org/zap"
@@ -9,6 +11,7 @@ import (
 	"ps-go/model"
 	"ps-go/tools"
 	"ps-go/tools/pool"
+	"strings"
 	"sync"
 	"time"
 )
@@ -25,23 +28,49 @@ type Runner interface {
 	SetStep(index int)
 	SetMethodAndPath(m, p string)
 	SetStepComponentRetry(index int, names []string) error
+	SetFinishComponents(keys []string)
+	SetSignal(signal *WaitSignal)
+	OnRelease(fn func())
+	SetDryRun(mocks map[string]any)
+	Wait()
+	Status() string
+	Log() any
+	Compensate(keys []string) error
 	ResponseType() string
 	ResponseXml() string
+	RunError() error
 	Release()
 }
 
 type runner struct {
-	rule     *Rule  //当前执行的规则
-	copyRule *Rule  //规则的副本，异常恢复时存档
-	count    int    //总的执行层数
-	index    int    //执行索引，控制流程
-	curIndex int    //当前执行层数
-	version  string //执行流程的版本
-	trx      string //请求唯一表示
-	method   string //请求方法
-	path     string //请求路径
+	rule      *Rule       //当前执行的规则
+	copyRule  *Rule       //规则的副本，异常恢复时存档
+	graph     *graph      //组件依赖图
+	count     int         //总的执行层数
+	curIndex  int         //当前执行到的最大层数
+	isBreak   bool        //是否已经中断，中断后不再调度新的组件
+	stepStart []time.Time //每一层的开始执行时间
+	finished  []string    //执行成功的组件标志，按完成顺序排列
+	version   string      //执行流程的版本
+	trx       string      //请求唯一表示
+	method    string      //请求方法
+	path      string      //请求路径
+	parents   []string    //上级流程的标志，子流程调用时用于检测循环调用
+	runErr    error       //流程执行的错误
+	started   bool        //流程是否已经开始执行
+	waiting   []*node     //等待信号的组件节点
+	signal    *WaitSignal //恢复执行时收到的信号
+	onRelease []func()    //流程执行完成并释放运行器时的回调
+	dryRun    *dryRun     //试运行配置，不为nil时为试运行
+
+	parentCtx context.Context    //上级流程的执行上下文，子流程调用时使用
+	runCtx    context.Context    //流程的执行上下文，中断或者超时之后取消
+	cancel    context.CancelFunc //取消流程的执行上下文
 
 	wg       *sync.WaitGroup //运行时锁
+	done     chan struct{}   //流程执行完成通知
+	respDone chan struct{}   //返回结果监听完成通知
+	finish   chan nodeResult //组件执行结果通道
 	response *responseChan   //返回通道
 	err      *errorChan      //错误通道
 	ctx      *gin.Context    //上下文
@@ -52,82 +81,240 @@ type runner struct {
 }
 
 func (r *runner) Run() {
+	r.started = true
+	defer close(r.done)
+
+	// 创建流程的执行上下文，中断或者超时之后取消执行中的组件
+	r.runCtx, r.cancel = r.newContext()
+	defer r.cancel()
 	defer func() { // 防止意外Panic
 		if p := recover(); p != nil {
 			r.ctx.Log.Error("recover", zap.Any("panic", p))
 		}
 	}()
 
-	for r.index < r.count {
-		// 设置执行的步数
-		r.logger.SetStep(r.curIndex + 1)
-
-		// 获取当前执行的组件列表
-		componentsCount := len(r.rule.Components[r.curIndex])
+	r.SaveStatus(model.RunStateRunning, nil)
 
-		//当前没有需要执行的则直接跳过
-		if componentsCount == 0 {
-			r.index++
-			r.curIndex++
-			continue
-		}
-
-		// 执行组件脚本/api
-		r.RunComponent(componentsCount)
-		r.curIndex++
-		r.index++
+	if err := r.NewGraph(); err != nil {
+		r.wg.Add(1)
+		r.err.SetAndClose(err, r.wg)
+	} else {
+		r.RunGraph()
 	}
 
+	// 等待组件以及错误处理完成
+	r.wg.Wait()
+
 	// 释放通道
 	r.err.Close()
 	r.response.Close()
 
+	// 存在等待信号的组件时，保存流程等待信号恢复
+	status := r.logger.GetStatus()
+	if status == "" && len(r.waiting) != 0 {
+		status = RunWait
+		r.logger.SetStatus(RunWait)
+		r.SaveWait()
+	}
+
+	// 未出现错误则为成功执行
+	if status == "" {
+		r.logger.SetStatus(RunSuccess)
+	}
+
+	// 流程中断时，逆序补偿已经执行成功的组件
+	if status == RunBreak || status == RunActiveBreak {
+		_ = r.Compensate(r.CompensateKeys())
+	}
+
+	// 异步执行时，等待返回结果处理完成之后存储最终的返回数据
+	if r.rule.IsAsync() {
+		<-r.respDone
+		state := model.RunStateDone
+		if status == RunWait {
+			state = model.RunStateWaiting
+		}
+		r.SaveStatus(state, r.Response())
+	}
+
 	// 存储日志
 	r.logger.SetRunTime()
 	r.SaveLog()
+
+	// 发送回调通知
+	r.Notify()
 }
 
-func (r *runner) RunComponent(count int) {
-	log := r.logger.NewStepLog(r.curIndex+1, count)
-	defer log.SetRunTime(time.Now())
+// newContext 创建流程的执行上下文，设置了流程超时时间时同时设置截止时间
+func (r *runner) newContext() (context.Context, context.CancelFunc) {
+	parent := r.parentCtx
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	if r.rule.Timeout > 0 {
+		return context.WithTimeout(parent, time.Duration(r.rule.Timeout)*time.Second)
+	}
+	return context.WithCancel(parent)
+}
 
-	// 设置需要执行的组件数量
-	r.wg.Add(count)
+// SaveStatus 存储异步流程的执行进度
+func (r *runner) SaveStatus(state string, resp any) {
+	if !r.rule.IsAsync() || len(r.parents) != 0 || r.dryRun != nil {
+		return
+	}
 
-	for i := 0; i < count; i++ {
-		rt, err := r.NewRuntime(log, i)
-		if err != nil {
-			log.SetError(err) // 设置执行层错误
-			r.err.SetAndClose(err, r.wg)
+	status := model.RunStatus{
+		Trx:      r.trx,
+		Method:   r.method,
+		Path:     r.path,
+		Version:  r.version,
+		State:    state,
+		Status:   r.logger.GetStatus(),
+		Step:     r.count,
+		CurStep:  r.curIndex + 1,
+		Response: resp,
+	}
+	if r.runErr != nil {
+		status.Error = r.runErr.Error()
+	}
+	if err := status.Save(r.ctx); err != nil {
+		r.ctx.Log.Error("执行进度存储失败", zap.Any("trx", r.trx), zap.Any("err", err))
+	}
+}
+
+// NewGraph 创建组件依赖图，并初始化每一层的日志
+func (r *runner) NewGraph() error {
+	g, err := newGraph(r.rule)
+	if err != nil {
+		return err
+	}
+
+	r.graph = g
+	r.finish = make(chan nodeResult, g.Len())
+	r.finished = g.FinishKeys()
+	r.stepStart = make([]time.Time, r.count)
+	for i := 0; i < r.count; i++ {
+		if r.logger.GetStepLog(i) == nil {
+			r.logger.NewStepLog(i+1, len(r.rule.Components[i]))
+		}
+	}
+	return nil
+}
+
+// RunGraph 按照依赖关系调度组件，依赖全部完成的组件立即执行
+func (r *runner) RunGraph() {
+	running := 0
+	ready := r.graph.Ready()
+
+	for {
+		for _, n := range ready {
+			r.RunNode(n)
+			running++
+		}
+
+		// 没有执行中的组件，则流程结束
+		if running == 0 {
+			return
+		}
+
+		res := <-r.finish
+		running--
+		r.logger.GetStepLog(res.node.step).SetRunTime(r.stepStart[res.node.step])
+
+		// 等待信号的组件不视为完成，依赖它的组件在收到信号之后才会执行
+		if res.wait {
+			r.waiting = append(r.waiting, res.node)
 			continue
 		}
-		// 异常重启时，存在同一层执行成功的组件，不在执行
-		if rt.component.IsFinish {
-			r.wg.Done()
+
+		// 出现错误之后，不再执行新的组件，等待执行中的组件完成
+		if !res.ok {
+			r.isBreak = true
+		}
+
+		// 记录执行成功的组件，中断时用于补偿
+		if res.ok && !res.skip && !res.node.skip {
+			r.finished = append(r.finished, res.node.key)
+		}
+
+		if r.isBreak {
+			ready = nil
 			continue
 		}
-		_ = pool.Get().Invoke(rt)
+
+		// 分支组件跳过未命中的分支
+		com := r.rule.Components[res.node.step][res.node.action]
+		if (com.Type == ComponentTypeSwitch || com.Type == ComponentTypeWait) && !res.node.skip {
+			r.graph.Branch(res.node, res.branch, com.BranchTargets())
+		}
+		ready = r.graph.Finish(res.node)
 	}
-	r.wg.Wait()
 }
 
-func (r *runner) NewRuntime(log StepLog, action int) (*runtime, error) {
-	com := r.rule.Components[r.curIndex][action]
+// RunNode 执行指定节点的组件
+func (r *runner) RunNode(n *node) {
+	if n.step > r.curIndex {
+		r.curIndex = n.step
+		r.SaveStatus(model.RunStateRunning, nil)
+	}
+	// 设置执行的步数
+	r.logger.SetStep(r.curIndex + 1)
+
+	if r.stepStart[n.step].IsZero() {
+		r.stepStart[n.step] = time.Now()
+	}
+
+	r.graph.Start(n)
+
+	// 未命中分支的组件直接跳过
+	if n.skip {
+		r.SkipNode(n)
+		return
+	}
+
+	r.wg.Add(1)
+	_ = pool.Get().Invoke(r.NewRuntime(r.logger.GetStepLog(n.step), n))
+}
+
+// SkipNode 跳过未命中分支的组件，并记录日志
+func (r *runner) SkipNode(n *node) {
+	log := r.logger.GetStepLog(n.step).NewComponentLog(n.step+1, n.action+1)
+	log.SetRequest(r.rule.Components[n.step][n.action])
+	log.SetSkip(true)
+	r.finish <- nodeResult{node: n, ok: true}
+}
+
+func (r *runner) NewRuntime(log StepLog, n *node) *runtime {
+	com := r.rule.Components[n.step][n.action].Copy()
+
+	// 收到的信号只交给对应的wait组件
+	var signal *WaitSignal
+	if r.signal != nil && r.signal.Key == n.key {
+		signal = r.signal
+	}
+
 	return &runtime{
 		stepLog:      log,
 		trx:          r.trx,
 		wg:           r.wg,
+		node:         n,
+		finish:       r.finish,
 		component:    com,
 		response:     r.response,
 		ctx:          r.ctx,
-		step:         r.curIndex,
-		action:       action,
-		maxRetry:     com.RetryMaxCount,
+		step:         n.step,
+		action:       n.action,
+		maxRetry:     com.MaxRetry(),
 		retryMaxWait: com.RetryMaxWait,
 		store:        r.store,
 		err:          r.err,
 		runStore:     r.runStore,
-	}, nil
+		runCtx:       r.runCtx,
+		signal:       signal,
+		dryRun:       r.dryRun,
+		stack:        append(append([]string{}, r.parents...), RuleKey(r.method, r.path)),
+	}
 }
 
 func (r *runner) NewLogger() {
@@ -140,16 +327,53 @@ func (r *runner) NewLogger() {
 	}
 }
 
+// Release 释放运行器，流程仍在执行时等待执行完成之后再释放
 func (r *runner) Release() {
+	if !r.started {
+		r.release()
+		return
+	}
+
+	select {
+	case <-r.done:
+		r.release()
+	default:
+		go func() {
+			<-r.done
+			r.release()
+		}()
+	}
+}
+
+func (r *runner) release() {
+	for _, fn := range r.onRelease {
+		fn()
+	}
+	r.onRelease = nil
 	r.trx = ""
 	r.version = ""
 	r.rule = nil
 	r.copyRule = nil
+	r.graph = nil
 	r.count = 0
-	r.index = 0
 	r.curIndex = 0
+	r.isBreak = false
+	r.stepStart = nil
+	r.finished = nil
+	r.parents = nil
+	r.runErr = nil
+	r.started = false
+	r.waiting = nil
+	r.signal = nil
+	r.dryRun = nil
+	r.parentCtx = nil
+	r.runCtx = nil
+	r.cancel = nil
+	r.done = nil
+	r.respDone = nil
 	r.runStore = nil
 	r.wg = nil
+	r.finish = nil
 	r.store = nil
 	r.response = nil
 	r.ctx = nil
@@ -159,7 +383,7 @@ func (r *runner) Release() {
 
 func (r *runner) NewLoggerFromString(str string) {
 	log := &runLog{}
-	if json.UnmarshalFromString(str, log) != nil {
+	if json.UnmarshalFromString(str, log) == nil {
 		log.start, _ = time.Parse(LogDatetimeFormat, log.StartDatetime)
 		log.CurStep = log.CurStep - 1
 	}
@@ -168,6 +392,7 @@ func (r *runner) NewLoggerFromString(str string) {
 
 // WaitResponse 监听当前流程返回事件，只监听一次，不中断流程
 func (r *runner) WaitResponse() {
+	defer close(r.respDone)
 	defer r.logger.SetResponseTime()
 
 	// 拿到了就删除返回通道，只能返回一次
@@ -180,26 +405,22 @@ func (r *runner) WaitResponse() {
 
 // WaitError 监听当前流程错误事件，只监听一次，并且中断流程执行
 func (r *runner) WaitError() {
-	var err error
-
-	// 设置请求状态
-	defer func() {
-		r.SetStatus(err)
-	}()
-
 	// 监听等待错误中断事件
 	err, is := r.err.Get()
 	if !is || err == nil {
 		return
 	}
 
-	r.SetError(err)
-	r.Suspend(err)
-
 	//当遇到报错时，应该先处理完事物才done 否则无法准确中断流程执行。
 	defer r.wg.Done()
-	// 中断执行流程
-	r.index = r.count
+
+	// 取消执行中的组件，不再等待同层的其他组件执行完成
+	r.cancel()
+
+	r.runErr = err
+	r.SetError(err)
+	r.SetStatus(err)
+	r.Suspend(err)
 
 	// 处理返回值
 	if !r.response.IsClose() {
@@ -207,9 +428,18 @@ func (r *runner) WaitError() {
 	}
 }
 
-// SetStep 设置流程当前的层数
+// SetStep 设置流程从指定层开始执行，之前层的组件视为已完成
 func (r *runner) SetStep(index int) {
-	r.index = index
+	for i := 0; i < index && i < len(r.rule.Components); i++ {
+		for key := range r.rule.Components[i] {
+			r.rule.Components[i][key].IsFinish = true
+		}
+	}
+}
+
+// RuleKey 流程的唯一标志
+func RuleKey(method, path string) string {
+	return fmt.Sprintf("%v:%v", strings.ToUpper(method), strings.TrimLeft(path, "/"))
 }
 
 // SetMethodAndPath 设置组件的请求方法以及path
@@ -218,6 +448,11 @@ func (r *runner) SetMethodAndPath(m, p string) {
 	r.method = m
 }
 
+// OnRelease 设置流程执行完成之后的回调，异步执行时在流程真正结束之后调用
+func (r *runner) OnRelease(fn func()) {
+	r.onRelease = append(r.onRelease, fn)
+}
+
 // SetStepComponentRetry 设置需要重新执行的组件
 func (r *runner) SetStepComponentRetry(index int, names []string) error {
 	if len(r.rule.Components) <= index {
@@ -231,6 +466,17 @@ func (r *runner) SetStepComponentRetry(index int, names []string) error {
 	return nil
 }
 
+// SetFinishComponents 设置已经完成的组件，异常恢复时只执行未完成的组件
+func (r *runner) SetFinishComponents(keys []string) {
+	for step, list := range r.rule.Components {
+		for key, com := range list {
+			if tools.InList(keys, NodeKey(step, com.Name)) {
+				r.rule.Components[step][key].IsFinish = true
+			}
+		}
+	}
+}
+
 // ResponseError 错误信息分类发送到返回器
 func (r *runner) ResponseError(err error) {
 	if e, ok := err.(*gin.CustomError); ok {
@@ -253,7 +499,7 @@ func (r *runner) ResponseXml() string {
 	}
 	body := r.rule.Response.Body
 	if body != nil {
-		resp = r.runStore.GetMatchData(r.rule.Response.Body)
+		resp = r.runStore.GetMatchData(tools.CopyData(body))
 	}
 	xmlStr := ""
 	switch resp.(type) {
@@ -276,7 +522,7 @@ func (r *runner) Response() any {
 
 	body := r.rule.Response.Body
 	if body != nil {
-		resp = r.runStore.GetMatchData(r.rule.Response.Body)
+		resp = r.runStore.GetMatchData(tools.CopyData(body))
 	}
 
 	// 设置返回的数据
@@ -298,14 +544,17 @@ func (r *runner) GetDataToString() string {
 
 // GetComponentErrorNames 获取挂起时错误的组件名称
 func (r *runner) GetComponentErrorNames() string {
-	log := r.logger.GetStepLog(r.curIndex)
-	if log == nil {
-		r.ctx.Log.Error("GetComponentErrorNames Error", zap.Any("index", r.curIndex))
+	names := r.logger.GetComponentErrorNames()
+	str, _ := json.MarshalToString(names)
+	return str
+}
+
+// GetFinishComponents 获取挂起时已经完成的组件
+func (r *runner) GetFinishComponents() string {
+	if r.graph == nil {
 		return "[]"
 	}
-
-	names := log.GetComponentErrorNames()
-	str, _ := json.MarshalToString(names)
+	str, _ := json.MarshalToString(r.graph.FinishKeys())
 	return str
 }
 
@@ -316,6 +565,11 @@ func (r *runner) Suspend(err error) {
 		return
 	}
 
+	// 子流程由上级流程统一挂起，试运行不存储挂起任务
+	if len(r.parents) != 0 || r.dryRun != nil {
+		return
+	}
+
 	// 在设置了挂起的情况下，中断错误则直接返回
 	var code = DefaultErrorCode
 	if e, ok := err.(*Error); ok {
@@ -327,20 +581,21 @@ func (r *runner) Suspend(err error) {
 
 	// 进行任务存库
 	suspendLog := model.SuspendLog{
-		Trx:          r.trx,
-		LogID:        r.ctx.TraceID,
-		Method:       r.method,
-		Path:         r.path,
-		Version:      r.version,
-		Step:         r.count,
-		CurStep:      r.curIndex + 1,
-		ErrCode:      code,
-		ErrMsg:       err.Error(),
-		Rule:         r.GetRuleToString(),
-		Data:         r.GetDataToString(),
-		ErrComponent: r.GetComponentErrorNames(),
-	}
-	if err = suspendLog.Create(r.ctx); err != nil {
+		Trx:             r.trx,
+		LogID:           r.ctx.TraceID,
+		Method:          r.method,
+		Path:            r.path,
+		Version:         r.version,
+		Step:            r.count,
+		CurStep:         r.curIndex + 1,
+		ErrCode:         code,
+		ErrMsg:          err.Error(),
+		Rule:            r.GetRuleToString(),
+		Data:            r.GetDataToString(),
+		ErrComponent:    r.GetComponentErrorNames(),
+		FinishComponent: r.GetFinishComponents(),
+	}
+	if err = suspendLog.Save(r.ctx); err != nil {
 		r.ctx.Log.Error("流程存储失败：%v", zap.Any("trx", r.trx), zap.Any("err", err))
 	}
 }
@@ -373,10 +628,15 @@ func (r *runner) SetStatus(err error) {
 // SaveLog 存储请求链日志
 func (r *runner) SaveLog() {
 
+	// 子流程的日志记录在上级流程的组件日志中
+	if len(r.parents) != 0 {
+		return
+	}
+
 	msg := r.logger.Get()
 	r.ctx.Log.Info("link log", zap.Any("data", msg))
 
-	if !r.rule.Record {
+	if !r.rule.Record || r.dryRun != nil {
 		return
 	}
 
@@ -394,7 +654,7 @@ func (r *runner) SaveLog() {
 		Status:  r.logger.GetStatus(),
 	}
 
-	if err := log.Create(r.ctx); err != nil {
+	if err := log.Save(r.ctx); err != nil {
 		r.ctx.Log.Error("执行流程存储失败：%v", zap.Any("trx", r.trx), zap.Any("err", err))
 	}
 }
@@ -418,3 +678,9 @@ func (r *runner) SetError(err error) {
 func (r *runner) ResponseType() string {
 	return r.rule.Response.Type
 }
+
+// RunError 获取流程执行的错误，流程中断或者执行失败时不为nil。
+// 错误由WaitError异步设置，只有流程执行完成之后才能确定，需要在OnRelease中获取
+func (r *runner) RunError() error {
+	return r.runErr
+}
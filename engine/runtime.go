@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"context"
 	json2 "encoding/json"
 	"fmt"
 	json "github.com/json-iterator/go"
@@ -20,19 +21,34 @@ import (
 )
 
 type runtime struct {
-	vm           *otto.Otto      // js 运行虚拟器
-	wg           *sync.WaitGroup // 运行时锁，与runner共用一个锁
-	component    Component       // 运行组件信息
-	ctx          *gin.Context    // 上下文
-	retry        int             // 重试次数
-	maxRetry     int             // 最大重试次数
-	retryMaxWait int             // 重试最大等待时长
-	action       int             // 当前所在步数
-	step         int             // 当前所在层级
-	response     *responseChan   // 返回通道
-	err          *errorChan      // 错误通道
-	version      string          // 当前运行的版本
-	trx          string          // 请求唯一标志
+	vm           *otto.Otto        // js 运行虚拟器
+	wg           *sync.WaitGroup   // 运行时锁，与runner共用一个锁
+	node         *node             // 所在依赖图节点
+	finish       chan<- nodeResult // 组件执行结果通道
+	component    Component         // 运行组件信息
+	ctx          *gin.Context      // 上下文
+	retry        int               // 重试次数
+	maxRetry     int               // 最大重试次数
+	retryMaxWait int               // 重试最大等待时长
+	action       int               // 当前所在步数
+	step         int               // 当前所在层级
+	response     *responseChan     // 返回通道
+	err          *errorChan        // 错误通道
+	version      string            // 当前运行的版本
+	trx          string            // 请求唯一标志
+	branch       string            // 分支组件命中的目标
+	stack        []string          // 当前流程以及上级流程的标志
+	skip         bool              // 是否因为准入条件未通过而跳过
+	wait         bool              // 是否进入等待信号
+	signal       *WaitSignal       // 恢复执行时收到的信号，仅wait组件使用
+	dryRun       *dryRun           // 试运行配置，不为nil时为试运行
+	runCtx       context.Context   // 流程的执行上下文，流程中断或者超时之后取消
+	statusCode   int               // 本次执行api返回的状态码
+	unmatched    bool              // 本次执行api返回不满足responseCondition
+	lastWait     time.Duration     // 上一次重试的等待时长
+	firstStart   time.Time         // 第一次执行的开始时间
+	attemptLog   AttemptLog        // 本次执行的日志
+	fallback     bool              // 本次执行因上游服务熔断返回了降级数据
 
 	runStore     RunStore     // 运行存储器
 	store        Store        // 全局存储器
@@ -60,26 +76,43 @@ func (r *runtime) Run() {
 	defer func() { // 防止意外Panic
 		if p := recover(); p != nil {
 			r.ctx.Log.Error("recover", zap.Any("panic", p))
+			r.err.SetAndClose(NewSystemPanicError(fmt.Sprint(p)), r.wg)
+			r.done(false)
 		}
 	}()
 
+	// 流程已经中断或者超时，不再执行
+	if r.runCtx.Err() != nil {
+		r.err.SetAndClose(r.contextError(), r.wg)
+		r.done(false)
+		return
+	}
+
 	var resp any
 	var err error
 
-	// 创建组件日志
-	r.componentLog = r.stepLog.NewComponentLog(r.step, r.action)
+	// 创建组件日志，重试时沿用同一个组件日志
+	if r.componentLog == nil {
+		r.componentLog = r.stepLog.NewComponentLog(r.step, r.action)
+	}
 	// 设置组件日志
-	defer r.setLog(resp, err, time.Now())
+	defer func(t time.Time) {
+		r.setLog(resp, err, t)
+	}(time.Now())
 
 	// 判断是否跳过
 	entry, err := r.GetConditionResult(r.component.Condition, nil)
 	if err != nil {
 		r.err.SetAndClose(err, r.wg)
+		r.done(false)
+		return
 	}
 
 	if !entry {
+		r.skip = true
 		r.componentLog.SetSkip(true)
 		r.wg.Done()
+		r.done(true)
 		return
 	}
 	// 进行任务执行
@@ -88,19 +121,23 @@ func (r *runtime) Run() {
 
 	//判断是否使用缓存
 	cache := r.newRunCache()
-	if r.component.IsCache { //从缓存读取数据
+	if r.useCache() { //从缓存读取数据
 		if resp, err = cache.getCache(); err == nil {
 			r.componentLog.SetOutputData(resp)
 			r.runStore.SetData(r.component.OutputName, resp)
 			r.wg.Done()
+			r.done(true)
 			return
 		}
 	}
 
-	if r.component.Type == ComponentTypeApi {
-		resp, err = r.runApi()
-	} else {
-		resp, err = r.runScript()
+	resp, err = r.attempt()
+
+	// 进入等待信号，依赖当前组件的组件暂不执行
+	if err == nil && r.wait {
+		r.wg.Done()
+		r.done(true)
+		return
 	}
 
 	//处理请求异常
@@ -108,23 +145,27 @@ func (r *runtime) Run() {
 		// 设置执行错误日志
 		r.componentLog.SetError(err)
 
-		if r.retry <= r.maxRetry && r.IsRetry(err) {
-			if r.retryMaxWait != 0 {
-				time.AfterFunc(r.getWaitTime(r.retry, r.maxRetry, r.retryMaxWait), func() {
+		if wait, is := r.nextRetry(err); is {
+			r.retry++
+			if wait != 0 {
+				// 等待期间流程中断或者超时时立即重新执行，由Run返回中断错误，不再等待完整的重试间隔
+				go func() {
+					r.sleep(wait)
 					_ = pool.Get().Invoke(r)
-				})
+				}()
 			} else {
 				_ = pool.Get().Invoke(r)
 			}
-			r.retry++
 		} else {
 			r.err.SetAndClose(err, r.wg)
+			r.done(false)
 		}
 
 		return
 	}
 
-	if r.component.OutputData != nil {
+	// 降级数据直接作为输出数据
+	if r.component.OutputData != nil && !r.fallback {
 		resp = r.GetOutputData(r.component.OutputData, resp)
 	}
 
@@ -132,7 +173,7 @@ func (r *runtime) Run() {
 		r.runStore.SetData(r.component.OutputName, resp)
 		r.componentLog.SetOutputData(resp)
 
-		if r.component.IsCache {
+		if r.useCache() && !r.fallback {
 			cache.setCache(resp)
 		}
 	}
@@ -141,6 +182,124 @@ func (r *runtime) Run() {
 		r.response.SetAndClose(nil)
 	}
 	r.wg.Done()
+	r.done(true)
+}
+
+// runSync 同步执行组件，失败时按照重试配置进行重试，用于foreach单项以及补偿组件
+func (r *runtime) runSync() (resp any, err error) {
+	defer func(t time.Time) {
+		r.setLog(resp, err, t)
+	}(time.Now())
+
+	if r.runCtx.Err() != nil {
+		return nil, r.contextError()
+	}
+
+	entry, err := r.GetConditionResult(r.component.Condition, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !entry {
+		r.componentLog.SetSkip(true)
+		return nil, nil
+	}
+
+	r.transferData()
+
+	cache := r.newRunCache()
+	if r.useCache() {
+		if resp, err = cache.getCache(); err == nil {
+			r.componentLog.SetOutputData(resp)
+			return resp, nil
+		}
+	}
+
+	for {
+		if resp, err = r.attempt(); err == nil {
+			break
+		}
+		wait, is := r.nextRetry(err)
+		if !is {
+			break
+		}
+		if wait != 0 {
+			r.sleep(wait)
+		}
+		if r.runCtx.Err() != nil {
+			err = r.contextError()
+			break
+		}
+		r.retry++
+	}
+
+	if err != nil {
+		if r.component.IgnoreError {
+			r.componentLog.SetError(err)
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// 降级数据直接作为输出数据
+	if r.component.OutputData != nil && !r.fallback {
+		resp = r.GetOutputData(r.component.OutputData, resp)
+	}
+	r.componentLog.SetOutputData(resp)
+
+	if r.useCache() && !r.fallback {
+		cache.setCache(resp)
+	}
+	return resp, nil
+}
+
+// invoke 根据组件类型执行组件
+func (r *runtime) invoke() (any, error) {
+	switch r.component.Type {
+	case ComponentTypeApi:
+		return r.runApi()
+	case ComponentTypeSwitch:
+		return r.runSwitch()
+	case ComponentTypeForeach:
+		return r.runForeach()
+	case ComponentTypeRule:
+		return r.runRule()
+	case ComponentTypeWait:
+		return r.runWait()
+	default:
+		return r.runScript()
+	}
+}
+
+// done 通知运行器组件执行结束
+func (r *runtime) done(ok bool) {
+	r.finish <- nodeResult{node: r.node, ok: ok, branch: r.branch, skip: r.skip, wait: r.wait}
+}
+
+// runSwitch 执行分支组件，按顺序匹配分支条件，未命中时使用默认目标
+func (r *runtime) runSwitch() (any, error) {
+	r.branch = r.component.Default
+	index := -1
+
+	for key, item := range r.component.Cases {
+		is, err := r.GetConditionResult(item.Condition, nil)
+		if err != nil {
+			return nil, err
+		}
+		if is {
+			r.branch = item.Target
+			index = key
+			break
+		}
+	}
+
+	// 记录命中的分支
+	r.componentLog.SetBranch(r.branch)
+	return map[string]any{"target": r.branch, "case": index}, nil
+}
+
+// useCache 是否使用缓存，试运行时不读取也不写入缓存
+func (r *runtime) useCache() bool {
+	return r.dryRun == nil && r.component.UseCache()
 }
 
 func (r *runtime) newRunCache() *runCache {
@@ -165,8 +324,8 @@ func (r *runtime) runApi() (any, error) {
 
 	// 转换auth
 	if len(com.Auth) != 0 {
-		for key, val := range com.Auth {
-			auth[key] = fmt.Sprint(val)
+		for _, val := range com.Auth {
+			auth = append(auth, fmt.Sprint(val))
 		}
 	}
 
@@ -181,6 +340,7 @@ func (r *runtime) runApi() (any, error) {
 		ResponseType: com.ResponseType,
 		RequestType:  com.RequestType,
 		XmlName:      com.XmlName,
+		Ctx:          r.runCtx,
 	}
 
 	if com.Tls != nil {
@@ -201,8 +361,30 @@ func (r *runtime) runApi() (any, error) {
 	// 设置api的请求日志
 	defer r.componentLog.SetApiRequest(request)
 
-	resp, err := request.Result()
+	var err error
+	if r.dryRun != nil {
+		err = r.mockRequest(&request)
+	} else {
+		err = r.doRequest(&request, com.Breaker)
+	}
+	if data, ok := r.fallbackData(err, com.Breaker); ok {
+		r.fallback = true
+		return data, nil
+	}
+	resp := request.ResponseBody()
+
+	// 返回的状态码命中重试配置时视为失败
+	r.statusCode = request.ResponseCode()
+	if policy := r.component.Retry; policy != nil && tools.InList(policy.StatusCodes, r.statusCode) {
+		return nil, NewStatusCodeError(fmt.Sprintf("api返回状态码：%v", r.statusCode))
+	}
+
 	if err != nil {
+		switch err.(type) {
+		case *gin.CustomError, *Error:
+		default:
+			err = NewNetworkError(err.Error())
+		}
 		return resp, err
 	}
 
@@ -219,6 +401,7 @@ func (r *runtime) runApi() (any, error) {
 
 	// 表达式为false
 	if !is {
+		r.unmatched = true
 		reg := regexp.MustCompile(`\{(\w|\.)+}`)
 		if str := reg.FindString(r.component.ErrorMsg); str != "" {
 			return nil, errors.New(fmt.Sprint(tools.GetMapData(str[1:len(str)-1], data)))
@@ -265,10 +448,25 @@ func (r *runtime) runScript() (resp any, err error) {
 	// 设置输出日志版本
 	r.componentLog.SetVersion(version)
 
+	// 试运行时配置了模拟数据则不执行脚本
+	if data, ok := r.dryRun.mock(r.component.Name); ok {
+		return data, nil
+	}
+
+	program, err := compileScript(r.component.Url, version, script)
+	if err != nil {
+		return nil, NewRunScriptError(err.Error())
+	}
+
 	r.vm = otto.New()
-	go r.waitTimeout()
+	r.vm.Interrupt = make(chan func(), 1)
 
-	if _, err = r.vm.Run(script); err != nil {
+	// 脚本执行完成之后，结束超时监听
+	done := make(chan struct{})
+	defer close(done)
+	go r.waitTimeout(done)
+
+	if _, err = r.vm.Run(program); err != nil {
 		return nil, NewRunScriptError(err.Error())
 	}
 
@@ -308,20 +506,47 @@ func (r *runtime) transferData() {
 
 }
 
-// waitTimeout 监听等待超时
-func (r *runtime) waitTimeout() {
-	r.vm.Interrupt = make(chan func(), 1)
-
-	if r.component.Timeout <= 0 || r.component.Timeout > consts.ComponentExecSecond {
-		r.component.Timeout = consts.ComponentExecSecond
+// waitTimeout 监听等待超时，脚本超时或者流程取消时中断脚本执行
+func (r *runtime) waitTimeout(done <-chan struct{}) {
+	timeout := r.component.Timeout
+	if timeout <= 0 || timeout > consts.ComponentExecSecond {
+		timeout = consts.ComponentExecSecond
 	}
 
+	timer := time.NewTimer(time.Duration(timeout) * time.Second)
+	defer timer.Stop()
+
 	// 监听超时时间
 	select {
-	case <-time.After(time.Duration(r.component.Timeout) * time.Second):
+	case <-done:
+	case <-timer.C:
 		r.vm.Interrupt <- func() {
 			panic(errors.NewF("run script %v timeout", r.component.Url))
 		}
+	case <-r.runCtx.Done():
+		err := r.contextError()
+		r.vm.Interrupt <- func() {
+			panic(err)
+		}
+	}
+}
+
+// contextError 流程执行上下文取消的原因
+func (r *runtime) contextError() error {
+	if r.runCtx.Err() == context.DeadlineExceeded {
+		return NewDeadlineError("流程执行超时")
+	}
+	return NewCanceledError("流程已中断，取消执行")
+}
+
+// sleep 等待重试间隔，流程中断或者超时之后立即返回
+func (r *runtime) sleep(wait time.Duration) {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-r.runCtx.Done():
 	}
 }
 
@@ -342,11 +567,32 @@ func (r *runtime) getWaitTime(cur, max, wait int) time.Duration {
 	}
 }
 
+// IsRetry 判断错误是否需要重试，网络错误默认重试，其他错误按照重试策略判断
 func (r *runtime) IsRetry(err error) bool {
-	if e, ok := err.(*Error); ok {
-		return e.Code == NetworkErrorCode
+	// 流程中断或者超时之后不再重试
+	if r.runCtx.Err() != nil {
+		return false
+	}
+
+	if e, ok := err.(*Error); ok && e.Code == NetworkErrorCode {
+		return true
+	}
+
+	policy := r.component.Retry
+	if policy == nil {
+		return false
+	}
+
+	// 状态码错误只有命中重试配置时才会产生
+	if e, ok := err.(*Error); ok && e.Code == StatusCodeErrorCode {
+		return true
 	}
-	return false
+
+	if policy.OnCondition && r.unmatched {
+		return true
+	}
+
+	return tools.InList(policy.ErrorCodes, errorCode(err))
 }
 
 // GetConditionResult 获取表达式结果
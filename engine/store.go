@@ -7,6 +7,18 @@ import (
 	"ps-go/model"
 )
 
+const (
+	StoreDriverMysql = "mysql" //从数据库加载规则以及脚本
+	StoreDriverFile  = "file"  //从文件目录加载规则以及脚本
+)
+
+// StoreConfig 存储器配置，对应配置文件中的store字段
+type StoreConfig struct {
+	Driver string //存储方式 [mysql|file]，默认为mysql
+	Path   string //driver=file时的规则目录
+	Watch  bool   //driver=file时是否监听文件变更自动重新加载
+}
+
 type store struct {
 }
 
@@ -14,16 +26,50 @@ func NewStore() *store {
 	return &store{}
 }
 
+// NewStoreByConfig 按照配置创建存储器
+func NewStoreByConfig(ctx *gin.Context) (Store, error) {
+	conf := StoreConfig{}
+	if err := ctx.Config.UnmarshalKey("store", &conf); err != nil {
+		return nil, err
+	}
+
+	switch conf.Driver {
+	case "", StoreDriverMysql:
+		return NewCanaryStore(NewCacheStore(ctx, NewStore())), nil
+	case StoreDriverFile:
+		return NewFileStore(ctx, conf.Path, conf.Watch)
+	default:
+		return nil, errors.NewF("不支持的存储方式：%v", conf.Driver)
+	}
+}
+
+// Driver 获取规则以及脚本的存储方式
+func (e engine) Driver() string {
+	if _, ok := e.Store.(*fileStore); ok {
+		return StoreDriverFile
+	}
+	return StoreDriverMysql
+}
+
+// manageStore 管理api使用的存储器，管理api始终读写mysql，使用文件存储时同样从mysql加载校验规则时引用的数据
+func (e engine) manageStore() Store {
+	if e.Driver() == StoreDriverFile {
+		return NewStore()
+	}
+	return e.Store
+}
+
 type Store interface {
 	LoadRule(ctx *gin.Context, method, path string) (*Rule, error)
 	LoadScript(ctx *gin.Context, name string) (string, string, error)
+	LoadLimit(ctx *gin.Context, method, path string, rule *Rule) (*Limit, error)
 }
 
 // LoadRule 获取指定规则
 func (s *store) LoadRule(ctx *gin.Context, method, path string) (*Rule, error) {
 	rule := model.Rule{}
 	if err := rule.OneByNameMethod(ctx, path, method); err != nil {
-		return nil, errors.NewF("不存在流程：%v->%v", ctx.Request.Method, path)
+		return nil, errors.NewF("不存在流程：%v->%v", method, path)
 	}
 
 	er := Rule{Version: rule.Version}
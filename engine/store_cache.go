@@ -0,0 +1,220 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"github.com/limeschool/gin"
+	"github.com/robertkrimen/otto"
+	"ps-go/consts"
+	"ps-go/errors"
+	"ps-go/model"
+	"strings"
+	"sync"
+	"time"
+)
+
+type ruleEntry struct {
+	rule     *Rule
+	expireAt time.Time
+}
+
+type canaryEntry struct {
+	canary   *Canary
+	expireAt time.Time
+}
+
+type scriptEntry struct {
+	script   string
+	version  string
+	expireAt time.Time
+}
+
+// cacheStore 进程内缓存解析之后的规则以及脚本，减少每次请求的redis查询以及反序列化。
+// 规则或者脚本变更时，通过redis通知全部实例清理缓存，缓存key与redis中的缓存key保持一致
+type cacheStore struct {
+	Store
+	lock     sync.RWMutex
+	gen      uint64 //清理次数，防止加载期间发生变更时写入旧数据
+	rules    map[string]ruleEntry
+	scripts  map[string]scriptEntry
+	canaries map[string]canaryEntry
+}
+
+// NewCacheStore 为存储器增加进程内缓存
+func NewCacheStore(ctx *gin.Context, st Store) *cacheStore {
+	s := &cacheStore{
+		Store:    st,
+		rules:    map[string]ruleEntry{},
+		scripts:  map[string]scriptEntry{},
+		canaries: map[string]canaryEntry{},
+	}
+	go s.listen(ctx)
+	return s
+}
+
+// LoadRule 获取指定规则，缓存中的规则保持不变，只复制流程执行时会修改的部分
+func (s *cacheStore) LoadRule(ctx *gin.Context, method, path string) (*Rule, error) {
+	key := (&model.Rule{}).CacheKey(fmt.Sprintf("%v:%v", path, strings.ToUpper(method)))
+
+	s.lock.RLock()
+	entry, ok := s.rules[key]
+	gen := s.gen
+	s.lock.RUnlock()
+
+	if !ok || time.Now().After(entry.expireAt) {
+		rule, err := s.Store.LoadRule(ctx, method, path)
+		if err != nil {
+			return nil, err
+		}
+		entry = ruleEntry{rule: rule, expireAt: time.Now().Add(consts.StoreCacheExpire)}
+
+		s.lock.Lock()
+		if gen == s.gen {
+			s.rules[key] = entry
+		}
+		s.lock.Unlock()
+	}
+
+	return entry.rule.Copy(), nil
+}
+
+// LoadScript 获取指定脚本
+func (s *cacheStore) LoadScript(ctx *gin.Context, name string) (string, string, error) {
+	key := (&model.Script{}).CacheKey(name)
+
+	s.lock.RLock()
+	entry, ok := s.scripts[key]
+	gen := s.gen
+	s.lock.RUnlock()
+
+	if ok && time.Now().Before(entry.expireAt) {
+		return entry.script, entry.version, nil
+	}
+
+	script, version, err := s.Store.LoadScript(ctx, name)
+	if err != nil {
+		return "", "", err
+	}
+
+	s.lock.Lock()
+	if gen == s.gen {
+		s.scripts[key] = scriptEntry{script: script, version: version, expireAt: time.Now().Add(consts.StoreCacheExpire)}
+	}
+	s.lock.Unlock()
+	return script, version, nil
+}
+
+// LoadRuleByVersion 获取指定版本的规则，版本的内容不会变更，与启用中的规则共用缓存
+func (s *cacheStore) LoadRuleByVersion(ctx *gin.Context, version string) (*Rule, error) {
+	src, ok := s.Store.(canarySource)
+	if !ok {
+		return nil, errors.NewF("存储器不支持按照版本加载规则")
+	}
+	key := (&model.Rule{}).CacheKey(version)
+
+	s.lock.RLock()
+	entry, ok := s.rules[key]
+	gen := s.gen
+	s.lock.RUnlock()
+
+	if !ok || time.Now().After(entry.expireAt) {
+		rule, err := src.LoadRuleByVersion(ctx, version)
+		if err != nil {
+			return nil, err
+		}
+		entry = ruleEntry{rule: rule, expireAt: time.Now().Add(consts.StoreCacheExpire)}
+
+		// 加载期间版本被删除时不写入缓存
+		s.lock.Lock()
+		if gen == s.gen {
+			s.rules[key] = entry
+		}
+		s.lock.Unlock()
+	}
+
+	return entry.rule.Copy(), nil
+}
+
+// LoadCanary 获取规则的灰度配置，不存在灰度配置时同样进行缓存
+func (s *cacheStore) LoadCanary(ctx *gin.Context, method, path string) (*Canary, error) {
+	src, ok := s.Store.(canarySource)
+	if !ok {
+		return nil, nil
+	}
+	key := (&model.RuleCanary{}).CacheKey(path, method)
+
+	s.lock.RLock()
+	entry, ok := s.canaries[key]
+	gen := s.gen
+	s.lock.RUnlock()
+
+	if ok && time.Now().Before(entry.expireAt) {
+		return entry.canary, nil
+	}
+
+	canary, err := src.LoadCanary(ctx, method, path)
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	if gen == s.gen {
+		s.canaries[key] = canaryEntry{canary: canary, expireAt: time.Now().Add(consts.StoreCacheExpire)}
+	}
+	s.lock.Unlock()
+	return canary, nil
+}
+
+// purge 清理指定key的缓存
+func (s *cacheStore) purge(key string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.gen++
+	delete(s.rules, key)
+	delete(s.scripts, key)
+	delete(s.canaries, key)
+}
+
+// listen 监听规则、脚本的变更通知
+func (s *cacheStore) listen(ctx *gin.Context) {
+	client := ctx.Redis(consts.ProcessScheduleCache)
+	if client == nil {
+		return
+	}
+
+	sub := client.Subscribe(context.Background(), consts.StoreChangeChannel)
+	for msg := range sub.Channel() {
+		s.purge(msg.Payload)
+	}
+}
+
+type program struct {
+	version string
+	script  *otto.Script
+}
+
+var programs = struct {
+	lock sync.RWMutex
+	data map[string]program
+}{data: map[string]program{}}
+
+// compileScript 获取编译之后的脚本，同一个版本的脚本只编译一次，编译结果可以在多个vm中重复执行
+func compileScript(name, version, src string) (*otto.Script, error) {
+	programs.lock.RLock()
+	p, ok := programs.data[name]
+	programs.lock.RUnlock()
+	if ok && p.version == version {
+		return p.script, nil
+	}
+
+	script, err := otto.New().Compile(name, src)
+	if err != nil {
+		return nil, err
+	}
+
+	programs.lock.Lock()
+	programs.data[name] = program{version: version, script: script}
+	programs.lock.Unlock()
+	return script, nil
+}
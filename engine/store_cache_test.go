@@ -0,0 +1,131 @@
+package engine
+
+import (
+	"fmt"
+	json "github.com/json-iterator/go"
+	"github.com/limeschool/gin"
+	"testing"
+)
+
+// jsonStore 模拟未缓存的存储器，每次加载都重新解析规则
+type jsonStore struct {
+	Store
+	rule []byte
+}
+
+func (s *jsonStore) LoadRule(ctx *gin.Context, method, path string) (*Rule, error) {
+	rule := &Rule{}
+	return rule, json.Unmarshal(s.rule, rule)
+}
+
+func newJsonStore(tb testing.TB) *jsonStore {
+	tb.Helper()
+	rule := &Rule{
+		Version:  "v1",
+		Response: Response{Body: map[string]any{"code": 0, "data": "{a.data}"}},
+	}
+	for i := 0; i < 5; i++ {
+		var list []Component
+		for j := 0; j < 3; j++ {
+			list = append(list, Component{
+				Name:       fmt.Sprintf("c%v_%v", i, j),
+				Type:       ComponentTypeApi,
+				Url:        "http://127.0.0.1/api",
+				Method:     "POST",
+				Header:     map[string]any{"token": "{header.token}"},
+				Input:      map[string]any{"id": "{body.id}", "list": []any{"{body.a}", map[string]any{"b": "{body.b}"}}},
+				OutputData: map[string]any{"data": "{response.data}"},
+			})
+		}
+		rule.Components = append(rule.Components, list)
+	}
+
+	data, err := json.Marshal(rule)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return &jsonStore{rule: data}
+}
+
+func newTestCacheStore(st Store) *cacheStore {
+	return &cacheStore{
+		Store:    st,
+		rules:    map[string]ruleEntry{},
+		scripts:  map[string]scriptEntry{},
+		canaries: map[string]canaryEntry{},
+	}
+}
+
+func TestCacheStoreLoadRuleImmutable(t *testing.T) {
+	st := newTestCacheStore(newJsonStore(t))
+
+	rule, err := st.LoadRule(nil, "POST", "/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 模拟流程执行期间对规则的修改
+	rule.Record = true
+	rule.Components[0][0].IsFinish = true
+	com := rule.Components[0][1].Copy()
+	com.Input.(map[string]any)["id"] = 1
+	com.Input.(map[string]any)["list"].([]any)[1].(map[string]any)["b"] = 2
+	com.Header["token"] = "token"
+	com.OutputData.(map[string]any)["data"] = 3
+	rule.Components[1][0].Name = "changed"
+
+	cached := st.rules["rule_/test:POST"].rule
+	if cached.Record || cached.Components[0][0].IsFinish || cached.Components[1][0].Name != "c1_0" {
+		t.Fatalf("cached rule changed: %+v", cached)
+	}
+	origin := cached.Components[0][1]
+	if origin.Input.(map[string]any)["id"] != "{body.id}" ||
+		origin.Input.(map[string]any)["list"].([]any)[1].(map[string]any)["b"] != "{body.b}" ||
+		origin.Header["token"] != "{header.token}" ||
+		origin.OutputData.(map[string]any)["data"] != "{response.data}" {
+		t.Fatalf("cached component changed: %+v", origin)
+	}
+
+	again, err := st.LoadRule(nil, "POST", "/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Record || again.Components[0][0].IsFinish {
+		t.Fatalf("reloaded rule changed: %+v", again)
+	}
+}
+
+// BenchmarkLoadRule 对比使用缓存与每次解析规则的开销，缓存的规则同样需要复制组件
+func BenchmarkLoadRule(b *testing.B) {
+	b.Run("uncached", func(b *testing.B) {
+		st := newJsonStore(b)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			rule, err := st.LoadRule(nil, "POST", "/test")
+			if err != nil {
+				b.Fatal(err)
+			}
+			for _, list := range rule.Components {
+				for _, com := range list {
+					_ = com.Copy()
+				}
+			}
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		st := newTestCacheStore(newJsonStore(b))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			rule, err := st.LoadRule(nil, "POST", "/test")
+			if err != nil {
+				b.Fatal(err)
+			}
+			for _, list := range rule.Components {
+				for _, com := range list {
+					_ = com.Copy()
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package engine
+
+import (
+	"ps-go/errors"
+	"ps-go/tools"
+	"strings"
+	"time"
+)
+
+// runRule 执行子流程组件，url为子流程的规则名，method为规则的请求方法，input作为子流程的请求body
+func (r *runtime) runRule() (any, error) {
+	method := r.component.Method
+	if method == "" {
+		method = "POST"
+	}
+	path := strings.TrimLeft(r.component.Url, "/")
+
+	// 检测循环调用
+	key := RuleKey(method, path)
+	if tools.InList(r.stack, key) {
+		return nil, errors.NewF("子流程存在循环调用：%v->%v", strings.Join(r.stack, "->"), key)
+	}
+
+	// 试运行时配置了模拟数据则不执行子流程
+	if data, ok := r.dryRun.mock(r.component.Name); ok {
+		return data, nil
+	}
+
+	rule, err := r.store.LoadRule(r.ctx, method, path)
+	if err != nil {
+		return nil, err
+	}
+	r.version = rule.Version
+	r.componentLog.SetVersion(rule.Version)
+
+	// 校验子流程参数
+	body, _ := r.component.Input.(map[string]any)
+	request, err := Get().NewValidate(rule.Request).BindData(body)
+	if err != nil {
+		return nil, errors.NewF("子流程%v参数校验失败：%v", key, err.Error())
+	}
+
+	// 子流程使用独立的运行存储器
+	runStore := Get().NewRunStore()
+	runStore.SetData("request", request)
+
+	child := Get().NewRunner(r.ctx, rule, runStore).(*runner)
+	defer child.Release()
+
+	child.parents = r.stack
+	child.parentCtx = r.runCtx
+	child.dryRun = r.dryRun.sub(key)
+	child.SetMethodAndPath(method, path)
+	child.NewLogger()
+	child.SetRequestLog(time.Now(), request)
+
+	// 同步执行子流程，等待执行以及返回处理完成
+	done := make(chan struct{})
+	go func() {
+		child.WaitResponse()
+		close(done)
+	}()
+	go child.WaitError()
+	child.Run()
+	<-done
+
+	resp := child.Response()
+	r.componentLog.SetRunLog(child.logger.Get())
+
+	// 子流程的中断以及挂起错误原样传递给上级流程
+	if child.runErr != nil {
+		return nil, child.runErr
+	}
+	return resp, nil
+}
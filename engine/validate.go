@@ -15,15 +15,43 @@ type validate struct {
 
 type Validate interface {
 	Bind(ctx *gin.Context) (map[string]any, error)
+	BindData(body map[string]any) (map[string]any, error)
+	BindRequest(query, header, body map[string]any) (map[string]any, error)
 }
 
 // Bind 绑定参数并校验。
 func (v *validate) Bind(ctx *gin.Context) (map[string]any, error) {
+	return v.check(v.getQuery(ctx), v.getHeader(ctx), v.getBody(ctx))
+}
+
+// BindData 对直接传入的body数据进行校验，用于子流程调用
+func (v *validate) BindData(body map[string]any) (map[string]any, error) {
+	if body == nil {
+		body = make(map[string]any)
+	}
+	return v.check(make(map[string]any), make(map[string]any), body)
+}
+
+// BindRequest 对直接传入的query、header、body数据进行校验，用于流程试运行
+func (v *validate) BindRequest(query, header, body map[string]any) (map[string]any, error) {
+	if query == nil {
+		query = make(map[string]any)
+	}
+	if header == nil {
+		header = make(map[string]any)
+	}
+	if body == nil {
+		body = make(map[string]any)
+	}
+	return v.check(query, header, body)
+}
+
+// check 校验query、header、body参数
+func (v *validate) check(queryMap, headerMap, bodyMap map[string]any) (map[string]any, error) {
 	var value any
 	var exist bool
 
 	// 绑定query
-	queryMap := v.getQuery(ctx)
 	for key, field := range v.request.Query {
 		value, exist = queryMap[key]
 		newVal, ignore, err := field.Validate(value, exist)
@@ -36,7 +64,6 @@ func (v *validate) Bind(ctx *gin.Context) (map[string]any, error) {
 	}
 
 	// 绑定header
-	headerMap := v.getHeader(ctx)
 	for key, field := range v.request.Header {
 		value, exist = headerMap[key]
 		newVal, ignore, err := field.Validate(value, exist)
@@ -49,7 +76,6 @@ func (v *validate) Bind(ctx *gin.Context) (map[string]any, error) {
 	}
 
 	// 绑定body
-	bodyMap := v.getBody(ctx)
 	for key, field := range v.request.Body {
 		value, exist = bodyMap[key]
 		newVal, ignore, err := field.Validate(value, exist)
@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"fmt"
+	json "github.com/json-iterator/go"
+	"go.uber.org/zap"
+	"ps-go/errors"
+	"ps-go/model"
+	"time"
+)
+
+// WaitNode 等待信号的组件
+type WaitNode struct {
+	Key       string `json:"key"`        //组件标志 step.name
+	Signal    string `json:"signal"`     //等待的信号名
+	TimeoutAt int64  `json:"timeout_at"` //超时时间，0为不超时
+}
+
+// WaitSignal 恢复等待流程时传入的信号
+type WaitSignal struct {
+	Key     string     //收到信号的组件标志
+	Data    any        //信号携带的数据
+	Timeout bool       //是否为等待超时
+	Waits   []WaitNode //恢复前等待中的组件，再次等待时沿用原来的超时时间
+}
+
+// runWait 执行等待信号组件，未收到信号时进入等待，
+// 收到信号之后输出信号数据，超时之后跳转到超时目标或者中断流程
+func (r *runtime) runWait() (any, error) {
+	// 子流程的执行不会持久化，不支持等待
+	if len(r.stack) > 1 {
+		return nil, errors.NewF("子流程不支持wait组件%v", r.component.Name)
+	}
+
+	// 试运行时模拟数据作为收到的信号数据
+	if data, ok := r.dryRun.mock(r.component.Name); ok && r.signal == nil {
+		return data, nil
+	}
+
+	if r.signal == nil {
+		r.wait = true
+		r.componentLog.SetWait(true)
+		return nil, nil
+	}
+
+	if r.signal.Timeout {
+		if r.component.TimeoutTarget == "" {
+			return nil, NewBreakError(fmt.Sprintf("等待信号%v超时", r.component.SignalName()))
+		}
+		r.branch = r.component.TimeoutTarget
+		r.componentLog.SetBranch(r.branch)
+		return map[string]any{"timeout": true}, nil
+	}
+
+	return r.signal.Data, nil
+}
+
+// SetSignal 设置恢复执行时收到的信号
+func (r *runner) SetSignal(signal *WaitSignal) {
+	r.signal = signal
+	// 清除等待状态，重新计算执行结果
+	r.logger.SetStatus("")
+}
+
+// SaveWait 存储等待信号的流程，收到信号或者超时之后从等待的组件继续执行
+func (r *runner) SaveWait() {
+	if r.dryRun != nil {
+		return
+	}
+
+	// 上一次等待时的超时时间
+	deadlines := map[string]int64{}
+	if r.signal != nil {
+		for _, item := range r.signal.Waits {
+			deadlines[item.Key] = item.TimeoutAt
+		}
+	}
+
+	var timeoutAt int64
+	var waits []WaitNode
+	for _, n := range r.waiting {
+		com := r.rule.Components[n.step][n.action]
+		item := WaitNode{
+			Key:    n.key,
+			Signal: com.SignalName(),
+		}
+
+		if at, ok := deadlines[n.key]; ok {
+			item.TimeoutAt = at
+		} else if com.WaitTimeout > 0 {
+			item.TimeoutAt = time.Now().Add(time.Duration(com.WaitTimeout) * time.Second).Unix()
+		}
+
+		if item.TimeoutAt != 0 && (timeoutAt == 0 || item.TimeoutAt < timeoutAt) {
+			timeoutAt = item.TimeoutAt
+		}
+		waits = append(waits, item)
+	}
+
+	waitStr, _ := json.MarshalToString(waits)
+	waitLog := model.WaitLog{
+		Trx:             r.trx,
+		LogID:           r.ctx.TraceID,
+		Method:          r.method,
+		Path:            r.path,
+		Version:         r.version,
+		Step:            r.count,
+		CurStep:         r.curIndex + 1,
+		Rule:            r.GetRuleToString(),
+		Data:            r.GetDataToString(),
+		Waits:           waitStr,
+		FinishComponent: r.GetFinishComponents(),
+		TimeoutAt:       timeoutAt,
+	}
+	if err := waitLog.Create(r.ctx); err != nil {
+		r.ctx.Log.Error("等待流程存储失败", zap.Any("trx", r.trx), zap.Any("err", err))
+	}
+}
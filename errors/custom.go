@@ -9,5 +9,8 @@ var (
 	DBDupError      = &gin.CustomError{Code: 100005, Msg: "数据已存在"}
 	DBNotFoundError = &gin.CustomError{Code: 100006, Msg: "数据不存在"}
 
-	RuleNotFoundError = &gin.CustomError{Code: 100100, Msg: "流程规则不存在"}
+	RuleNotFoundError     = &gin.CustomError{Code: 100100, Msg: "流程规则不存在"}
+	RateLimitError        = &gin.CustomError{Code: 100101, Msg: "请求过于频繁，请稍后重试"}
+	ConcurrencyLimitError = &gin.CustomError{Code: 100102, Msg: "执行中的请求过多，请稍后重试"}
+	IdempotentError       = &gin.CustomError{Code: 100103, Msg: "相同的请求正在执行中，请稍后重试"}
 )
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"bytes"
+	"github.com/limeschool/gin"
+	"io"
+	"ps-go/errors"
+	"ps-go/service"
+	"ps-go/types"
+)
+
+// auditWriter 记录返回数据，用于获取操作结果
+type auditWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *auditWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *auditWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Audit 记录后台管理接口的变更操作，只挂载在变更数据的路由上，查询、校验以及试运行等只读接口不挂载
+func Audit(ctx *gin.Context) {
+	var body []byte
+	if ctx.Request.Body != nil {
+		body, _ = io.ReadAll(ctx.Request.Body)
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	writer := &auditWriter{ResponseWriter: ctx.Writer}
+	ctx.Writer = writer
+	ctx.Next()
+
+	service.RecordAudit(ctx, body, writer.body.Bytes())
+}
+
+func GetAuditLog(ctx *gin.Context) {
+	in := types.GetAuditLogRequest{}
+	if ctx.ShouldBind(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if resp, err := service.GetAuditLog(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespData(resp)
+	}
+}
+
+func PageAuditLog(ctx *gin.Context) {
+	in := types.PageAuditLogRequest{}
+	if ctx.ShouldBind(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if resp, total, err := service.PageAuditLog(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespList(in.Page, in.Count, int(total), resp)
+	}
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"github.com/limeschool/gin"
+	"ps-go/errors"
+	"ps-go/service"
+	"ps-go/types"
+)
+
+func ListBreaker(ctx *gin.Context) {
+	in := types.ListBreakerRequest{}
+
+	if ctx.ShouldBind(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if resp, err := service.ListBreaker(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespData(resp)
+	}
+}
+
+func ResetBreaker(ctx *gin.Context) {
+	in := types.ResetBreakerRequest{}
+	if err := ctx.ShouldBindJSON(&in); err != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+	if err := service.ResetBreaker(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespSuccess()
+	}
+}
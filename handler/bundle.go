@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"github.com/limeschool/gin"
+	"ps-go/errors"
+	"ps-go/service"
+	"ps-go/types"
+)
+
+func ExportRule(ctx *gin.Context) {
+	in := types.ExportRuleRequest{}
+	if ctx.ShouldBind(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if in.ID == 0 && in.Name == "" && in.Version == "" {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if resp, err := service.ExportRule(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespData(resp)
+	}
+}
+
+func ImportRule(ctx *gin.Context) {
+	in := types.ImportRuleRequest{}
+	if ctx.ShouldBindJSON(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if resp, err := service.ImportRule(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespData(resp)
+	}
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"github.com/limeschool/gin"
+	"ps-go/errors"
+	"ps-go/service"
+	"ps-go/types"
+)
+
+func ListCache(ctx *gin.Context) {
+	in := types.ListCacheRequest{}
+
+	if ctx.ShouldBind(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if resp, err := service.ListCache(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespData(resp)
+	}
+}
+
+func PurgeCache(ctx *gin.Context) {
+	in := types.PurgeCacheRequest{}
+	if err := ctx.ShouldBindJSON(&in); err != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if count, err := service.PurgeCache(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespData(gin.H{"count": count})
+	}
+}
@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"github.com/limeschool/gin"
+	"ps-go/errors"
+	"ps-go/service"
+	"ps-go/types"
+)
+
+func PageNotifyLog(ctx *gin.Context) {
+	in := types.PageNotifyLogRequest{}
+
+	if ctx.ShouldBind(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if resp, total, err := service.PageNotifyLog(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespList(in.Page, in.Count, int(total), resp)
+	}
+}
@@ -3,8 +3,10 @@ package handler
 import (
 	"fmt"
 	"github.com/limeschool/gin"
+	"net/http"
 	"ps-go/consts"
 	"ps-go/engine"
+	"ps-go/model"
 	"ps-go/tools"
 	"ps-go/tools/pool"
 	"strings"
@@ -15,6 +17,7 @@ func ProcessSchedule(ctx *gin.Context) {
 	startTime := time.Now()
 
 	trx := NewTrx()
+	ctx.Set(consts.ProcessScheduleTrx, trx)
 	ctx.Writer.Header().Set(consts.ProcessScheduleTrx, trx)
 
 	eg := engine.Get()
@@ -35,13 +38,53 @@ func ProcessSchedule(ctx *gin.Context) {
 		return
 	}
 
+	// 幂等控制，重复请求直接返回首次请求的执行结果
+	idem := eg.NewIdempotency(ctx, ctx.Request.Method, path, rule, requestInfo)
+	if idem != nil {
+		record, err := idem.Begin()
+		if err != nil {
+			ctx.RespError(TransferError(err))
+			return
+		}
+		if record != nil {
+			ctx.Writer.Header().Set(consts.ProcessScheduleTrx, record.Trx)
+			ctx.Writer.Header().Set(consts.IdempotentReplay, "true")
+			if record.State == model.IdempotentAccepted {
+				writeResponse(ctx, http.StatusAccepted, consts.RespJson, acceptedResponse(ctx, record.Trx))
+				return
+			}
+			writeResponse(ctx, record.Code, record.ResponseType, record.Response)
+			return
+		}
+	}
+
+	// 限流以及并发控制，被限流时不再执行流程
+	release, reject := eg.AcquireLimit(ctx, ctx.Request.Method, path, rule)
+	if reject != nil {
+		if idem != nil {
+			idem.Abort()
+		}
+		for key, val := range reject.Header {
+			ctx.Writer.Header().Set(key, val)
+		}
+		ctx.JSON(reject.Status, reject.Body)
+		return
+	}
+
 	// 创建请求存储器
 	runStore := eg.NewRunStore()
 	runStore.SetData("request", requestInfo)
 
-	// 创建运行器
-	runner := eg.NewRunner(ctx, rule, runStore)
+	// 异步执行时，请求返回之后流程仍需继续执行，使用独立的上下文
+	runCtx := ctx
+	if rule.IsAsync() {
+		runCtx = tools.CopyContext(ctx)
+	}
+
+	// 创建运行器，流程执行完成之后才会真正释放
+	runner := eg.NewRunner(runCtx, rule, runStore)
 	runner.SetMethodAndPath(ctx.Request.Method, path)
+	runner.OnRelease(release)
 	defer runner.Release()
 
 	// 设置执行日志
@@ -52,23 +95,80 @@ func ProcessSchedule(ctx *gin.Context) {
 	_ = pool.Get().Invoke(runner)
 	// 异步监听错误信息
 	go runner.WaitError()
+
+	// 异步执行模式，立即返回trx，流程执行失败时删除幂等记录，相同幂等键的请求可以重新执行
+	if rule.IsAsync() {
+		go runner.WaitResponse()
+		if idem != nil {
+			idem.Accept()
+			runner.OnRelease(func() {
+				if runner.RunError() != nil {
+					idem.Forget(runCtx)
+				}
+			})
+		}
+		writeResponse(ctx, http.StatusAccepted, consts.RespJson, gin.H{
+			"trx":   trx,
+			"state": model.RunStateRunning,
+		})
+		return
+	}
+
 	// 同步等待返回结果
 	runner.WaitResponse()
 	// 获取返回结果
+	var data any
 	if runner.ResponseType() == consts.RespXml {
-		ctx.Writer.Header().Set("Content-Type", "application/xml")
-		ctx.String(200, runner.ResponseXml())
-		return
+		data = runner.ResponseXml()
+	} else {
+		data = runner.Response()
 	}
 
-	data := runner.Response()
-	if runner.ResponseType() == consts.RespText {
-		ctx.String(200, fmt.Sprint(data))
-		return
+	// 只存储执行成功的结果，中断或者执行失败时相同幂等键的请求可以重新执行。
+	// 立即响应的组件返回结果之后流程仍在执行，需要等待流程执行完成之后才能确定执行结果
+	if idem != nil {
+		responseType := runner.ResponseType()
+		runner.OnRelease(func() {
+			if runner.RunError() != nil {
+				idem.Abort()
+			} else {
+				idem.Finish(http.StatusOK, responseType, data)
+			}
+		})
+	}
+	writeResponse(ctx, http.StatusOK, runner.ResponseType(), data)
+}
+
+// acceptedResponse 异步执行的重复请求，返回首次请求的trx以及当前的执行状态
+func acceptedResponse(ctx *gin.Context, trx string) gin.H {
+	state := model.RunStateRunning
+	status := model.RunStatus{}
+	if status.OneByTrx(ctx, trx) == nil {
+		state = status.State
+	}
+	return gin.H{
+		"trx":   trx,
+		"state": state,
+	}
+}
+
+// writeResponse 按照返回数据类型返回结果
+func writeResponse(ctx *gin.Context, code int, responseType string, data any) {
+	switch responseType {
+	case consts.RespXml:
+		ctx.Writer.Header().Set("Content-Type", "application/xml")
+		ctx.String(code, fmt.Sprint(data))
+	case consts.RespText:
+		ctx.String(code, fmt.Sprint(data))
+	default:
+		if code != http.StatusOK {
+			ctx.JSON(code, data)
+			return
+		}
+		ctx.RespJson(data)
 	}
-	ctx.RespJson(data)
 }
 
 func NewTrx() string {
-	return fmt.Sprintf("TRX%v", tools.UUID())
+	return tools.NewTrx()
 }
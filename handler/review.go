@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"github.com/limeschool/gin"
+	"ps-go/errors"
+	"ps-go/service"
+	"ps-go/types"
+)
+
+func GetReview(ctx *gin.Context) {
+	in := types.GetReviewRequest{}
+	if ctx.ShouldBind(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if review, logs, err := service.GetReview(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespData(gin.H{"review": review, "logs": logs})
+	}
+}
+
+func PageReview(ctx *gin.Context) {
+	in := types.PageReviewRequest{}
+	if ctx.ShouldBind(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if resp, total, err := service.PageReview(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespList(in.Page, in.Count, int(total), resp)
+	}
+}
+
+func SubmitReview(ctx *gin.Context) {
+	in := types.ReviewRequest{}
+	if ctx.ShouldBindJSON(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if err := service.SubmitReview(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespSuccess()
+	}
+}
+
+func ApproveReview(ctx *gin.Context) {
+	in := types.ReviewRequest{}
+	if ctx.ShouldBindJSON(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if err := service.ApproveReview(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespSuccess()
+	}
+}
+
+func RejectReview(ctx *gin.Context) {
+	in := types.ReviewRequest{}
+	if ctx.ShouldBindJSON(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if err := service.RejectReview(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespSuccess()
+	}
+}
+
+func PublishReview(ctx *gin.Context) {
+	in := types.PublishReviewRequest{}
+	if ctx.ShouldBindJSON(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if err := service.PublishReview(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespSuccess()
+	}
+}
@@ -55,6 +55,50 @@ func AddRule(ctx *gin.Context) {
 	}
 }
 
+func DiffRule(ctx *gin.Context) {
+	in := types.DiffRuleRequest{}
+	if ctx.ShouldBind(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if resp, err := service.DiffRule(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespData(resp)
+	}
+}
+
+func ValidateRule(ctx *gin.Context) {
+	in := types.ValidateRuleRequest{}
+	if err := ctx.ShouldBindJSON(&in); err != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	list, valid := service.ValidateRule(ctx, &in)
+	ctx.RespData(gin.H{"valid": valid, "diagnostics": list})
+}
+
+func DryRunRule(ctx *gin.Context) {
+	in := types.DryRunRuleRequest{}
+	if err := ctx.ShouldBindJSON(&in); err != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if in.ID == 0 && in.Version == "" && in.Rule == "" {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if resp, log, err := service.DryRunRule(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespData(gin.H{"response": resp, "log": log})
+	}
+}
+
 func SwitchRuleVersion(ctx *gin.Context) {
 	in := types.SwitchVersionRuleRequest{}
 	if err := ctx.ShouldBindJSON(&in); err != nil {
@@ -80,3 +124,125 @@ func DeleteRule(ctx *gin.Context) {
 		ctx.RespSuccess()
 	}
 }
+
+func GetRuleLimit(ctx *gin.Context) {
+	in := types.GetRuleLimitRequest{}
+	if ctx.ShouldBind(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if limit, override, err := service.GetRuleLimit(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespData(gin.H{"limit": limit, "override": override})
+	}
+}
+
+func UpdateRuleLimit(ctx *gin.Context) {
+	in := types.UpdateRuleLimitRequest{}
+	if ctx.ShouldBindJSON(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if err := service.UpdateRuleLimit(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespSuccess()
+	}
+}
+
+func DeleteRuleLimit(ctx *gin.Context) {
+	in := types.DeleteRuleLimitRequest{}
+	if ctx.ShouldBindJSON(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if err := service.DeleteRuleLimit(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespSuccess()
+	}
+}
+
+func GetRuleCanary(ctx *gin.Context) {
+	in := types.GetRuleCanaryRequest{}
+	if ctx.ShouldBind(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if resp, err := service.GetRuleCanary(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespData(resp)
+	}
+}
+
+func UpdateRuleCanary(ctx *gin.Context) {
+	in := types.UpdateRuleCanaryRequest{}
+	if ctx.ShouldBindJSON(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if err := service.UpdateRuleCanary(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespSuccess()
+	}
+}
+
+func PromoteRuleCanary(ctx *gin.Context) {
+	in := types.PromoteRuleCanaryRequest{}
+	if ctx.ShouldBindJSON(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if err := service.PromoteRuleCanary(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespSuccess()
+	}
+}
+
+func DeleteRuleCanary(ctx *gin.Context) {
+	in := types.DeleteRuleCanaryRequest{}
+	if ctx.ShouldBindJSON(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if err := service.DeleteRuleCanary(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespSuccess()
+	}
+}
+
+func GetRuleRetention(ctx *gin.Context) {
+	in := types.GetRuleRetentionRequest{}
+	if ctx.ShouldBind(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	ctx.RespData(gin.H{"history_count": service.GetRuleRetention(ctx, &in)})
+}
+
+func UpdateRuleRetention(ctx *gin.Context) {
+	in := types.UpdateRuleRetentionRequest{}
+	if ctx.ShouldBindJSON(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if err := service.UpdateRuleRetention(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespSuccess()
+	}
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"github.com/limeschool/gin"
+	"ps-go/errors"
+	"ps-go/service"
+	"ps-go/types"
+)
+
+func PageRuleCase(ctx *gin.Context) {
+	in := types.PageRuleCaseRequest{}
+	if ctx.ShouldBind(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if resp, total, err := service.PageRuleCase(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespList(in.Page, in.Count, int(total), resp)
+	}
+}
+
+func AddRuleCase(ctx *gin.Context) {
+	in := types.AddRuleCaseRequest{}
+	if err := ctx.ShouldBindJSON(&in); err != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+	if err := service.AddRuleCase(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespSuccess()
+	}
+}
+
+func UpdateRuleCase(ctx *gin.Context) {
+	in := types.UpdateRuleCaseRequest{}
+	if err := ctx.ShouldBindJSON(&in); err != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+	if err := service.UpdateRuleCase(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespSuccess()
+	}
+}
+
+func DeleteRuleCase(ctx *gin.Context) {
+	in := types.DeleteRuleCaseRequest{}
+	if err := ctx.ShouldBindJSON(&in); err != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+	if err := service.DeleteRuleCase(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespSuccess()
+	}
+}
+
+func RunRuleCase(ctx *gin.Context) {
+	in := types.RunRuleCaseRequest{}
+	if err := ctx.ShouldBindJSON(&in); err != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+	if resp, err := service.RunRuleCase(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespData(resp)
+	}
+}
+
+func PageRuleCaseResult(ctx *gin.Context) {
+	in := types.PageRuleCaseResultRequest{}
+	if ctx.ShouldBind(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if resp, total, err := service.PageRuleCaseResult(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespList(in.Page, in.Count, int(total), resp)
+	}
+}
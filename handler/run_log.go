@@ -21,3 +21,18 @@ func GetRunLog(ctx *gin.Context) {
 		ctx.RespData(resp)
 	}
 }
+
+func GetRunStatus(ctx *gin.Context) {
+	in := types.GetRunStatusRequest{}
+
+	if ctx.ShouldBind(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if resp, err := service.GetRunStatus(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespData(resp)
+	}
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"github.com/limeschool/gin"
+	"ps-go/errors"
+	"ps-go/service"
+	"ps-go/types"
+)
+
+func PageSchedule(ctx *gin.Context) {
+	in := types.PageScheduleRequest{}
+
+	if ctx.ShouldBind(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if resp, total, err := service.PageSchedule(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespList(in.Page, in.Count, int(total), resp)
+	}
+}
+
+func AddSchedule(ctx *gin.Context) {
+	in := types.AddScheduleRequest{}
+	if err := ctx.ShouldBindJSON(&in); err != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+	if err := service.AddSchedule(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespSuccess()
+	}
+}
+
+func DeleteSchedule(ctx *gin.Context) {
+	in := types.DeleteScheduleRequest{}
+	if err := ctx.ShouldBindJSON(&in); err != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+	if err := service.DeleteSchedule(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespSuccess()
+	}
+}
+
+func PauseSchedule(ctx *gin.Context) {
+	in := types.ScheduleStatusRequest{}
+	if err := ctx.ShouldBindJSON(&in); err != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+	if err := service.PauseSchedule(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespSuccess()
+	}
+}
+
+func ResumeSchedule(ctx *gin.Context) {
+	in := types.ScheduleStatusRequest{}
+	if err := ctx.ShouldBindJSON(&in); err != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+	if err := service.ResumeSchedule(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespSuccess()
+	}
+}
+
+func TriggerSchedule(ctx *gin.Context) {
+	in := types.TriggerScheduleRequest{}
+	if err := ctx.ShouldBindJSON(&in); err != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+	if trx, err := service.TriggerSchedule(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespData(gin.H{"trx": trx})
+	}
+}
@@ -80,3 +80,17 @@ func DeleteScript(ctx *gin.Context) {
 		ctx.RespSuccess()
 	}
 }
+
+func DiffScript(ctx *gin.Context) {
+	in := types.DiffScriptRequest{}
+	if ctx.ShouldBind(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if resp, err := service.DiffScript(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespData(resp)
+	}
+}
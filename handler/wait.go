@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"github.com/limeschool/gin"
+	"ps-go/consts"
+	"ps-go/errors"
+	"ps-go/service"
+	"ps-go/types"
+)
+
+func PageWait(ctx *gin.Context) {
+	in := types.PageWaitRequest{}
+
+	if ctx.ShouldBind(&in) != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	if resp, total, err := service.PageWait(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespList(in.Page, in.Count, int(total), resp)
+	}
+}
+
+func Signal(ctx *gin.Context) {
+	in := types.SignalRequest{}
+	if err := ctx.ShouldBindJSON(&in); err != nil {
+		ctx.RespError(errors.ParamsError)
+		return
+	}
+
+	ctx.Writer.Header().Set(consts.ProcessScheduleTrx, in.Trx)
+	if data, err := service.Signal(ctx, &in); err != nil {
+		ctx.RespError(TransferError(err))
+	} else {
+		ctx.RespJson(data)
+	}
+}
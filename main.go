@@ -5,6 +5,7 @@ import (
 	"log"
 	"ps-go/engine"
 	"ps-go/rooter"
+	"ps-go/scheduler"
 	"ps-go/tools/hash"
 	"ps-go/tools/pool"
 	"runtime"
@@ -13,12 +14,14 @@ import (
 func main() {
 	// 协程池初始化
 	pool.Init()
+	// api 初始化，同时初始化全局配置
+	rg := rooter.Init()
 	// 调度引擎初始化
 	engine.Init()
 	// 初始化hash一致性算法
 	hash.Init()
-	// api 初始化
-	rg := rooter.Init()
+	// 定时任务调度初始化
+	scheduler.Init()
 
 	// 启动并监听端口
 	if err := rg.Run(":8080"); err != nil {
@@ -0,0 +1,56 @@
+package model
+
+import "github.com/limeschool/gin"
+
+// AuditLog 后台管理接口的操作记录
+type AuditLog struct {
+	gin.CreateModel
+	Entity     string `json:"entity"`      //操作的数据类型，如rule、script、secret
+	EntityKey  string `json:"entity_key"`  //操作的数据标识，如id、name:method、version
+	Action     string `json:"action"`      //操作类型 [create|update|delete]
+	Method     string `json:"method"`      //请求方法
+	Path       string `json:"path"`        //请求路由
+	Operator   string `json:"operator"`    //操作人员
+	OperatorID int64  `json:"operator_id"` //操作人员ID
+	IP         string `json:"ip"`          //请求ip
+	Request    string `json:"request"`     //请求数据，敏感字段已脱敏
+	BeforeData string `json:"before_data"` //变更前的数据，敏感字段已脱敏
+	AfterData  string `json:"after_data"`  //变更后的数据，敏感字段已脱敏
+	Code       int    `json:"code"`        //返回码
+	Msg        string `json:"msg"`         //返回信息
+}
+
+func (s AuditLog) Table() string {
+	return "audit_log"
+}
+
+// Create 记录操作
+func (s *AuditLog) Create(ctx *gin.Context) error {
+	return database(ctx).Table(s.Table()).Create(s).Error
+}
+
+// Page 查询分页数据
+func (s *AuditLog) Page(ctx *gin.Context, page, count int, m interface{}, fs ...callback) ([]AuditLog, int64, error) {
+	var list []AuditLog
+	var total int64
+
+	db := database(ctx).Table(s.Table()).
+		Select("id,entity,entity_key,action,method,path,operator,operator_id,ip,code,msg,created_at")
+	db = gin.GormWhere(db, s.Table(), m)
+	db = exec(db, fs...)
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, total, err
+	}
+
+	if err := db.Order("id desc").Offset((page - 1) * count).Limit(count).Find(&list).Error; err != nil {
+		return list, total, err
+	}
+
+	return list, total, nil
+}
+
+// OneByID 通过id查询操作详情
+func (s *AuditLog) OneByID(ctx *gin.Context, id int64) error {
+	return database(ctx).Table(s.Table()).Where("id = ?", id).First(s).Error
+}
@@ -23,6 +23,8 @@ func delayDelCache(ctx *gin.Context, key string) {
 	go func() {
 		time.Sleep(1 * time.Second)
 		ctx.Redis(consts.ProcessScheduleCache).Del(ctx, key)
+		// 数据已经变更完成，通知全部实例清理进程内缓存
+		ctx.Redis(consts.ProcessScheduleCache).Publish(ctx, consts.StoreChangeChannel, key)
 	}()
 }
 
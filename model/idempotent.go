@@ -0,0 +1,54 @@
+package model
+
+import (
+	"fmt"
+	"github.com/limeschool/gin"
+	"ps-go/errors"
+	"time"
+)
+
+const (
+	IdempotentRunning  = "running"  //执行中
+	IdempotentDone     = "done"     //执行成功
+	IdempotentAccepted = "accepted" //异步执行已受理，通过trx查询执行进度
+)
+
+// IdempotentRecord 幂等请求的执行记录，存储在redis中
+type IdempotentRecord struct {
+	Trx          string `json:"trx"`                //首次请求的唯一id
+	State        string `json:"state"`              //执行状态 running|done|accepted
+	Code         int    `json:"code,omitempty"`     //返回的http状态码
+	ResponseType string `json:"response_type"`      //返回数据类型
+	Response     any    `json:"response,omitempty"` //返回数据
+	UpdatedAt    int64  `json:"updated_at"`         //更新时间
+}
+
+func (s *IdempotentRecord) CacheKey(key string) string {
+	return fmt.Sprintf("idempotent_%v", key)
+}
+
+// Replayable 是否可以直接返回给相同幂等键的请求
+func (s *IdempotentRecord) Replayable() bool {
+	return s.State == IdempotentDone || s.State == IdempotentAccepted
+}
+
+// Save 存储执行记录
+func (s *IdempotentRecord) Save(ctx *gin.Context, key string, expire time.Duration) error {
+	s.UpdatedAt = time.Now().Unix()
+	str, _ := json.MarshalToString(s)
+	return cache(ctx).Set(ctx, s.CacheKey(key), str, expire).Err()
+}
+
+// OneByKey 通过幂等键查询执行记录
+func (s *IdempotentRecord) OneByKey(ctx *gin.Context, key string) error {
+	str, err := cache(ctx).Get(ctx, s.CacheKey(key)).Result()
+	if err != nil || str == "" {
+		return errors.DBNotFoundError
+	}
+	return json.UnmarshalFromString(str, s)
+}
+
+// DeleteByKey 删除执行记录
+func (s *IdempotentRecord) DeleteByKey(ctx *gin.Context, key string) error {
+	return cache(ctx).Del(ctx, s.CacheKey(key)).Err()
+}
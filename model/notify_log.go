@@ -0,0 +1,46 @@
+package model
+
+import (
+	"github.com/limeschool/gin"
+)
+
+type NotifyLog struct {
+	gin.CreateModel
+	Trx        string `json:"trx"`         //唯一请求id
+	Name       string `json:"name"`        //通知名称
+	Url        string `json:"url"`         //回调地址
+	Event      string `json:"event"`       //通知事件
+	Attempt    int    `json:"attempt"`     //第几次发送
+	Body       string `json:"body"`        //发送的数据
+	StatusCode int    `json:"status_code"` //返回状态码
+	Success    bool   `json:"success"`     //是否发送成功
+	Error      string `json:"error"`       //失败原因
+}
+
+func (s NotifyLog) Table() string {
+	return "notify_log"
+}
+
+func (s *NotifyLog) Create(ctx *gin.Context) error {
+	return database(ctx).Table(s.Table()).Create(s).Error
+}
+
+// Page 查询分页数据
+func (s *NotifyLog) Page(ctx *gin.Context, page, count int, m interface{}, fs ...callback) ([]NotifyLog, int64, error) {
+	var list []NotifyLog
+	var total int64
+
+	db := database(ctx).Table(s.Table())
+	db = gin.GormWhere(db, s.Table(), m)
+	db = exec(db, fs...)
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, total, err
+	}
+
+	if err := db.Order("id desc").Offset((page - 1) * count).Limit(count).Find(&list).Error; err != nil {
+		return list, total, err
+	}
+
+	return list, total, nil
+}
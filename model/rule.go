@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"github.com/limeschool/gin"
 	"gorm.io/gorm"
-	"ps-go/consts"
 	"ps-go/errors"
 	"ps-go/tools"
 	"ps-go/tools/lock"
@@ -161,6 +160,16 @@ func (u *Rule) OneByID(ctx *gin.Context, id int64) error {
 	return nil
 }
 
+// IsFirstVersion 是否为规则的第一个版本，已删除的历史版本同样计算在内
+func (u *Rule) IsFirstVersion(ctx *gin.Context) (bool, error) {
+	var total int64
+	db := database(ctx).Table(u.Table())
+	if err := db.Where("name = ? and method = ? and id < ?", u.Name, u.Method, u.ID).Count(&total).Error; err != nil {
+		return false, err
+	}
+	return total == 0, nil
+}
+
 // Create 创建规则
 func (u *Rule) Create(ctx *gin.Context) error {
 	u.Method = strings.ToUpper(u.Method)
@@ -175,24 +184,40 @@ func (u *Rule) Create(ctx *gin.Context) error {
 		return db.Where("name = ? and method = ?", u.Name, u.Method)
 	})
 
-	// 创建规则,第一个规则则直接使用
+	// 创建规则,第一个规则则直接使用，之后的新版本需要审核通过之后才能发布使用
 	u.Status = tools.Bool(count == 0)
 	u.Version = tools.UUID()
 	if err := db.Create(u).Error; err != nil {
 		return err
 	}
 
-	// 判断是否超过保存最大的副本数量
-	if count >= consts.RuleHistoryCount {
+	// 判断是否超过保存最大的副本数量，数量可以通过rule_retention按规则单独设置
+	history := (&RuleRetention{}).Count(ctx, u.Name, u.Method)
+	if int(count) >= history {
 		rule := Rule{}
 		if err := db.Where("name=? and method=?", u.Name, u.Method).
-			Order("id desc").Offset(consts.RuleHistoryCount - 1).Limit(1).First(&rule).Error; err == nil {
-			db.Where("id<=? and name=? and method=? and status=false", rule.ID, u.Name, u.Method).Delete(&Rule{})
+			Order("id desc").Offset(history - 1).Limit(1).First(&rule).Error; err == nil {
+			query := db.Where("id<=? and id<>? and name=? and method=? and status=false", rule.ID, u.ID, u.Name, u.Method)
+			if protected := u.protectedVersions(ctx); len(protected) != 0 {
+				query = query.Where("version not in ?", protected)
+			}
+			query.Delete(&Rule{})
 		}
 	}
 	return nil
 }
 
+// protectedVersions 清理历史版本时需要保留的版本，包括灰度中的版本以及尚未发布的版本
+func (u *Rule) protectedVersions(ctx *gin.Context) []string {
+	list := reviewingVersions(ctx, ReviewKindRule, u.Name, u.Method)
+
+	canary := RuleCanary{}
+	if canary.OneByNameMethod(ctx, u.Name, u.Method) == nil && canary.Version != "" {
+		list = append(list, canary.Version)
+	}
+	return list
+}
+
 // SwitchVersion 切换使用版本
 func (u *Rule) SwitchVersion(ctx *gin.Context) error {
 	if err := u.OneByID(ctx, u.ID); err != nil {
@@ -219,6 +244,16 @@ func (u *Rule) SwitchVersion(ctx *gin.Context) error {
 	})
 }
 
+// Revoke 撤销刚创建的版本，不校验启用状态，用于导入失败时回滚
+func (u *Rule) Revoke(ctx *gin.Context) error {
+	if err := u.OneByID(ctx, u.ID); err != nil {
+		return err
+	}
+
+	delayDelCache(ctx, u.CacheKey(fmt.Sprintf("%v:%v", u.Name, u.Method)))
+	return database(ctx).Table(u.Table()).Delete(u).Error
+}
+
 // DeleteByID 通过id删除规则
 func (u *Rule) DeleteByID(ctx *gin.Context) error {
 	if err := u.OneByID(ctx, u.ID); err != nil {
@@ -229,6 +264,8 @@ func (u *Rule) DeleteByID(ctx *gin.Context) error {
 		return errors.New("启用中的规则不允许删除")
 	}
 
+	delayDelCache(ctx, u.CacheKey(fmt.Sprintf("%v:%v", u.Name, u.Method)))
+
 	db := database(ctx).Table(u.Table())
 	if err := db.Updates(u).Delete(u).Error; err != nil {
 		return err
@@ -0,0 +1,94 @@
+package model
+
+import (
+	"fmt"
+	"github.com/limeschool/gin"
+	"gorm.io/gorm"
+	"ps-go/errors"
+	"strings"
+	"time"
+)
+
+// RuleCanary 规则的灰度发布配置，每个规则同时只能存在一个灰度版本
+type RuleCanary struct {
+	Name       string `json:"name"`       //规则名称
+	Method     string `json:"method"`     //规则请求方法
+	Version    string `json:"version"`    //灰度版本
+	Weight     int    `json:"weight"`     //灰度版本的流量百分比
+	Matches    string `json:"matches"`    //直接命中灰度版本的请求条件 list
+	StickyKey  string `json:"sticky_key"` //流量分配的粘性键
+	Operator   string `json:"operator,omitempty"`
+	OperatorID int64  `json:"operator_id,omitempty"`
+	gin.BaseModel
+}
+
+func (s RuleCanary) Table() string {
+	return "rule_canary"
+}
+
+func (s *RuleCanary) CacheKey(name, method string) string {
+	return fmt.Sprintf("rule_canary_%v:%v", name, strings.ToUpper(method))
+}
+
+// OneByNameMethod 通过name和method查询灰度配置，查询结果会进行缓存
+func (s *RuleCanary) OneByNameMethod(ctx *gin.Context, name, method string) error {
+	method = strings.ToUpper(method)
+	key := s.CacheKey(name, method)
+
+	if str, err := cache(ctx).Get(ctx, key).Result(); err == nil {
+		if err = json.UnmarshalFromString(str, s); err != nil {
+			return err
+		}
+		if s.ID == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	}
+
+	db := database(ctx).Table(s.Table())
+	if err := db.Where("name = ? and method = ?", name, method).First(s).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			cache(ctx).Set(ctx, key, "{}", time.Minute*5)
+		}
+		return err
+	}
+
+	str, _ := json.MarshalToString(s)
+	cache(ctx).Set(ctx, key, str, 24*time.Hour)
+	return nil
+}
+
+// Save 新增或者更新灰度配置
+func (s *RuleCanary) Save(ctx *gin.Context) error {
+	s.Method = strings.ToUpper(s.Method)
+	defer delayDelCache(ctx, s.CacheKey(s.Name, s.Method))
+
+	old := RuleCanary{}
+	db := database(ctx).Table(s.Table())
+	if err := db.Where("name = ? and method = ?", s.Name, s.Method).First(&old).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		return database(ctx).Table(s.Table()).Create(s).Error
+	}
+
+	// weight允许设置为0，使用map更新
+	s.ID = old.ID
+	return database(ctx).Table(s.Table()).Where("id = ?", s.ID).Updates(map[string]any{
+		"version":     s.Version,
+		"weight":      s.Weight,
+		"matches":     s.Matches,
+		"sticky_key":  s.StickyKey,
+		"operator":    s.Operator,
+		"operator_id": s.OperatorID,
+	}).Error
+}
+
+// DeleteByNameMethod 删除灰度配置，删除之后全部流量使用启用中的版本
+func (s *RuleCanary) DeleteByNameMethod(ctx *gin.Context, name, method string) error {
+	method = strings.ToUpper(method)
+	defer delayDelCache(ctx, s.CacheKey(name, method))
+
+	db := database(ctx).Table(s.Table())
+	return db.Where("name = ? and method = ?", name, method).Delete(s).Error
+}
@@ -0,0 +1,125 @@
+package model
+
+import (
+	"github.com/limeschool/gin"
+	"strings"
+)
+
+// RuleCase 规则的测试用例，切换规则版本之前需要全部通过
+type RuleCase struct {
+	Name       string `json:"name"`       //规则名称
+	Method     string `json:"method"`     //规则请求方法
+	Title      string `json:"title"`      //用例名称
+	Request    string `json:"request"`    //请求数据 {"query":{},"header":{},"body":{}}
+	Mocks      string `json:"mocks"`      //组件的模拟数据 map
+	Assertions string `json:"assertions"` //断言 list
+	Operator   string `json:"operator,omitempty"`
+	OperatorID int64  `json:"operator_id,omitempty"`
+	gin.DeleteModel
+}
+
+func (s RuleCase) Table() string {
+	return "rule_case"
+}
+
+// Page 查询分页数据
+func (s *RuleCase) Page(ctx *gin.Context, page, count int, m interface{}, fs ...callback) ([]RuleCase, int64, error) {
+	var list []RuleCase
+	var total int64
+
+	db := database(ctx).Table(s.Table())
+	db = gin.GormWhere(db, s.Table(), m)
+	db = exec(db, fs...)
+
+	if err := db.Where("deleted_at is null").Count(&total).Error; err != nil {
+		return nil, total, err
+	}
+
+	if err := db.Order("created_at desc").Offset((page - 1) * count).Limit(count).Find(&list).Error; err != nil {
+		return list, total, err
+	}
+
+	return list, total, nil
+}
+
+// AllByNameMethod 查询规则的全部测试用例
+func (s *RuleCase) AllByNameMethod(ctx *gin.Context, name, method string) ([]RuleCase, error) {
+	var list []RuleCase
+	db := database(ctx).Table(s.Table())
+	return list, db.Where("name = ? and method = ? and deleted_at is null", name, strings.ToUpper(method)).
+		Order("id asc").Find(&list).Error
+}
+
+// OneByID 通过id查询测试用例
+func (s *RuleCase) OneByID(ctx *gin.Context, id int64) error {
+	return database(ctx).Table(s.Table()).Where("id = ? and deleted_at is null", id).First(s).Error
+}
+
+// Create 创建测试用例
+func (s *RuleCase) Create(ctx *gin.Context) error {
+	s.Method = strings.ToUpper(s.Method)
+	return database(ctx).Table(s.Table()).Create(s).Error
+}
+
+// Update 更新测试用例
+func (s *RuleCase) Update(ctx *gin.Context) error {
+	if err := (&RuleCase{}).OneByID(ctx, s.ID); err != nil {
+		return err
+	}
+	return database(ctx).Table(s.Table()).Where("id = ?", s.ID).Updates(s).Error
+}
+
+// DeleteByID 通过id删除测试用例
+func (s *RuleCase) DeleteByID(ctx *gin.Context) error {
+	if err := (&RuleCase{}).OneByID(ctx, s.ID); err != nil {
+		return err
+	}
+
+	db := database(ctx).Table(s.Table())
+	return db.Updates(s).Delete(s).Error
+}
+
+// RuleCaseResult 测试用例在指定规则版本上的执行结果
+type RuleCaseResult struct {
+	gin.CreateModel
+	CaseID   int64  `json:"case_id"`  //测试用例id
+	Name     string `json:"name"`     //规则名称
+	Method   string `json:"method"`   //规则请求方法
+	Version  string `json:"version"`  //规则版本
+	Title    string `json:"title"`    //用例名称
+	Passed   bool   `json:"passed"`   //是否通过
+	Failures string `json:"failures"` //未通过的原因 list
+	Response string `json:"response"` //流程的返回数据
+	Status   string `json:"status"`   //流程的执行状态
+	Trx      string `json:"trx"`      //本次执行的请求唯一标识
+}
+
+func (s RuleCaseResult) Table() string {
+	return "rule_case_result"
+}
+
+// Create 存储执行结果
+func (s *RuleCaseResult) Create(ctx *gin.Context) error {
+	s.Method = strings.ToUpper(s.Method)
+	return database(ctx).Table(s.Table()).Create(s).Error
+}
+
+// Page 查询分页数据
+func (s *RuleCaseResult) Page(ctx *gin.Context, page, count int, m interface{}, fs ...callback) ([]RuleCaseResult, int64, error) {
+	var list []RuleCaseResult
+	var total int64
+
+	db := database(ctx).Table(s.Table())
+	db = gin.GormWhere(db, s.Table(), m)
+	db = exec(db, fs...)
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, total, err
+	}
+
+	if err := db.Order("id desc").Offset((page - 1) * count).Limit(count).Find(&list).Error; err != nil {
+		return list, total, err
+	}
+
+	return list, total, nil
+}
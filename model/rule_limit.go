@@ -0,0 +1,83 @@
+package model
+
+import (
+	"fmt"
+	"github.com/limeschool/gin"
+	"gorm.io/gorm"
+	"ps-go/errors"
+	"strings"
+	"time"
+)
+
+// RuleLimit 规则的限流配置，优先于规则中的limit配置，修改之后不需要发布新的规则版本
+type RuleLimit struct {
+	Name       string `json:"name"`   //规则名称
+	Method     string `json:"method"` //规则请求方法
+	Config     string `json:"config"` //限流配置 map
+	Operator   string `json:"operator,omitempty"`
+	OperatorID int64  `json:"operator_id,omitempty"`
+	gin.BaseModel
+}
+
+func (s RuleLimit) Table() string {
+	return "rule_limit"
+}
+
+func (s *RuleLimit) CacheKey(name, method string) string {
+	return fmt.Sprintf("rule_limit_%v:%v", name, strings.ToUpper(method))
+}
+
+// OneByNameMethod 通过name和method查询限流配置，查询结果会进行缓存
+func (s *RuleLimit) OneByNameMethod(ctx *gin.Context, name, method string) error {
+	method = strings.ToUpper(method)
+	key := s.CacheKey(name, method)
+
+	if str, err := cache(ctx).Get(ctx, key).Result(); err == nil {
+		if err = json.UnmarshalFromString(str, s); err != nil {
+			return err
+		}
+		if s.ID == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	}
+
+	db := database(ctx).Table(s.Table())
+	if err := db.Where("name = ? and method = ?", name, method).First(s).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			cache(ctx).Set(ctx, key, "{}", time.Minute*5)
+		}
+		return err
+	}
+
+	str, _ := json.MarshalToString(s)
+	cache(ctx).Set(ctx, key, str, 24*time.Hour)
+	return nil
+}
+
+// Save 新增或者更新限流配置
+func (s *RuleLimit) Save(ctx *gin.Context) error {
+	s.Method = strings.ToUpper(s.Method)
+	defer delayDelCache(ctx, s.CacheKey(s.Name, s.Method))
+
+	old := RuleLimit{}
+	db := database(ctx).Table(s.Table())
+	if err := db.Where("name = ? and method = ?", s.Name, s.Method).First(&old).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		return database(ctx).Table(s.Table()).Create(s).Error
+	}
+
+	s.ID = old.ID
+	return database(ctx).Table(s.Table()).Where("id = ?", s.ID).Updates(s).Error
+}
+
+// DeleteByNameMethod 删除限流配置，删除之后使用规则中的limit配置
+func (s *RuleLimit) DeleteByNameMethod(ctx *gin.Context, name, method string) error {
+	method = strings.ToUpper(method)
+	defer delayDelCache(ctx, s.CacheKey(name, method))
+
+	db := database(ctx).Table(s.Table())
+	return db.Where("name = ? and method = ?", name, method).Delete(s).Error
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"github.com/limeschool/gin"
+	"gorm.io/gorm"
+	"ps-go/consts"
+	"ps-go/errors"
+	"strings"
+)
+
+// RuleRetention 规则的历史版本保留配置，未设置时保留consts.RuleHistoryCount个版本
+type RuleRetention struct {
+	Name         string `json:"name"`          //规则名称
+	Method       string `json:"method"`        //规则请求方法
+	HistoryCount int    `json:"history_count"` //保留的版本数量，包含启用中的版本
+	Operator     string `json:"operator,omitempty"`
+	OperatorID   int64  `json:"operator_id,omitempty"`
+	gin.BaseModel
+}
+
+func (s RuleRetention) Table() string {
+	return "rule_retention"
+}
+
+// OneByNameMethod 通过name和method查询保留配置
+func (s *RuleRetention) OneByNameMethod(ctx *gin.Context, name, method string) error {
+	db := database(ctx).Table(s.Table())
+	return db.Where("name = ? and method = ?", name, strings.ToUpper(method)).First(s).Error
+}
+
+// Count 获取规则保留的版本数量，不小于consts.RuleHistoryMinCount
+func (s *RuleRetention) Count(ctx *gin.Context, name, method string) int {
+	if err := s.OneByNameMethod(ctx, name, method); err != nil || s.HistoryCount <= 0 {
+		return consts.RuleHistoryCount
+	}
+	if s.HistoryCount < consts.RuleHistoryMinCount {
+		return consts.RuleHistoryMinCount
+	}
+	return s.HistoryCount
+}
+
+// Save 新增或者更新保留配置，只在创建新版本时清理超出数量的历史版本
+func (s *RuleRetention) Save(ctx *gin.Context) error {
+	s.Method = strings.ToUpper(s.Method)
+
+	old := RuleRetention{}
+	db := database(ctx).Table(s.Table())
+	if err := db.Where("name = ? and method = ?", s.Name, s.Method).First(&old).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		return database(ctx).Table(s.Table()).Create(s).Error
+	}
+
+	s.ID = old.ID
+	return database(ctx).Table(s.Table()).Where("id = ?", s.ID).Updates(s).Error
+}
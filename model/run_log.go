@@ -3,6 +3,8 @@ package model
 import (
 	"fmt"
 	"github.com/limeschool/gin"
+	"gorm.io/gorm"
+	"ps-go/errors"
 	"ps-go/tools/hash"
 )
 
@@ -31,6 +33,22 @@ func (s *RunLog) Create(ctx *gin.Context) error {
 	return database(ctx).Table(s.Table(s.Trx)).Create(s).Error
 }
 
+// Save 存储执行日志，同一个trx已经存在日志时进行更新，恢复执行的流程不会产生重复的日志
+func (s *RunLog) Save(ctx *gin.Context) error {
+	old := RunLog{}
+	db := database(ctx).Table(s.Table(s.Trx))
+	if err := db.Select("id").Where("trx = ?", s.Trx).First(&old).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		return database(ctx).Table(s.Table(s.Trx)).Create(s).Error
+	}
+
+	s.ID = old.ID
+	return database(ctx).Table(s.Table(s.Trx)).Select("*").Omit("id", "created_at").
+		Where("id = ?", s.ID).Updates(s).Error
+}
+
 func (s *RunLog) DeleteByTrx(ctx *gin.Context, trx string) error {
 	return database(ctx).Table(s.Table(trx)).Delete(s, "trx = ?", trx).Error
 }
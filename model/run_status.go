@@ -0,0 +1,50 @@
+package model
+
+import (
+	"fmt"
+	"github.com/limeschool/gin"
+	"ps-go/consts"
+	"ps-go/errors"
+	"time"
+)
+
+const (
+	RunStateRunning = "running" //执行中
+	RunStateDone    = "done"    //执行完成
+	RunStateWaiting = "waiting" //等待信号
+)
+
+// RunStatus 异步流程的执行进度，存储在redis中
+type RunStatus struct {
+	Trx       string `json:"trx"`                //唯一请求id
+	Method    string `json:"method"`             //请求的方法
+	Path      string `json:"path"`               //请求的路径
+	Version   string `json:"version"`            //规则版本
+	State     string `json:"state"`              //执行状态 running|done
+	Status    string `json:"status,omitempty"`   //执行结果 中断/错误挂起/主动挂起/成功
+	Step      int    `json:"step"`               //总执行层数
+	CurStep   int    `json:"cur_step"`           //当前执行层数
+	Error     string `json:"error,omitempty"`    //错误原因
+	Response  any    `json:"response,omitempty"` //执行完成之后的返回数据
+	UpdatedAt int64  `json:"updated_at"`         //更新时间
+}
+
+func (s *RunStatus) CacheKey(trx string) string {
+	return fmt.Sprintf("run_status_%v", trx)
+}
+
+// Save 存储执行进度
+func (s *RunStatus) Save(ctx *gin.Context) error {
+	s.UpdatedAt = time.Now().Unix()
+	str, _ := json.MarshalToString(s)
+	return cache(ctx).Set(ctx, s.CacheKey(s.Trx), str, consts.RunStatusExpire).Err()
+}
+
+// OneByTrx 通过trx查询执行进度
+func (s *RunStatus) OneByTrx(ctx *gin.Context, trx string) error {
+	str, err := cache(ctx).Get(ctx, s.CacheKey(trx)).Result()
+	if err != nil || str == "" {
+		return errors.DBNotFoundError
+	}
+	return json.UnmarshalFromString(str, s)
+}
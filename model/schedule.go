@@ -0,0 +1,115 @@
+package model
+
+import (
+	"github.com/limeschool/gin"
+	"ps-go/errors"
+	"ps-go/tools/cron"
+	"strings"
+	"time"
+)
+
+type Schedule struct {
+	Name        string `json:"name"`        //规则名称
+	Method      string `json:"method"`      //规则请求方法
+	Cron        string `json:"cron"`        //cron表达式
+	Request     string `json:"request"`     //触发时的请求body
+	Description string `json:"description"` //定时任务描述
+	Status      *bool  `json:"status"`      //是否启用
+	LastTrx     string `json:"last_trx"`    //上次触发的请求唯一标识
+	LastTime    int64  `json:"last_time"`   //上次触发时间
+	NextTime    int64  `json:"next_time"`   //下次触发时间
+	Operator    string `json:"operator,omitempty"`
+	OperatorID  int64  `json:"operator_id,omitempty"`
+	gin.DeleteModel
+}
+
+func (s Schedule) Table() string {
+	return "schedule"
+}
+
+// Page 查询分页数据
+func (s *Schedule) Page(ctx *gin.Context, page, count int, m interface{}, fs ...callback) ([]Schedule, int64, error) {
+	var list []Schedule
+	var total int64
+
+	db := database(ctx).Table(s.Table())
+	db = gin.GormWhere(db, s.Table(), m)
+	db = exec(db, fs...)
+
+	if err := db.Where("deleted_at is null").Count(&total).Error; err != nil {
+		return nil, total, err
+	}
+
+	if err := db.Order("created_at desc").Offset((page - 1) * count).Limit(count).Find(&list).Error; err != nil {
+		return list, total, err
+	}
+
+	return list, total, nil
+}
+
+// All 查询所有启用中的定时任务
+func (s *Schedule) All(ctx *gin.Context) ([]Schedule, error) {
+	var list []Schedule
+	db := database(ctx).Table(s.Table())
+	return list, db.Where("status = true and deleted_at is null").Find(&list).Error
+}
+
+// OneByID 通过id查询定时任务
+func (s *Schedule) OneByID(ctx *gin.Context, id int64) error {
+	return database(ctx).Table(s.Table()).Where("id = ? and deleted_at is null", id).First(s).Error
+}
+
+// Create 创建定时任务
+func (s *Schedule) Create(ctx *gin.Context) error {
+	s.Method = strings.ToUpper(s.Method)
+
+	spec, err := cron.Parse(s.Cron)
+	if err != nil {
+		return errors.New(err.Error())
+	}
+
+	s.NextTime = spec.Next(time.Now()).Unix()
+	return database(ctx).Table(s.Table()).Create(s).Error
+}
+
+// UpdateStatus 暂停或者恢复定时任务
+func (s *Schedule) UpdateStatus(ctx *gin.Context, status bool) error {
+	if err := s.OneByID(ctx, s.ID); err != nil {
+		return err
+	}
+
+	update := map[string]any{"status": status}
+	if status {
+		if spec, err := cron.Parse(s.Cron); err == nil {
+			update["next_time"] = spec.Next(time.Now()).Unix()
+		}
+	}
+
+	db := database(ctx).Table(s.Table())
+	return db.Where("id = ?", s.ID).Updates(update).Error
+}
+
+// UpdateFire 更新定时任务的触发信息
+func (s *Schedule) UpdateFire(ctx *gin.Context, trx string, t time.Time) error {
+	update := map[string]any{
+		"last_trx":  trx,
+		"last_time": t.Unix(),
+	}
+	if spec, err := cron.Parse(s.Cron); err == nil {
+		update["next_time"] = spec.Next(t).Unix()
+	}
+
+	db := database(ctx).Table(s.Table())
+	return db.Where("id = ?", s.ID).Updates(update).Error
+}
+
+// DeleteByID 通过id删除定时任务
+func (s *Schedule) DeleteByID(ctx *gin.Context) error {
+	schedule := Schedule{}
+	if err := schedule.OneByID(ctx, s.ID); err != nil {
+		return err
+	}
+
+	db := database(ctx).Table(s.Table())
+	return db.Updates(s).Delete(s).Error
+}
@@ -154,15 +154,28 @@ func (u *Script) OneByID(ctx *gin.Context, id int64) error {
 	return nil
 }
 
+// IsFirstVersion 是否为脚本的第一个版本，已删除的历史版本同样计算在内
+func (u *Script) IsFirstVersion(ctx *gin.Context) (bool, error) {
+	var total int64
+	db := database(ctx).Table(u.Table())
+	if err := db.Where("name = ? and id < ?", u.Name, u.ID).Count(&total).Error; err != nil {
+		return false, err
+	}
+	return total == 0, nil
+}
+
 // Create 创建脚本
 func (u *Script) Create(ctx *gin.Context) error {
+	// 延迟双删
+	delayDelCache(ctx, u.CacheKey(u.Name))
+
 	db := database(ctx).Table(u.Table()).Session(&gorm.Session{NewDB: true})
 	// 查看当前是否存在脚本
 	count, _ := u.Count(ctx, func(db *gorm.DB) *gorm.DB {
 		return db.Where("name = ?", u.Name)
 	})
 
-	// 创建脚本,第一个脚本则直接使用
+	// 创建脚本,第一个脚本则直接使用，之后的新版本需要审核通过之后才能发布使用
 	u.Status = tools.Bool(count == 0)
 	u.Version = tools.UUID()
 	if err := db.Create(u).Error; err != nil {
@@ -179,8 +192,12 @@ func (u *Script) Create(ctx *gin.Context) error {
 			Limit(1).
 			First(&script).Error; err == nil {
 
-			db.Where("id<=? and name=? and status=false", script.ID, script.Name).
-				Delete(&Script{})
+			// 保留本次创建的版本以及尚未发布的版本
+			query := db.Where("id<=? and id<>? and name=? and status=false", script.ID, u.ID, script.Name)
+			if protected := reviewingVersions(ctx, ReviewKindScript, u.Name, ""); len(protected) != 0 {
+				query = query.Where("version not in ?", protected)
+			}
+			query.Delete(&Script{})
 
 		}
 	}
@@ -205,7 +222,7 @@ func (u *Script) SwitchVersion(ctx *gin.Context) error {
 
 	// 进行版本切换，使用指定id版本
 	return db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Where("name=", u.Name).Update("status", false).Error; err != nil {
+		if err := tx.Where("name=?", u.Name).Update("status", false).Error; err != nil {
 			return err
 		}
 
@@ -214,6 +231,16 @@ func (u *Script) SwitchVersion(ctx *gin.Context) error {
 	})
 }
 
+// Revoke 撤销刚创建的版本，不校验启用状态，用于导入失败时回滚
+func (u *Script) Revoke(ctx *gin.Context) error {
+	if err := u.OneByID(ctx, u.ID); err != nil {
+		return err
+	}
+
+	delayDelCache(ctx, u.CacheKey(u.Name))
+	return database(ctx).Table(u.Table()).Delete(u).Error
+}
+
 // DeleteByID 通过id删除规则
 func (u *Script) DeleteByID(ctx *gin.Context) error {
 	if err := u.OneByID(ctx, u.ID); err != nil {
@@ -224,6 +251,8 @@ func (u *Script) DeleteByID(ctx *gin.Context) error {
 		return errors.New("启用中的脚本不允许删除")
 	}
 
+	delayDelCache(ctx, u.CacheKey(u.Name))
+
 	db := database(ctx).Table(u.Table())
 	if err := db.Updates(u).Delete(u).Error; err != nil {
 		return err
@@ -3,10 +3,17 @@ package model
 import (
 	"github.com/limeschool/gin"
 	"gorm.io/gorm"
+	"ps-go/errors"
+)
+
+const (
+	SuspendTypeFlow       = ""           //流程挂起
+	SuspendTypeCompensate = "compensate" //补偿失败挂起
 )
 
 type SuspendLog struct {
 	gin.CreateModel
+	Type         string `json:"type"`          //挂起类型 [|compensate]
 	Trx          string `json:"trx"`           //唯一请求id
 	Method       string `json:"method"`        //请求的方法
 	Path         string `json:"path"`          //请求的路径
@@ -19,6 +26,8 @@ type SuspendLog struct {
 	Rule         string `json:"rule"`          //流程规则 map
 	Data         string `json:"data"`          //流程上下文数据 map
 	ErrComponent string `json:"err_component"` //错误组件名 slice
+
+	FinishComponent string `json:"finish_component"` //已完成的组件标志 slice
 }
 
 func (s SuspendLog) Table() string {
@@ -29,16 +38,26 @@ func (s *SuspendLog) Create(ctx *gin.Context) error {
 	return database(ctx).Table(s.Table()).Create(s).Error
 }
 
-func (s *SuspendLog) DeleteByTrx(ctx *gin.Context, trx string) error {
-	db := database(ctx)
-	return db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Table(s.Table()).Delete(s, "trx = ?", trx).Error; err != nil {
+// Save 存储挂起任务，同一个trx只保留一条挂起记录。
+// 流程挂起之后补偿失败时，补偿任务覆盖流程挂起任务，已经补偿的流程不能再恢复执行
+func (s *SuspendLog) Save(ctx *gin.Context) error {
+	old := SuspendLog{}
+	db := database(ctx).Table(s.Table())
+	if err := db.Select("id").Where("trx = ?", s.Trx).First(&old).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
 			return err
 		}
-		log := RunLog{}
-		return tx.Table(log.Table(trx)).Delete(&log, "trx = ?", trx).Error
-	})
+		return database(ctx).Table(s.Table()).Create(s).Error
+	}
+
+	s.ID = old.ID
+	return database(ctx).Table(s.Table()).Select("*").Omit("id", "created_at").
+		Where("id = ?", s.ID).Updates(s).Error
+}
 
+// DeleteByTrx 删除挂起任务，执行日志保留，恢复执行之后更新原有的日志
+func (s *SuspendLog) DeleteByTrx(ctx *gin.Context, trx string) error {
+	return database(ctx).Table(s.Table()).Delete(s, "trx = ?", trx).Error
 }
 
 func (s *SuspendLog) DeleteByID(ctx *gin.Context, id int64) error {
@@ -63,7 +82,7 @@ func (s *SuspendLog) Page(ctx *gin.Context, page, count int, m interface{}, fs .
 	var total int64
 
 	db := database(ctx).Table(s.Table()).
-		Select("id,trx,method,path,version,log_id,step,cur_step,err_msg,err_component,created_at")
+		Select("id,type,trx,method,path,version,log_id,step,cur_step,err_msg,err_component,finish_component,created_at")
 
 	db = gin.GormWhere(db, s.Table(), m)
 	db = exec(db, fs...)
@@ -0,0 +1,162 @@
+package model
+
+import (
+	"github.com/limeschool/gin"
+	"gorm.io/gorm"
+	"ps-go/errors"
+	"strings"
+	"time"
+)
+
+const (
+	ReviewKindRule   = "rule"
+	ReviewKindScript = "script"
+
+	ReviewDraft     = "draft"     //草稿，版本创建之后的初始状态
+	ReviewSubmitted = "submitted" //已提交，等待审核
+	ReviewApproved  = "approved"  //审核通过，可以发布
+	ReviewPublished = "published" //已发布
+)
+
+// VersionReview 规则、脚本版本的审核状态，审核通过之后才能发布
+type VersionReview struct {
+	Kind       string `json:"kind"`        //版本类型 [rule|script]
+	Name       string `json:"name"`        //规则或者脚本名称
+	Method     string `json:"method"`      //规则请求方法，脚本为空
+	Version    string `json:"version"`     //版本
+	State      string `json:"state"`       //审核状态
+	Author     string `json:"author"`      //版本作者
+	AuthorID   int64  `json:"author_id"`   //版本作者ID
+	Reviewer   string `json:"reviewer"`    //审核人员
+	ReviewerID int64  `json:"reviewer_id"` //审核人员ID
+	PublishAt  int64  `json:"publish_at"`  //定时发布的时间，0为未设置
+	gin.BaseModel
+}
+
+func (s VersionReview) Table() string {
+	return "version_review"
+}
+
+// OneByVersion 通过版本查询审核状态
+func (s *VersionReview) OneByVersion(ctx *gin.Context, kind, version string) error {
+	db := database(ctx).Table(s.Table())
+	return db.Where("kind = ? and version = ?", kind, version).First(s).Error
+}
+
+// Page 查询分页数据
+func (s *VersionReview) Page(ctx *gin.Context, page, count int, m interface{}, fs ...callback) ([]VersionReview, int64, error) {
+	var list []VersionReview
+	var total int64
+
+	db := database(ctx).Table(s.Table())
+	db = gin.GormWhere(db, s.Table(), m)
+	db = exec(db, fs...)
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, total, err
+	}
+
+	if err := db.Order("id desc").Offset((page - 1) * count).Limit(count).Find(&list).Error; err != nil {
+		return list, total, err
+	}
+
+	return list, total, nil
+}
+
+// DueList 查询已经到达定时发布时间的版本
+func (s *VersionReview) DueList(ctx *gin.Context, now int64, limit int) ([]VersionReview, error) {
+	var list []VersionReview
+	db := database(ctx).Table(s.Table())
+	db = db.Where("state = ? and publish_at > 0 and publish_at <= ?", ReviewApproved, now)
+	return list, db.Order("publish_at").Limit(limit).Find(&list).Error
+}
+
+// reviewingVersions 查询尚未发布的版本，包括草稿、待审核、审核通过以及等待定时发布的版本，清理历史版本时需要保留
+func reviewingVersions(ctx *gin.Context, kind, name, method string) []string {
+	var list []string
+	db := database(ctx).Table(VersionReview{}.Table())
+	db.Where("kind = ? and name = ? and method = ? and state in ?",
+		kind, name, strings.ToUpper(method), []string{ReviewDraft, ReviewSubmitted, ReviewApproved}).
+		Pluck("version", &list)
+	return list
+}
+
+// Create 创建审核状态，并记录创建日志，未指定状态时为草稿状态
+func (s *VersionReview) Create(ctx *gin.Context, comment string) error {
+	s.Method = strings.ToUpper(s.Method)
+	if s.State == "" {
+		s.State = ReviewDraft
+	}
+
+	return database(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table(s.Table()).Create(s).Error; err != nil {
+			return err
+		}
+		log := VersionReviewLog{
+			Kind:       s.Kind,
+			Version:    s.Version,
+			ToState:    s.State,
+			Comment:    comment,
+			Operator:   s.Author,
+			OperatorID: s.AuthorID,
+		}
+		return tx.Table(log.Table()).Create(&log).Error
+	})
+}
+
+// Transit 从from状态变更为log.ToState状态，同时更新其他字段并记录变更日志。
+// 状态已经被其他请求修改时返回错误，防止并发操作重复变更
+func (s *VersionReview) Transit(ctx *gin.Context, from string, updates map[string]any, log *VersionReviewLog) error {
+	updates["state"] = log.ToState
+	updates["updated_at"] = time.Now().Unix()
+	log.Kind, log.Version, log.FromState = s.Kind, s.Version, from
+
+	return database(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Table(s.Table()).Where("id = ? and state = ?", s.ID, from).Updates(updates)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errors.NewF("版本%v的审核状态已变更，请刷新后重试", s.Version)
+		}
+		return tx.Table(log.Table()).Create(log).Error
+	})
+}
+
+// DeleteByVersion 删除版本的审核状态以及变更记录，用于撤销导入失败的版本
+func (s *VersionReview) DeleteByVersion(ctx *gin.Context) error {
+	return database(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table(s.Table()).Where("kind = ? and version = ?", s.Kind, s.Version).Delete(&VersionReview{}).Error; err != nil {
+			return err
+		}
+		return tx.Table(VersionReviewLog{}.Table()).Where("kind = ? and version = ?", s.Kind, s.Version).Delete(&VersionReviewLog{}).Error
+	})
+}
+
+// VersionReviewLog 审核状态的变更记录
+type VersionReviewLog struct {
+	gin.CreateModel
+	Kind       string `json:"kind"`       //版本类型 [rule|script]
+	Version    string `json:"version"`    //版本
+	FromState  string `json:"from_state"` //变更前的状态，创建时为空
+	ToState    string `json:"to_state"`   //变更后的状态
+	Comment    string `json:"comment"`    //变更说明
+	Operator   string `json:"operator"`
+	OperatorID int64  `json:"operator_id"`
+}
+
+func (s VersionReviewLog) Table() string {
+	return "version_review_log"
+}
+
+// Create 记录变更日志
+func (s *VersionReviewLog) Create(ctx *gin.Context) error {
+	return database(ctx).Table(s.Table()).Create(s).Error
+}
+
+// AllByVersion 查询版本的全部变更记录
+func (s *VersionReviewLog) AllByVersion(ctx *gin.Context, kind, version string) ([]VersionReviewLog, error) {
+	var list []VersionReviewLog
+	db := database(ctx).Table(s.Table())
+	return list, db.Where("kind = ? and version = ?", kind, version).Order("id asc").Find(&list).Error
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"github.com/limeschool/gin"
+)
+
+type WaitLog struct {
+	gin.CreateModel
+	Trx             string `json:"trx"`              //唯一请求id
+	Method          string `json:"method"`           //请求的方法
+	Path            string `json:"path"`             //请求的路径
+	Version         string `json:"version"`          //规则版本
+	LogID           string `json:"log_id"`           //日志id
+	Step            int    `json:"step"`             //总步数
+	CurStep         int    `json:"cur_step"`         //当前执行步
+	Rule            string `json:"rule"`             //流程规则 map
+	Data            string `json:"data"`             //流程上下文数据 map
+	Waits           string `json:"waits"`            //等待信号的组件 slice
+	FinishComponent string `json:"finish_component"` //已完成的组件标志 slice
+	TimeoutAt       int64  `json:"timeout_at"`       //最近的等待超时时间，0为不超时
+}
+
+func (s WaitLog) Table() string {
+	return "wait_log"
+}
+
+func (s *WaitLog) Create(ctx *gin.Context) error {
+	return database(ctx).Table(s.Table()).Create(s).Error
+}
+
+func (s *WaitLog) OneByTrx(ctx *gin.Context, trx string) error {
+	return database(ctx).Table(s.Table()).Where("trx = ?", trx).First(s).Error
+}
+
+// DeleteByTrx 删除等待信息，执行日志保留，恢复执行之后更新原有的日志
+func (s *WaitLog) DeleteByTrx(ctx *gin.Context, trx string) error {
+	return database(ctx).Table(s.Table()).Delete(s, "trx = ?", trx).Error
+}
+
+// TimeoutList 查询已经等待超时的流程
+func (s *WaitLog) TimeoutList(ctx *gin.Context, now int64, limit int) ([]WaitLog, error) {
+	var list []WaitLog
+	db := database(ctx).Table(s.Table())
+	db = db.Where("timeout_at > 0 and timeout_at <= ?", now)
+	return list, db.Order("timeout_at").Limit(limit).Find(&list).Error
+}
+
+// Page 查询分页数据
+func (s *WaitLog) Page(ctx *gin.Context, page, count int, m interface{}, fs ...callback) ([]WaitLog, int64, error) {
+	var list []WaitLog
+	var total int64
+
+	db := database(ctx).Table(s.Table()).
+		Select("id,trx,method,path,version,log_id,step,cur_step,waits,finish_component,timeout_at,created_at")
+
+	db = gin.GormWhere(db, s.Table(), m)
+	db = exec(db, fs...)
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, total, err
+	}
+
+	if err := db.Order("created_at desc").Offset((page - 1) * count).Limit(count).Find(&list).Error; err != nil {
+		return list, total, err
+	}
+
+	return list, total, nil
+}
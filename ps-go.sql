@@ -15,6 +15,79 @@
 /*!40101 SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='NO_AUTO_VALUE_ON_ZERO' */;
 /*!40111 SET @OLD_SQL_NOTES=@@SQL_NOTES, SQL_NOTES=0 */;
 
+--
+-- Table structure for table `audit_log`
+--
+
+DROP TABLE IF EXISTS `audit_log`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `audit_log` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `entity` varchar(64) NOT NULL COMMENT '操作的数据类型',
+  `entity_key` varchar(256) NOT NULL DEFAULT '' COMMENT '操作的数据标识',
+  `action` varchar(32) NOT NULL COMMENT '操作类型',
+  `method` varchar(16) NOT NULL COMMENT '请求方法',
+  `path` varchar(256) NOT NULL COMMENT '请求路由',
+  `operator` varchar(128) NOT NULL DEFAULT '' COMMENT '操作人员',
+  `operator_id` int(11) NOT NULL DEFAULT 0 COMMENT '操作人员ID',
+  `ip` varchar(64) NOT NULL DEFAULT '' COMMENT '请求ip',
+  `request` mediumtext NOT NULL COMMENT '请求数据',
+  `before_data` mediumtext NOT NULL COMMENT '变更前的数据',
+  `after_data` mediumtext NOT NULL COMMENT '变更后的数据',
+  `code` int(11) NOT NULL DEFAULT 0 COMMENT '返回码',
+  `msg` varchar(1024) NOT NULL DEFAULT '' COMMENT '返回信息',
+  `created_at` int(11) DEFAULT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `entity` (`entity`,`entity_key`),
+  KEY `operator` (`operator_id`),
+  KEY `created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `audit_log`
+--
+
+LOCK TABLES `audit_log` WRITE;
+/*!40000 ALTER TABLE `audit_log` DISABLE KEYS */;
+/*!40000 ALTER TABLE `audit_log` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `notify_log`
+--
+
+DROP TABLE IF EXISTS `notify_log`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `notify_log` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `trx` varchar(128) NOT NULL COMMENT '唯一请求id',
+  `name` varchar(128) NOT NULL COMMENT '通知名称',
+  `url` varchar(512) NOT NULL COMMENT '回调地址',
+  `event` varchar(32) NOT NULL COMMENT '通知事件',
+  `attempt` int(11) NOT NULL COMMENT '第几次发送',
+  `body` text NOT NULL COMMENT '发送的数据',
+  `status_code` int(11) NOT NULL DEFAULT 0 COMMENT '返回状态码',
+  `success` tinyint(1) NOT NULL COMMENT '是否发送成功',
+  `error` varchar(1024) NOT NULL DEFAULT '' COMMENT '失败原因',
+  `created_at` int(11) DEFAULT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `trx` (`trx`),
+  KEY `created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `notify_log`
+--
+
+LOCK TABLES `notify_log` WRITE;
+/*!40000 ALTER TABLE `notify_log` DISABLE KEYS */;
+/*!40000 ALTER TABLE `notify_log` ENABLE KEYS */;
+UNLOCK TABLES;
+
 --
 -- Table structure for table `rule`
 --
@@ -51,6 +124,170 @@ INSERT INTO `rule` VALUES (3,'api/v1/test','GET','{\"record\":true,\"request\":{
 /*!40000 ALTER TABLE `rule` ENABLE KEYS */;
 UNLOCK TABLES;
 
+--
+-- Table structure for table `rule_canary`
+--
+
+DROP TABLE IF EXISTS `rule_canary`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `rule_canary` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `name` varchar(256) CHARACTER SET utf8 COLLATE utf8_bin NOT NULL COMMENT '规则名称',
+  `method` varchar(128) NOT NULL COMMENT '请求方法',
+  `version` varchar(128) CHARACTER SET utf8 COLLATE utf8_bin NOT NULL COMMENT '灰度版本',
+  `weight` int(11) NOT NULL DEFAULT 0 COMMENT '灰度版本的流量百分比',
+  `matches` text NOT NULL COMMENT '直接命中灰度版本的请求条件',
+  `sticky_key` varchar(256) NOT NULL DEFAULT '' COMMENT '流量分配的粘性键',
+  `operator` varchar(128) NOT NULL COMMENT '操作人员',
+  `operator_id` int(11) NOT NULL COMMENT '操作人员ID',
+  `created_at` int(11) DEFAULT NULL COMMENT '创建时间',
+  `updated_at` int(11) DEFAULT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `name` (`name`,`method`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `rule_canary`
+--
+
+LOCK TABLES `rule_canary` WRITE;
+/*!40000 ALTER TABLE `rule_canary` DISABLE KEYS */;
+/*!40000 ALTER TABLE `rule_canary` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `rule_case`
+--
+
+DROP TABLE IF EXISTS `rule_case`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `rule_case` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `name` varchar(256) CHARACTER SET utf8 COLLATE utf8_bin NOT NULL COMMENT '规则名称',
+  `method` varchar(128) NOT NULL COMMENT '规则请求方法',
+  `title` varchar(256) NOT NULL COMMENT '用例名称',
+  `request` text NOT NULL COMMENT '请求数据',
+  `mocks` text NOT NULL COMMENT '组件的模拟数据',
+  `assertions` text NOT NULL COMMENT '断言',
+  `operator` varchar(128) NOT NULL COMMENT '操作人员',
+  `operator_id` int(11) NOT NULL COMMENT '操作人员ID',
+  `created_at` int(11) DEFAULT NULL COMMENT '创建时间',
+  `updated_at` int(11) DEFAULT NULL COMMENT '更新时间',
+  `deleted_at` datetime DEFAULT NULL COMMENT '删除时间',
+  PRIMARY KEY (`id`),
+  KEY `name` (`name`,`method`),
+  KEY `deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `rule_case`
+--
+
+LOCK TABLES `rule_case` WRITE;
+/*!40000 ALTER TABLE `rule_case` DISABLE KEYS */;
+/*!40000 ALTER TABLE `rule_case` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `rule_case_result`
+--
+
+DROP TABLE IF EXISTS `rule_case_result`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `rule_case_result` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `case_id` int(11) NOT NULL COMMENT '测试用例id',
+  `name` varchar(256) CHARACTER SET utf8 COLLATE utf8_bin NOT NULL COMMENT '规则名称',
+  `method` varchar(128) NOT NULL COMMENT '规则请求方法',
+  `version` varchar(128) CHARACTER SET utf8 COLLATE utf8_bin NOT NULL COMMENT '规则版本',
+  `title` varchar(256) NOT NULL COMMENT '用例名称',
+  `passed` tinyint(1) NOT NULL COMMENT '是否通过',
+  `failures` text NOT NULL COMMENT '未通过的原因',
+  `response` text NOT NULL COMMENT '流程的返回数据',
+  `status` varchar(32) NOT NULL DEFAULT '' COMMENT '流程的执行状态',
+  `trx` varchar(128) NOT NULL COMMENT '请求唯一标识',
+  `created_at` int(11) DEFAULT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `version` (`version`),
+  KEY `case_id` (`case_id`),
+  KEY `name` (`name`,`method`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `rule_case_result`
+--
+
+LOCK TABLES `rule_case_result` WRITE;
+/*!40000 ALTER TABLE `rule_case_result` DISABLE KEYS */;
+/*!40000 ALTER TABLE `rule_case_result` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `rule_limit`
+--
+
+DROP TABLE IF EXISTS `rule_limit`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `rule_limit` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `name` varchar(256) CHARACTER SET utf8 COLLATE utf8_bin NOT NULL COMMENT '规则名称',
+  `method` varchar(128) NOT NULL COMMENT '请求方法',
+  `config` text NOT NULL COMMENT '限流配置',
+  `operator` varchar(128) NOT NULL COMMENT '操作人员',
+  `operator_id` int(11) NOT NULL COMMENT '操作人员ID',
+  `created_at` int(11) DEFAULT NULL COMMENT '创建时间',
+  `updated_at` int(11) DEFAULT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `name` (`name`,`method`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `rule_limit`
+--
+
+LOCK TABLES `rule_limit` WRITE;
+/*!40000 ALTER TABLE `rule_limit` DISABLE KEYS */;
+/*!40000 ALTER TABLE `rule_limit` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `rule_retention`
+--
+
+DROP TABLE IF EXISTS `rule_retention`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `rule_retention` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `name` varchar(256) CHARACTER SET utf8 COLLATE utf8_bin NOT NULL COMMENT '规则名称',
+  `method` varchar(128) NOT NULL COMMENT '请求方法',
+  `history_count` int(11) NOT NULL COMMENT '保留的版本数量',
+  `operator` varchar(128) NOT NULL COMMENT '操作人员',
+  `operator_id` int(11) NOT NULL COMMENT '操作人员ID',
+  `created_at` int(11) DEFAULT NULL COMMENT '创建时间',
+  `updated_at` int(11) DEFAULT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `name` (`name`,`method`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `rule_retention`
+--
+
+LOCK TABLES `rule_retention` WRITE;
+/*!40000 ALTER TABLE `rule_retention` DISABLE KEYS */;
+/*!40000 ALTER TABLE `rule_retention` ENABLE KEYS */;
+UNLOCK TABLES;
+
 --
 -- Table structure for table `run_log_0`
 --
@@ -117,6 +354,45 @@ INSERT INTO `run_log_1` VALUES (8,'TRX1F68B5F860BDEA7A778EEEDC1A1A596D','54D5329
 /*!40000 ALTER TABLE `run_log_1` ENABLE KEYS */;
 UNLOCK TABLES;
 
+--
+-- Table structure for table `schedule`
+--
+
+DROP TABLE IF EXISTS `schedule`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `schedule` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `name` varchar(256) CHARACTER SET utf8 COLLATE utf8_bin NOT NULL COMMENT '规则名称',
+  `method` varchar(128) NOT NULL COMMENT '规则请求方法',
+  `cron` varchar(128) NOT NULL COMMENT 'cron表达式',
+  `request` text NOT NULL COMMENT '触发时的请求body',
+  `description` varchar(256) NOT NULL COMMENT '定时任务描述',
+  `status` tinyint(1) NOT NULL COMMENT '状态，启用true,暂停false',
+  `last_trx` varchar(128) NOT NULL DEFAULT '' COMMENT '上次触发的请求唯一标识',
+  `last_time` int(11) NOT NULL DEFAULT 0 COMMENT '上次触发时间',
+  `next_time` int(11) NOT NULL DEFAULT 0 COMMENT '下次触发时间',
+  `operator` varchar(128) NOT NULL COMMENT '操作人员',
+  `operator_id` int(11) NOT NULL COMMENT '操作人员ID',
+  `created_at` int(11) DEFAULT NULL COMMENT '创建时间',
+  `updated_at` int(11) DEFAULT NULL COMMENT '更新时间',
+  `deleted_at` datetime DEFAULT NULL COMMENT '删除时间',
+  PRIMARY KEY (`id`),
+  KEY `name` (`name`,`method`),
+  KEY `status` (`status`),
+  KEY `deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `schedule`
+--
+
+LOCK TABLES `schedule` WRITE;
+/*!40000 ALTER TABLE `schedule` DISABLE KEYS */;
+/*!40000 ALTER TABLE `schedule` ENABLE KEYS */;
+UNLOCK TABLES;
+
 --
 -- Table structure for table `script`
 --
@@ -192,6 +468,7 @@ DROP TABLE IF EXISTS `suspend_log`;
 /*!50503 SET character_set_client = utf8mb4 */;
 CREATE TABLE `suspend_log` (
   `id` int(11) NOT NULL AUTO_INCREMENT,
+  `type` varchar(32) NOT NULL DEFAULT '' COMMENT '挂起类型 空为流程挂起 compensate为补偿失败挂起',
   `trx` varchar(128) CHARACTER SET utf8 COLLATE utf8_bin NOT NULL COMMENT '请求唯一标识',
   `version` varchar(128) CHARACTER SET utf8 COLLATE utf8_bin NOT NULL COMMENT '规则版本',
   `method` varchar(128) CHARACTER SET utf8 COLLATE utf8_bin NOT NULL COMMENT '规则名称',
@@ -204,6 +481,7 @@ CREATE TABLE `suspend_log` (
   `rule` text NOT NULL COMMENT '执行规则存档，方便修改',
   `data` text NOT NULL COMMENT '执行上下文数据',
   `err_component` varchar(256) NOT NULL COMMENT '执行失败的组件',
+  `finish_component` text COMMENT '已经完成的组件',
   `created_at` int(11) DEFAULT NULL COMMENT '创建时间',
   PRIMARY KEY (`id`),
   UNIQUE KEY `trx` (`trx`),
@@ -218,10 +496,114 @@ CREATE TABLE `suspend_log` (
 
 LOCK TABLES `suspend_log` WRITE;
 /*!40000 ALTER TABLE `suspend_log` DISABLE KEYS */;
-INSERT INTO `suspend_log` VALUES (1,'TRXDA0A3E8AC79FDBDDED3F16BD519E6773','2635904E88813FC144C7317A81DF8F91','GET','api/v1/test','1e273ce0-a51d-48cd-b63a-441b1ddf9fca',1,'110000','run script rule/api/test2.js timeout',1,'{\"version\":\"2635904E88813FC144C7317A81DF8F91\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"query\":{\"id\":{\"type\":\"object\",\"required\":true}},\"body\":{\"id\":{\"type\":\"string\",\"required\":true}},\"header\":{\"trx\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"data1\":\"{devops1}\",\"msg\":\"{response.body.msg}\"},\"header\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops1\",\"desc\":\"devops1流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops1\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops2\",\"desc\":\"devops2流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops2\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops3\",\"desc\":\"devops3流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test1.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops3\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"devops3\":{\"data\":{\"id\":\"1\"}},\"request\":{\"body\":{\"id\":\"1\"},\"header\":{\"trx\":\"11111\"},\"query\":{\"id\":{\"hello\":\"world\"}}}}','[\"devops\",\"devops1\",\"devops2\"]',1668340760),(2,'TRX7A9DE11280C3AC7D633967AE24FC3792','2635904E88813FC144C7317A81DF8F91','GET','api/v1/test','eb3529b0-b38e-4d79-9b9c-af12e20eb8c0',1,'110000','run script rule/api/test2.js timeout',1,'{\"version\":\"2635904E88813FC144C7317A81DF8F91\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"query\":{\"id\":{\"type\":\"object\",\"required\":true}},\"body\":{\"id\":{\"type\":\"string\",\"required\":true}},\"header\":{\"trx\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"data1\":\"{devops1}\",\"msg\":\"{response.body.msg}\"},\"header\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops1\",\"desc\":\"devops1流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops1\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops2\",\"desc\":\"devops2流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops2\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops3\",\"desc\":\"devops3流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test1.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops3\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"devops3\":{\"data\":{\"id\":\"1\"}},\"request\":{\"body\":{\"id\":\"1\"},\"header\":{\"trx\":\"11111\"},\"query\":{\"id\":{\"hello\":\"world\"}}}}','[\"devops1\",\"devops2\"]',1668341222),(3,'TRX24A9D0F49984B0508D35CE70E6E642C6','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','f7967978-ccca-493e-bb00-2593fab5f008',1,'000400','加载脚本rule/api/test.js失败：record not found',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"query\":{\"id\":{\"type\":\"object\",\"required\":true}},\"body\":{\"id\":{\"type\":\"string\",\"required\":true}},\"header\":{\"trx\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"data1\":\"{devops1}\",\"msg\":\"{response.body.msg}\"},\"header\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops1\",\"desc\":\"devops1流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops1\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops2\",\"desc\":\"devops2流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops2\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops3\",\"desc\":\"devops3流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops3\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{\"trx\":\"11111\"},\"query\":{\"id\":{\"hello\":\"world\"}}}}','[\"devops3\"]',1668341546),(4,'TRX89DFA9CD151B89CCEA1E7D3479F78CD1','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','fdf4d164-151e-4c43-876e-994a714f2cb2',1,'110001','(anonymous): Line 1:56 Unexpected string (and 9 more errors)',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"query\":{\"id\":{\"type\":\"object\",\"required\":true}},\"body\":{\"id\":{\"type\":\"string\",\"required\":true}},\"header\":{\"trx\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"data1\":\"{devops1}\",\"msg\":\"{response.body.msg}\"},\"header\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops1\",\"desc\":\"devops1流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops1\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops2\",\"desc\":\"devops2流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops2\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops3\",\"desc\":\"devops3流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops3\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{\"trx\":\"11111\"},\"query\":{\"id\":{\"hello\":\"world\"}}}}','[\"devops3\"]',1668341635),(5,'TRX30C2B86F8AA71CA3D30DD25330B4BAB3','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','6b26cb1d-f191-4a45-a08b-11e1fc826231',1,'110000','run script rule/api/test2.js timeout',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"query\":{\"id\":{\"type\":\"object\",\"required\":true}},\"body\":{\"id\":{\"type\":\"string\",\"required\":true}},\"header\":{\"trx\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"body\":{\"code\":null,\"data\":null,\"data1\":null,\"msg\":null},\"header\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops1\",\"desc\":\"devops1流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops1\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops2\",\"desc\":\"devops2流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops2\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops3\",\"desc\":\"devops3流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops3\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{\"trx\":\"11111\"},\"query\":{\"id\":{\"hello\":\"world\"}}},\"response\":{\"body\":{\"response\":{\"body\":{\"code\":\"1\",\"data\":\"2\",\"data1\":\"3\"}}}}}','[\"devops1\",\"devops\",\"devops2\"]',1668341840),(6,'TRX151D4B7661414210C06FDB20D0CDF662','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','21320e99-33ec-4e76-9fec-93099b61d173',1,'110000','run script rule/api/test.js timeout',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"query\":{\"id\":{\"type\":\"object\",\"required\":true}},\"body\":{\"id\":{\"type\":\"string\",\"required\":true}},\"header\":{\"trx\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"body\":{\"code\":\"1\",\"data\":null,\"data1\":null,\"msg\":null},\"header\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops1\",\"desc\":\"devops1流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops1\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops2\",\"desc\":\"devops2流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops2\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops3\",\"desc\":\"devops3流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops3\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{\"trx\":\"11111\"},\"query\":{\"id\":{\"hello\":\"world\"}}},\"response\":{\"body\":{\"code\":\"1\",\"data\":\"2\",\"data1\":\"3\"}}}','[\"devops3\",\"devops2\"]',1668342020),(7,'TRXF58DECC0AA8CE759CB3258AE1082CBDA','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','52472bdc-5ba5-482c-b485-1249facc8f43',1,'110000','run script rule/api/test2.js timeout',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"query\":{\"id\":{\"type\":\"object\",\"required\":true}},\"body\":{\"id\":{\"type\":\"string\",\"required\":true}},\"header\":{\"trx\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"body\":{\"code\":\"1\",\"data\":null,\"data1\":null,\"msg\":\"3\"},\"header\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops1\",\"desc\":\"devops1流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops1\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops2\",\"desc\":\"devops2流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops2\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops3\",\"desc\":\"devops3流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops3\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{\"trx\":\"11111\"},\"query\":{\"id\":{\"hello\":\"world\"}}},\"response\":{\"body\":{\"code\":\"1\",\"data\":\"2\",\"msg\":\"3\"}}}','[\"devops1\",\"devops2\",\"devops\"]',1668342094),(8,'TRXFA1F2C833390B85F2AC16F2D00678977','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','c6077e0b-f3f7-4d9e-96cd-0adc700fc79f',1,'110001','(anonymous): Line 1:56 Unexpected string (and 9 more errors)',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"query\":{\"id\":{\"type\":\"object\",\"required\":true}},\"body\":{\"id\":{\"type\":\"string\",\"required\":true}},\"header\":{\"trx\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"data1\":\"{devops1}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops1\",\"desc\":\"devops1流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops1\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops2\",\"desc\":\"devops2流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops2\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops3\",\"desc\":\"devops3流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test.js\",\"isCache\":false,\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops3\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{\"trx\":\"11111\"},\"query\":{\"id\":{\"hello\":\"world\"}}}}','[\"devops2\"]',1668648638),(9,'TRX4CEBF54EF152200CA4500A334853E777','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','55056aaf-991b-43d9-a975-0eb6492a94c6',1,'110001','(anonymous): Line 1:56 Unexpected string (and 9 more errors)',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"query\":{\"id\":{\"type\":\"object\",\"required\":true}},\"body\":{\"id\":{\"type\":\"string\",\"required\":true}},\"header\":{\"trx\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"data1\":\"{devops1}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops1\",\"desc\":\"devops1流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops1\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops2\",\"desc\":\"devops2流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops2\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops3\",\"desc\":\"devops3流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test.js\",\"isCache\":false,\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops3\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{\"trx\":\"11111\"},\"query\":{\"id\":{\"hello\":\"world\"}}}}','[\"devops\",\"devops1\"]',1668648642),(10,'TRXC3AEC7A486B27699B839D12D975EEDD3','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','29f98813-77b9-4799-abfa-4078cb6e0ae2',1,'110001','(anonymous): Line 1:56 Unexpected string (and 9 more errors)',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"xml\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"xmlName\":\"\",\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":{\"code\":\"111\",\"data\":\"{id}\",\"msg\":\"test\"},\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{},\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"}}}','[\"devops\"]',1668656691),(11,'TRX877E3CCE72BD50E52EB193FD5F56A2C4','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','c9991837-8950-41b0-b23e-d6751bf98146',1,'110001','(anonymous): Line 1:56 Unexpected string (and 9 more errors)',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"xml\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"xmlName\":\"\",\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":{\"code\":\"111\",\"data\":\"{id}\",\"msg\":\"test\"},\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{},\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"}}}','[\"devops\"]',1668656773),(12,'TRX733415DE8FC4F44B1E7F5EC79C34F1D3','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','4a6c342c-5202-40b4-ada7-131f61b6b943',1,'110001','(anonymous): Line 1:56 Unexpected string (and 9 more errors)',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"xml\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"xmlName\":\"\",\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":{\"code\":\"111\",\"data\":\"{id}\",\"msg\":\"test\"},\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{},\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"}}}','[\"devops\"]',1668656775),(13,'TRX1CFCA3ABFE749D418E50BFCCAA7F5B06','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','dcee4f82-d766-4641-aa22-e244779ca5da',1,'110001','(anonymous): Line 1:56 Unexpected string (and 9 more errors)',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"xml\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"xmlName\":\"\",\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":{\"code\":\"111\",\"data\":\"{id}\",\"msg\":\"test\"},\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{},\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"}}}','[\"devops\"]',1668656871),(14,'TRX71098147B11710A2739882CB9FFE5AA9','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','1b042073-14cb-4e87-95ac-690d2a08f412',1,'110001','(anonymous): Line 1:56 Unexpected string (and 9 more errors)',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"xml\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"xmlName\":\"\",\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":{\"code\":\"111\",\"data\":\"{id}\",\"msg\":\"test\"},\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{},\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"}}}','[\"devops\"]',1668656987),(15,'TRX58BAF0FD43F3CBA64D41C6E11F4677FA','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','510def58-91bb-4225-8185-b803f2f9dc2a',1,'000400','request method not empty',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"api\",\"input\":\"{request.body}\",\"url\":\"http://localhost:8081/hello\",\"isCache\":false,\"requestType\":\"xml\",\"responseType\":\"xml\",\"xmlName\":\"\",\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{},\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"}}}','[\"devops\"]',1668672782),(16,'TRXDA7D7B3E7B1D0CB713A0A7B4E836F3CF','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','f5e944f4-1b40-461e-abb8-e3c51e47a68d',1,'110004','(anonymous): Line 1:26 Unexpected identifier (and 2 more errors)',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"api\",\"input\":{\"ID\":\"1\"},\"url\":\"http://localhost:8081/hello\",\"isCache\":false,\"method\":\"post\",\"requestType\":\"xml\",\"responseType\":\"xml\",\"xmlName\":\"Result\",\"responseCondition\":\"{code}!=0\",\"errorMsg\":\"{msg}\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{},\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"}}}','[\"devops\"]',1668674234),(17,'TRX154DB3F5EEA9D266FDFBA0E67ACFFA02','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','b9cb5a11-b6fd-45ea-b150-df231f37f8f0',1,'110004','(anonymous): Line 1:26 Unexpected identifier (and 2 more errors)',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"api\",\"input\":{\"ID\":\"1\"},\"url\":\"http://localhost:8081/hello\",\"isCache\":false,\"method\":\"post\",\"requestType\":\"xml\",\"responseType\":\"xml\",\"xmlName\":\"Result\",\"responseCondition\":\"{code}!=0\",\"errorMsg\":\"{msg}\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{},\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"}}}','[\"devops\"]',1668674352),(18,'TRX534ABFDC0053012F460A77261BED92A1','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','e7ede0b5-1565-425a-8c78-272daa2d6275',1,'110004','(anonymous): Line 1:26 Unexpected identifier (and 2 more errors)',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"api\",\"input\":{\"ID\":\"1\"},\"url\":\"http://localhost:8081/hello\",\"isCache\":false,\"method\":\"post\",\"requestType\":\"xml\",\"responseType\":\"xml\",\"xmlName\":\"Result\",\"responseCondition\":\"{code}!=0\",\"errorMsg\":\"{msg}\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{},\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"}}}','[\"devops\"]',1668674535),(19,'TRX5C42EA4DAF255049B9B3E76AC87AB6D7','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','f87fc7b2-09b2-47cb-9bf8-be6f4747e68d',1,'000400','<nil>',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"api\",\"input\":{\"ID\":\"1\"},\"url\":\"http://localhost:8081/hello\",\"isCache\":false,\"method\":\"post\",\"requestType\":\"xml\",\"responseType\":\"xml\",\"xmlName\":\"Result\",\"responseCondition\":\"{code}==0\",\"errorMsg\":\"{msg}\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{},\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"}}}','[\"devops\"]',1668675556),(20,'TRX3450195710492EAC5AFBD754063B8697','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','729a6209-29b4-4f17-bb9d-d9c537bfa96e',1,'000400','hello',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"api\",\"input\":{\"ID\":\"1\"},\"url\":\"http://localhost:8081/hello\",\"isCache\":false,\"method\":\"post\",\"requestType\":\"xml\",\"responseType\":\"xml\",\"xmlName\":\"Result\",\"responseCondition\":\"{code}==0\",\"errorMsg\":\"{msg}\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{},\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"}}}','[\"devops\"]',1668675852),(21,'TRX42516149A476519ABD5B95B32DB23EA5','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','1b471e66-c931-4f84-b107-2b79837a2abf',1,'000400','hello',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"api\",\"input\":{\"ID\":\"1\"},\"url\":\"http://localhost:8081/hello\",\"isCache\":false,\"method\":\"post\",\"requestType\":\"xml\",\"responseType\":\"xml\",\"xmlName\":\"Result\",\"responseCondition\":\"{code}==0\",\"errorMsg\":\"{msg}\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{},\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"}}}','[\"devops\"]',1668676283),(22,'TRX1874AB61FC1CE91B21132FE6DF70AF50','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','39c16093-77af-4a7b-aab2-2e031dc100b6',1,'000400','hello',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"xmlName\":\"Result\",\"body\":{\"msg\":\"{response.body.msg}\",\"data\":\"{devops}\",\"code\":\"{response.body.code}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"api\",\"input\":{\"ID\":\"1\"},\"url\":\"http://localhost:8081/hello\",\"isCache\":false,\"method\":\"post\",\"requestType\":\"xml\",\"responseType\":\"xml\",\"xmlName\":\"Result\",\"responseCondition\":\"{code}==0\",\"errorMsg\":\"{msg}\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"},\"body\":{\"id\":\"1\"},\"header\":{}}}','[\"devops\"]',1668676776),(23,'TRX7A8F876ECB35E0D3614CE772EAB6F387','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','6464bce9-a18e-4801-bfde-e5fe4a247caf',1,'000400','hello',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"msg\":\"{response.body.msg}\",\"data\":\"{devops}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"api\",\"input\":{\"ID\":\"1\"},\"url\":\"http://localhost:8081/hello\",\"isCache\":false,\"method\":\"post\",\"requestType\":\"xml\",\"responseType\":\"xml\",\"xmlName\":\"Result\",\"responseCondition\":\"{code}==0\",\"errorMsg\":\"{msg}\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"},\"body\":{\"id\":\"1\"},\"header\":{}}}','[\"devops\"]',1668676821);
+INSERT INTO `suspend_log` VALUES (1,'','TRXDA0A3E8AC79FDBDDED3F16BD519E6773','2635904E88813FC144C7317A81DF8F91','GET','api/v1/test','1e273ce0-a51d-48cd-b63a-441b1ddf9fca',1,'110000','run script rule/api/test2.js timeout',1,'{\"version\":\"2635904E88813FC144C7317A81DF8F91\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"query\":{\"id\":{\"type\":\"object\",\"required\":true}},\"body\":{\"id\":{\"type\":\"string\",\"required\":true}},\"header\":{\"trx\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"data1\":\"{devops1}\",\"msg\":\"{response.body.msg}\"},\"header\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops1\",\"desc\":\"devops1流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops1\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops2\",\"desc\":\"devops2流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops2\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops3\",\"desc\":\"devops3流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test1.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops3\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"devops3\":{\"data\":{\"id\":\"1\"}},\"request\":{\"body\":{\"id\":\"1\"},\"header\":{\"trx\":\"11111\"},\"query\":{\"id\":{\"hello\":\"world\"}}}}','[\"devops\",\"devops1\",\"devops2\"]',NULL,1668340760),(2,'','TRX7A9DE11280C3AC7D633967AE24FC3792','2635904E88813FC144C7317A81DF8F91','GET','api/v1/test','eb3529b0-b38e-4d79-9b9c-af12e20eb8c0',1,'110000','run script rule/api/test2.js timeout',1,'{\"version\":\"2635904E88813FC144C7317A81DF8F91\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"query\":{\"id\":{\"type\":\"object\",\"required\":true}},\"body\":{\"id\":{\"type\":\"string\",\"required\":true}},\"header\":{\"trx\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"data1\":\"{devops1}\",\"msg\":\"{response.body.msg}\"},\"header\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops1\",\"desc\":\"devops1流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops1\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops2\",\"desc\":\"devops2流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops2\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops3\",\"desc\":\"devops3流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test1.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops3\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"devops3\":{\"data\":{\"id\":\"1\"}},\"request\":{\"body\":{\"id\":\"1\"},\"header\":{\"trx\":\"11111\"},\"query\":{\"id\":{\"hello\":\"world\"}}}}','[\"devops1\",\"devops2\"]',NULL,1668341222),(3,'','TRX24A9D0F49984B0508D35CE70E6E642C6','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','f7967978-ccca-493e-bb00-2593fab5f008',1,'000400','加载脚本rule/api/test.js失败：record not found',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"query\":{\"id\":{\"type\":\"object\",\"required\":true}},\"body\":{\"id\":{\"type\":\"string\",\"required\":true}},\"header\":{\"trx\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"data1\":\"{devops1}\",\"msg\":\"{response.body.msg}\"},\"header\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops1\",\"desc\":\"devops1流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops1\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops2\",\"desc\":\"devops2流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops2\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops3\",\"desc\":\"devops3流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops3\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{\"trx\":\"11111\"},\"query\":{\"id\":{\"hello\":\"world\"}}}}','[\"devops3\"]',NULL,1668341546),(4,'','TRX89DFA9CD151B89CCEA1E7D3479F78CD1','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','fdf4d164-151e-4c43-876e-994a714f2cb2',1,'110001','(anonymous): Line 1:56 Unexpected string (and 9 more errors)',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"query\":{\"id\":{\"type\":\"object\",\"required\":true}},\"body\":{\"id\":{\"type\":\"string\",\"required\":true}},\"header\":{\"trx\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"data1\":\"{devops1}\",\"msg\":\"{response.body.msg}\"},\"header\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops1\",\"desc\":\"devops1流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops1\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops2\",\"desc\":\"devops2流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops2\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops3\",\"desc\":\"devops3流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops3\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{\"trx\":\"11111\"},\"query\":{\"id\":{\"hello\":\"world\"}}}}','[\"devops3\"]',NULL,1668341635),(5,'','TRX30C2B86F8AA71CA3D30DD25330B4BAB3','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','6b26cb1d-f191-4a45-a08b-11e1fc826231',1,'110000','run script rule/api/test2.js timeout',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"query\":{\"id\":{\"type\":\"object\",\"required\":true}},\"body\":{\"id\":{\"type\":\"string\",\"required\":true}},\"header\":{\"trx\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"body\":{\"code\":null,\"data\":null,\"data1\":null,\"msg\":null},\"header\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops1\",\"desc\":\"devops1流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops1\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops2\",\"desc\":\"devops2流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops2\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops3\",\"desc\":\"devops3流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops3\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{\"trx\":\"11111\"},\"query\":{\"id\":{\"hello\":\"world\"}}},\"response\":{\"body\":{\"response\":{\"body\":{\"code\":\"1\",\"data\":\"2\",\"data1\":\"3\"}}}}}','[\"devops1\",\"devops\",\"devops2\"]',NULL,1668341840),(6,'','TRX151D4B7661414210C06FDB20D0CDF662','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','21320e99-33ec-4e76-9fec-93099b61d173',1,'110000','run script rule/api/test.js timeout',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"query\":{\"id\":{\"type\":\"object\",\"required\":true}},\"body\":{\"id\":{\"type\":\"string\",\"required\":true}},\"header\":{\"trx\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"body\":{\"code\":\"1\",\"data\":null,\"data1\":null,\"msg\":null},\"header\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops1\",\"desc\":\"devops1流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops1\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops2\",\"desc\":\"devops2流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops2\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops3\",\"desc\":\"devops3流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops3\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{\"trx\":\"11111\"},\"query\":{\"id\":{\"hello\":\"world\"}}},\"response\":{\"body\":{\"code\":\"1\",\"data\":\"2\",\"data1\":\"3\"}}}','[\"devops3\",\"devops2\"]',NULL,1668342020),(7,'','TRXF58DECC0AA8CE759CB3258AE1082CBDA','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','52472bdc-5ba5-482c-b485-1249facc8f43',1,'110000','run script rule/api/test2.js timeout',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"query\":{\"id\":{\"type\":\"object\",\"required\":true}},\"body\":{\"id\":{\"type\":\"string\",\"required\":true}},\"header\":{\"trx\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"body\":{\"code\":\"1\",\"data\":null,\"data1\":null,\"msg\":\"3\"},\"header\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops1\",\"desc\":\"devops1流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops1\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops2\",\"desc\":\"devops2流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops2\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops3\",\"desc\":\"devops3流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test.js\",\"isCache\":false,\"timeout\":10,\"outputName\":\"devops3\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{\"trx\":\"11111\"},\"query\":{\"id\":{\"hello\":\"world\"}}},\"response\":{\"body\":{\"code\":\"1\",\"data\":\"2\",\"msg\":\"3\"}}}','[\"devops1\",\"devops2\",\"devops\"]',NULL,1668342094),(8,'','TRXFA1F2C833390B85F2AC16F2D00678977','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','c6077e0b-f3f7-4d9e-96cd-0adc700fc79f',1,'110001','(anonymous): Line 1:56 Unexpected string (and 9 more errors)',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"query\":{\"id\":{\"type\":\"object\",\"required\":true}},\"body\":{\"id\":{\"type\":\"string\",\"required\":true}},\"header\":{\"trx\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"data1\":\"{devops1}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops1\",\"desc\":\"devops1流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops1\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops2\",\"desc\":\"devops2流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops2\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops3\",\"desc\":\"devops3流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test.js\",\"isCache\":false,\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops3\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{\"trx\":\"11111\"},\"query\":{\"id\":{\"hello\":\"world\"}}}}','[\"devops2\"]',NULL,1668648638),(9,'','TRX4CEBF54EF152200CA4500A334853E777','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','55056aaf-991b-43d9-a975-0eb6492a94c6',1,'110001','(anonymous): Line 1:56 Unexpected string (and 9 more errors)',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"query\":{\"id\":{\"type\":\"object\",\"required\":true}},\"body\":{\"id\":{\"type\":\"string\",\"required\":true}},\"header\":{\"trx\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"data1\":\"{devops1}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops1\",\"desc\":\"devops1流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops1\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops2\",\"desc\":\"devops2流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops2\",\"retryMaxCount\":0,\"retryMaxWait\":0},{\"name\":\"devops3\",\"desc\":\"devops3流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test.js\",\"isCache\":false,\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops3\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{\"trx\":\"11111\"},\"query\":{\"id\":{\"hello\":\"world\"}}}}','[\"devops\",\"devops1\"]',NULL,1668648642),(10,'','TRXC3AEC7A486B27699B839D12D975EEDD3','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','29f98813-77b9-4799-abfa-4078cb6e0ae2',1,'110001','(anonymous): Line 1:56 Unexpected string (and 9 more errors)',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"xml\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"xmlName\":\"\",\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":{\"code\":\"111\",\"data\":\"{id}\",\"msg\":\"test\"},\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{},\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"}}}','[\"devops\"]',NULL,1668656691),(11,'','TRX877E3CCE72BD50E52EB193FD5F56A2C4','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','c9991837-8950-41b0-b23e-d6751bf98146',1,'110001','(anonymous): Line 1:56 Unexpected string (and 9 more errors)',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"xml\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"xmlName\":\"\",\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":{\"code\":\"111\",\"data\":\"{id}\",\"msg\":\"test\"},\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{},\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"}}}','[\"devops\"]',NULL,1668656773),(12,'','TRX733415DE8FC4F44B1E7F5EC79C34F1D3','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','4a6c342c-5202-40b4-ada7-131f61b6b943',1,'110001','(anonymous): Line 1:56 Unexpected string (and 9 more errors)',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"xml\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"xmlName\":\"\",\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":{\"code\":\"111\",\"data\":\"{id}\",\"msg\":\"test\"},\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{},\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"}}}','[\"devops\"]',NULL,1668656775),(13,'','TRX1CFCA3ABFE749D418E50BFCCAA7F5B06','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','dcee4f82-d766-4641-aa22-e244779ca5da',1,'110001','(anonymous): Line 1:56 Unexpected string (and 9 more errors)',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"xml\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"xmlName\":\"\",\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":{\"code\":\"111\",\"data\":\"{id}\",\"msg\":\"test\"},\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{},\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"}}}','[\"devops\"]',NULL,1668656871),(14,'','TRX71098147B11710A2739882CB9FFE5AA9','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','1b042073-14cb-4e87-95ac-690d2a08f412',1,'110001','(anonymous): Line 1:56 Unexpected string (and 9 more errors)',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"xml\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"script\",\"input\":{\"data\":{\"id\":\"1\"}},\"url\":\"rule/api/test2.js\",\"isCache\":false,\"xmlName\":\"\",\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":{\"code\":\"111\",\"data\":\"{id}\",\"msg\":\"test\"},\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{},\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"}}}','[\"devops\"]',NULL,1668656987),(15,'','TRX58BAF0FD43F3CBA64D41C6E11F4677FA','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','510def58-91bb-4225-8185-b803f2f9dc2a',1,'000400','request method not empty',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"api\",\"input\":\"{request.body}\",\"url\":\"http://localhost:8081/hello\",\"isCache\":false,\"requestType\":\"xml\",\"responseType\":\"xml\",\"xmlName\":\"\",\"responseCondition\":\"\",\"errorMsg\":\"\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{},\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"}}}','[\"devops\"]',NULL,1668672782),(16,'','TRXDA7D7B3E7B1D0CB713A0A7B4E836F3CF','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','f5e944f4-1b40-461e-abb8-e3c51e47a68d',1,'110004','(anonymous): Line 1:26 Unexpected identifier (and 2 more errors)',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"api\",\"input\":{\"ID\":\"1\"},\"url\":\"http://localhost:8081/hello\",\"isCache\":false,\"method\":\"post\",\"requestType\":\"xml\",\"responseType\":\"xml\",\"xmlName\":\"Result\",\"responseCondition\":\"{code}!=0\",\"errorMsg\":\"{msg}\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{},\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"}}}','[\"devops\"]',NULL,1668674234),(17,'','TRX154DB3F5EEA9D266FDFBA0E67ACFFA02','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','b9cb5a11-b6fd-45ea-b150-df231f37f8f0',1,'110004','(anonymous): Line 1:26 Unexpected identifier (and 2 more errors)',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"api\",\"input\":{\"ID\":\"1\"},\"url\":\"http://localhost:8081/hello\",\"isCache\":false,\"method\":\"post\",\"requestType\":\"xml\",\"responseType\":\"xml\",\"xmlName\":\"Result\",\"responseCondition\":\"{code}!=0\",\"errorMsg\":\"{msg}\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{},\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"}}}','[\"devops\"]',NULL,1668674352),(18,'','TRX534ABFDC0053012F460A77261BED92A1','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','e7ede0b5-1565-425a-8c78-272daa2d6275',1,'110004','(anonymous): Line 1:26 Unexpected identifier (and 2 more errors)',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"api\",\"input\":{\"ID\":\"1\"},\"url\":\"http://localhost:8081/hello\",\"isCache\":false,\"method\":\"post\",\"requestType\":\"xml\",\"responseType\":\"xml\",\"xmlName\":\"Result\",\"responseCondition\":\"{code}!=0\",\"errorMsg\":\"{msg}\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{},\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"}}}','[\"devops\"]',NULL,1668674535),(19,'','TRX5C42EA4DAF255049B9B3E76AC87AB6D7','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','f87fc7b2-09b2-47cb-9bf8-be6f4747e68d',1,'000400','<nil>',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"api\",\"input\":{\"ID\":\"1\"},\"url\":\"http://localhost:8081/hello\",\"isCache\":false,\"method\":\"post\",\"requestType\":\"xml\",\"responseType\":\"xml\",\"xmlName\":\"Result\",\"responseCondition\":\"{code}==0\",\"errorMsg\":\"{msg}\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{},\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"}}}','[\"devops\"]',NULL,1668675556),(20,'','TRX3450195710492EAC5AFBD754063B8697','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','729a6209-29b4-4f17-bb9d-d9c537bfa96e',1,'000400','hello',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"api\",\"input\":{\"ID\":\"1\"},\"url\":\"http://localhost:8081/hello\",\"isCache\":false,\"method\":\"post\",\"requestType\":\"xml\",\"responseType\":\"xml\",\"xmlName\":\"Result\",\"responseCondition\":\"{code}==0\",\"errorMsg\":\"{msg}\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{},\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"}}}','[\"devops\"]',NULL,1668675852),(21,'','TRX42516149A476519ABD5B95B32DB23EA5','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','1b471e66-c931-4f84-b107-2b79837a2abf',1,'000400','hello',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"data\":\"{devops}\",\"msg\":\"{response.body.msg}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"api\",\"input\":{\"ID\":\"1\"},\"url\":\"http://localhost:8081/hello\",\"isCache\":false,\"method\":\"post\",\"requestType\":\"xml\",\"responseType\":\"xml\",\"xmlName\":\"Result\",\"responseCondition\":\"{code}==0\",\"errorMsg\":\"{msg}\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"body\":{\"id\":\"1\"},\"header\":{},\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"}}}','[\"devops\"]',NULL,1668676283),(22,'','TRX1874AB61FC1CE91B21132FE6DF70AF50','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','39c16093-77af-4a7b-aab2-2e031dc100b6',1,'000400','hello',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"xmlName\":\"Result\",\"body\":{\"msg\":\"{response.body.msg}\",\"data\":\"{devops}\",\"code\":\"{response.body.code}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"api\",\"input\":{\"ID\":\"1\"},\"url\":\"http://localhost:8081/hello\",\"isCache\":false,\"method\":\"post\",\"requestType\":\"xml\",\"responseType\":\"xml\",\"xmlName\":\"Result\",\"responseCondition\":\"{code}==0\",\"errorMsg\":\"{msg}\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"},\"body\":{\"id\":\"1\"},\"header\":{}}}','[\"devops\"]',NULL,1668676776),(23,'','TRX7A8F876ECB35E0D3614CE772EAB6F387','54D5329CB396D2B1AB6234EB8A9F1854','GET','api/v1/test','6464bce9-a18e-4801-bfde-e5fe4a247caf',1,'000400','hello',1,'{\"version\":\"54D5329CB396D2B1AB6234EB8A9F1854\",\"record\":true,\"suspend\":true,\"request\":{\"type\":\"json\",\"body\":{\"id\":{\"type\":\"string\",\"required\":true}}},\"response\":{\"type\":\"json\",\"xmlName\":\"Result\",\"body\":{\"code\":\"{response.body.code}\",\"msg\":\"{response.body.msg}\",\"data\":\"{devops}\"},\"header\":null,\"defaultBody\":null},\"components\":[[{\"name\":\"devops\",\"desc\":\"流程描述\",\"type\":\"api\",\"input\":{\"ID\":\"1\"},\"url\":\"http://localhost:8081/hello\",\"isCache\":false,\"method\":\"post\",\"requestType\":\"xml\",\"responseType\":\"xml\",\"xmlName\":\"Result\",\"responseCondition\":\"{code}==0\",\"errorMsg\":\"{msg}\",\"nowResponse\":false,\"ignoreError\":false,\"outputData\":null,\"timeout\":10,\"outputName\":\"devops\",\"retryMaxCount\":0,\"retryMaxWait\":0}]]}','{\"request\":{\"query\":{\"id\":\"{\\\"hello\\\":\\\"world\\\"}\"},\"body\":{\"id\":\"1\"},\"header\":{}}}','[\"devops\"]',NULL,1668676821);
 /*!40000 ALTER TABLE `suspend_log` ENABLE KEYS */;
 UNLOCK TABLES;
 
+--
+-- Table structure for table `version_review`
+--
+
+DROP TABLE IF EXISTS `version_review`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `version_review` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `kind` varchar(32) NOT NULL COMMENT '版本类型',
+  `name` varchar(256) CHARACTER SET utf8 COLLATE utf8_bin NOT NULL COMMENT '规则或者脚本名称',
+  `method` varchar(128) NOT NULL DEFAULT '' COMMENT '规则请求方法',
+  `version` varchar(128) CHARACTER SET utf8 COLLATE utf8_bin NOT NULL COMMENT '版本',
+  `state` varchar(32) NOT NULL COMMENT '审核状态',
+  `author` varchar(128) NOT NULL COMMENT '版本作者',
+  `author_id` int(11) NOT NULL COMMENT '版本作者ID',
+  `reviewer` varchar(128) NOT NULL DEFAULT '' COMMENT '审核人员',
+  `reviewer_id` int(11) NOT NULL DEFAULT 0 COMMENT '审核人员ID',
+  `publish_at` int(11) NOT NULL DEFAULT 0 COMMENT '定时发布时间',
+  `created_at` int(11) DEFAULT NULL COMMENT '创建时间',
+  `updated_at` int(11) DEFAULT NULL COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `version` (`kind`,`version`),
+  KEY `state` (`state`,`publish_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `version_review`
+--
+
+LOCK TABLES `version_review` WRITE;
+/*!40000 ALTER TABLE `version_review` DISABLE KEYS */;
+/*!40000 ALTER TABLE `version_review` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `version_review_log`
+--
+
+DROP TABLE IF EXISTS `version_review_log`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `version_review_log` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `kind` varchar(32) NOT NULL COMMENT '版本类型',
+  `version` varchar(128) CHARACTER SET utf8 COLLATE utf8_bin NOT NULL COMMENT '版本',
+  `from_state` varchar(32) NOT NULL DEFAULT '' COMMENT '变更前的状态',
+  `to_state` varchar(32) NOT NULL COMMENT '变更后的状态',
+  `comment` varchar(1024) NOT NULL DEFAULT '' COMMENT '变更说明',
+  `operator` varchar(128) NOT NULL COMMENT '操作人员',
+  `operator_id` int(11) NOT NULL COMMENT '操作人员ID',
+  `created_at` int(11) DEFAULT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `version` (`kind`,`version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `version_review_log`
+--
+
+LOCK TABLES `version_review_log` WRITE;
+/*!40000 ALTER TABLE `version_review_log` DISABLE KEYS */;
+/*!40000 ALTER TABLE `version_review_log` ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `wait_log`
+--
+
+DROP TABLE IF EXISTS `wait_log`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `wait_log` (
+  `id` int(11) NOT NULL AUTO_INCREMENT,
+  `trx` varchar(128) NOT NULL COMMENT '唯一请求id',
+  `method` varchar(128) NOT NULL COMMENT '请求方法',
+  `path` varchar(256) NOT NULL COMMENT '请求路径',
+  `version` varchar(128) NOT NULL COMMENT '规则版本',
+  `log_id` varchar(128) NOT NULL COMMENT '日志id',
+  `step` int(11) NOT NULL COMMENT '总步数',
+  `cur_step` int(11) NOT NULL COMMENT '当前执行步',
+  `rule` text NOT NULL COMMENT '流程规则',
+  `data` text NOT NULL COMMENT '流程上下文数据',
+  `waits` text NOT NULL COMMENT '等待信号的组件',
+  `finish_component` text COMMENT '已完成的组件标志',
+  `timeout_at` int(11) NOT NULL DEFAULT 0 COMMENT '最近的等待超时时间，0为不超时',
+  `created_at` int(11) DEFAULT NULL COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `trx` (`trx`),
+  KEY `timeout_at` (`timeout_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `wait_log`
+--
+
+LOCK TABLES `wait_log` WRITE;
+/*!40000 ALTER TABLE `wait_log` DISABLE KEYS */;
+/*!40000 ALTER TABLE `wait_log` ENABLE KEYS */;
+UNLOCK TABLES;
+
 --
 -- Dumping events for database 'process_schedule'
 --
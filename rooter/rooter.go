@@ -13,38 +13,95 @@ func Init() *gin.Engine {
 	// 健康检查
 	engine.GET("/check_healthy", gin.Success())
 
-	// 调度引擎的后台服务api
+	// 调度引擎的后台服务api，变更操作通过handler.Audit记录审计日志，查询、校验以及试运行不记录
 	api := engine.Group("/api/v1")
 	{
 		// 流程规则相关api
 		api.GET("/rule", handler.GetRule)
 		api.GET("/rule/page", handler.PageRule)
-		api.POST("/rule", handler.AddRule)
-		api.PUT("/rule/switch_version", handler.SwitchRuleVersion)
-		api.DELETE("/rule", handler.DeleteRule)
+		api.POST("/rule", handler.Audit, handler.AddRule)
+		api.POST("/rule/validate", handler.ValidateRule)
+		api.POST("/rule/dry_run", handler.DryRunRule)
+		api.PUT("/rule/switch_version", handler.Audit, handler.SwitchRuleVersion)
+		api.GET("/rule/diff", handler.DiffRule)
+		api.GET("/rule/export", handler.ExportRule)
+		api.POST("/rule/import", handler.Audit, handler.ImportRule)
+		api.DELETE("/rule", handler.Audit, handler.DeleteRule)
+		api.GET("/rule/limit", handler.GetRuleLimit)
+		api.PUT("/rule/limit", handler.Audit, handler.UpdateRuleLimit)
+		api.DELETE("/rule/limit", handler.Audit, handler.DeleteRuleLimit)
+		api.GET("/rule/canary", handler.GetRuleCanary)
+		api.PUT("/rule/canary", handler.Audit, handler.UpdateRuleCanary)
+		api.PUT("/rule/canary/promote", handler.Audit, handler.PromoteRuleCanary)
+		api.DELETE("/rule/canary", handler.Audit, handler.DeleteRuleCanary)
+		api.GET("/rule/retention", handler.GetRuleRetention)
+		api.PUT("/rule/retention", handler.Audit, handler.UpdateRuleRetention)
+
+		// 规则测试用例相关api
+		api.GET("/rule/case/page", handler.PageRuleCase)
+		api.POST("/rule/case", handler.Audit, handler.AddRuleCase)
+		api.PUT("/rule/case", handler.Audit, handler.UpdateRuleCase)
+		api.DELETE("/rule/case", handler.Audit, handler.DeleteRuleCase)
+		api.POST("/rule/case/run", handler.Audit, handler.RunRuleCase)
+		api.GET("/rule/case/result/page", handler.PageRuleCaseResult)
 
 		// 脚本相关api
 		api.GET("/script", handler.GetScript)
 		api.GET("/script/page", handler.PageScript)
-		api.POST("/script", handler.AddScript)
-		api.PUT("/script/switch_version", handler.SwitchScriptVersion)
-		api.DELETE("/script", handler.DeleteScript)
+		api.POST("/script", handler.Audit, handler.AddScript)
+		api.PUT("/script/switch_version", handler.Audit, handler.SwitchScriptVersion)
+		api.GET("/script/diff", handler.DiffScript)
+		api.DELETE("/script", handler.Audit, handler.DeleteScript)
+
+		// 版本审核相关api
+		api.GET("/review", handler.GetReview)
+		api.GET("/review/page", handler.PageReview)
+		api.PUT("/review/submit", handler.Audit, handler.SubmitReview)
+		api.PUT("/review/approve", handler.Audit, handler.ApproveReview)
+		api.PUT("/review/reject", handler.Audit, handler.RejectReview)
+		api.PUT("/review/publish", handler.Audit, handler.PublishReview)
 
 		// 密钥管理相关
 		api.GET("/secret", handler.GetSecret)
 		api.GET("/secret/page", handler.PageSecret)
-		api.POST("/secret", handler.AddSecret)
-		api.PUT("/secret", handler.UpdateSecret)
-		api.DELETE("/secret", handler.DeleteSecret)
+		api.POST("/secret", handler.Audit, handler.AddSecret)
+		api.PUT("/secret", handler.Audit, handler.UpdateSecret)
+		api.DELETE("/secret", handler.Audit, handler.DeleteSecret)
 
 		// 异常中断api
 		api.GET("/suspend/page", handler.PageSuspend)
 		api.GET("/suspend", handler.GetSuspend)
-		api.POST("/suspend/recover", handler.SuspendRecover)
-		api.PUT("/suspend", handler.UpdateSuspend)
+		api.POST("/suspend/recover", handler.Audit, handler.SuspendRecover)
+		api.PUT("/suspend", handler.Audit, handler.UpdateSuspend)
+
+		// 等待信号api
+		api.GET("/wait/page", handler.PageWait)
+		api.POST("/signal", handler.Audit, handler.Signal)
 
 		// 执行日志相关api
 		api.GET("/run_log", handler.GetRunLog)
+		api.GET("/run/status", handler.GetRunStatus)
+		api.GET("/notify_log/page", handler.PageNotifyLog)
+
+		// 定时任务相关api
+		api.GET("/schedule/page", handler.PageSchedule)
+		api.POST("/schedule", handler.Audit, handler.AddSchedule)
+		api.DELETE("/schedule", handler.Audit, handler.DeleteSchedule)
+		api.PUT("/schedule/pause", handler.Audit, handler.PauseSchedule)
+		api.PUT("/schedule/resume", handler.Audit, handler.ResumeSchedule)
+		api.POST("/schedule/trigger", handler.Audit, handler.TriggerSchedule)
+
+		// 熔断状态相关api
+		api.GET("/breaker", handler.ListBreaker)
+		api.PUT("/breaker/reset", handler.Audit, handler.ResetBreaker)
+
+		// 组件缓存相关api
+		api.GET("/cache", handler.ListCache)
+		api.DELETE("/cache", handler.Audit, handler.PurgeCache)
+
+		// 审计日志相关api
+		api.GET("/audit_log", handler.GetAuditLog)
+		api.GET("/audit_log/page", handler.PageAuditLog)
 	}
 
 	// 提供给通用的调度入口 http://ps-go/ps/[rule_name]
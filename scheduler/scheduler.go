@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"fmt"
+	"go.uber.org/zap"
+	"ps-go/consts"
+	"ps-go/model"
+	"ps-go/service"
+	"ps-go/tools"
+	"ps-go/tools/cron"
+	"ps-go/tools/lock"
+	"time"
+)
+
+// Init
+//
+//	@Description: 启动定时任务调度，每分钟检查一次需要触发的任务以及需要定时发布的版本，并定时处理等待信号超时的流程
+func Init() {
+	go func() {
+		for range time.Tick(consts.WaitScanInterval) {
+			waitTimeout()
+		}
+	}()
+
+	go func() {
+		for {
+			// 对齐到下一分钟的开始
+			now := time.Now()
+			next := now.Truncate(time.Minute).Add(time.Minute)
+			time.Sleep(next.Sub(now))
+
+			tick(next)
+			go service.PublishDueReview(tools.NewBackgroundContext())
+		}
+	}()
+}
+
+// tick 触发当前时间需要执行的定时任务。
+// 多实例部署时，通过分布式锁保证每个任务在同一时刻只会被一个实例触发，
+// 锁不主动释放，等待过期，防止其他实例在同一时刻重复触发
+func tick(t time.Time) {
+	ctx := tools.NewBackgroundContext()
+
+	schedule := model.Schedule{}
+	list, err := schedule.All(ctx)
+	if err != nil {
+		ctx.Log.Error("定时任务加载失败", zap.Any("err", err))
+		return
+	}
+
+	for index := range list {
+		item := list[index]
+
+		spec, err := cron.Parse(item.Cron)
+		if err != nil {
+			ctx.Log.Error("定时任务cron表达式错误", zap.Any("id", item.ID), zap.Any("err", err))
+			continue
+		}
+
+		if !spec.Match(t) {
+			continue
+		}
+
+		key := fmt.Sprintf("schedule_%v_%v", item.ID, t.Unix())
+		if !lock.NewLockWithDuration(ctx, key, 2*time.Minute).TryAcquire() {
+			continue
+		}
+
+		go service.RunSchedule(tools.NewBackgroundContext(), &item, tools.NewTrx())
+	}
+}
+
+// waitTimeout 恢复等待信号超时的流程，同一个流程由恢复时的分布式锁保证只会处理一次
+func waitTimeout() {
+	ctx := tools.NewBackgroundContext()
+
+	wait := model.WaitLog{}
+	list, err := wait.TimeoutList(ctx, time.Now().Unix(), 100)
+	if err != nil {
+		ctx.Log.Error("等待超时流程加载失败", zap.Any("err", err))
+		return
+	}
+
+	for index := range list {
+		go service.WaitTimeout(tools.NewBackgroundContext(), &list[index])
+	}
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"fmt"
+	json "github.com/json-iterator/go"
+	"github.com/limeschool/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"ps-go/consts"
+	"ps-go/model"
+	"ps-go/types"
+	"strings"
+)
+
+const auditRedacted = "******"
+
+var (
+	// 审计日志中需要脱敏的字段，secret的context为密钥内容，其他字段名包含以下关键字时脱敏
+	auditRedactKeys = []string{"password", "passwd", "token", "authorization", "cookie", "private_key", "api_key", "apikey", "access_key"}
+
+	// 不记录原文的业务数据，包括异常中断的上下文数据、测试用例的模拟数据以及信号的payload
+	auditPayloadKeys = []string{"data", "mocks", "payload"}
+
+	auditActions = map[string]string{
+		http.MethodPost:   "create",
+		http.MethodPut:    "update",
+		http.MethodDelete: "delete",
+	}
+)
+
+func GetAuditLog(ctx *gin.Context, in *types.GetAuditLogRequest) (model.AuditLog, error) {
+	log := model.AuditLog{}
+	return log, log.OneByID(ctx, in.ID)
+}
+
+func PageAuditLog(ctx *gin.Context, in *types.PageAuditLogRequest) ([]model.AuditLog, int64, error) {
+	log := model.AuditLog{}
+	return log.Page(ctx, in.Page, in.Count, in)
+}
+
+// RecordAudit 记录后台管理接口的操作，request、response为本次请求的原始数据，
+// 变更前后的数据由各个操作通过auditBefore、auditAfter设置
+func RecordAudit(ctx *gin.Context, request, response []byte) {
+	path := strings.TrimPrefix(ctx.FullPath(), "/api/v1/")
+	data := map[string]any{}
+	_ = json.Unmarshal(request, &data)
+
+	log := model.AuditLog{
+		Entity:    strings.Split(path, "/")[0],
+		EntityKey: auditEntityKey(data),
+		Action:    auditActions[ctx.Request.Method],
+		Method:    ctx.Request.Method,
+		Path:      ctx.FullPath(),
+		IP:        ctx.ClientIP(),
+		Request:   auditData(data),
+	}
+	// 非json格式的请求数据无法脱敏，不记录原文
+	if log.Request == "" && len(request) != 0 {
+		log.Request = auditRedacted
+	}
+	log.Operator, _ = data["operator"].(string)
+	if id, ok := data["operator_id"].(float64); ok {
+		log.OperatorID = int64(id)
+	}
+
+	if before, ok := ctx.Get(consts.AuditBeforeKey); ok {
+		log.BeforeData = auditData(before)
+	}
+	if after, ok := ctx.Get(consts.AuditAfterKey); ok {
+		log.AfterData = auditData(after)
+	}
+
+	resp := struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}{}
+	_ = json.Unmarshal(response, &resp)
+	log.Code, log.Msg = resp.Code, resp.Msg
+
+	if err := log.Create(ctx); err != nil {
+		ctx.Log.Error("审计日志记录失败", zap.Any("path", log.Path), zap.Any("err", err))
+	}
+}
+
+// auditBefore 设置本次操作变更前的数据
+func auditBefore(ctx *gin.Context, data any) {
+	ctx.Set(consts.AuditBeforeKey, data)
+}
+
+// auditAfter 设置本次操作变更后的数据
+func auditAfter(ctx *gin.Context, data any) {
+	ctx.Set(consts.AuditAfterKey, data)
+}
+
+// auditEntityKey 获取操作的数据标识，依次使用id、name:method、version、trx
+func auditEntityKey(data map[string]any) string {
+	for _, key := range []string{"id", "name", "version", "trx"} {
+		value, ok := data[key]
+		if !ok || value == nil || value == "" {
+			continue
+		}
+		if key == "name" && data["method"] != nil {
+			return fmt.Sprintf("%v:%v", value, strings.ToUpper(fmt.Sprint(data["method"])))
+		}
+		if f, is := value.(float64); is {
+			return fmt.Sprint(int64(f))
+		}
+		return fmt.Sprint(value)
+	}
+	return ""
+}
+
+// auditData 脱敏之后序列化为字符串，数据为空时返回空字符串
+func auditData(data any) string {
+	str, _ := json.MarshalToString(data)
+	var value any
+	if json.UnmarshalFromString(str, &value) != nil || value == nil {
+		return ""
+	}
+	if m, ok := value.(map[string]any); ok {
+		if len(m) == 0 {
+			return ""
+		}
+		for _, key := range auditPayloadKeys {
+			if !isEmpty(m[key]) {
+				m[key] = auditRedacted
+			}
+		}
+	}
+	str, _ = json.MarshalToString(redact(value))
+	return str
+}
+
+// redact 递归脱敏数据中的敏感字段，json格式的字符串（如规则）解析之后同样脱敏
+func redact(data any) any {
+	switch value := data.(type) {
+	case map[string]any:
+		for key, item := range value {
+			if !isEmpty(item) && sensitiveKey(key) {
+				value[key] = auditRedacted
+				continue
+			}
+			value[key] = redact(item)
+		}
+	case []any:
+		for index, item := range value {
+			value[index] = redact(item)
+		}
+	case string:
+		trim := strings.TrimSpace(value)
+		if !strings.HasPrefix(trim, "{") && !strings.HasPrefix(trim, "[") {
+			return data
+		}
+		var nested any
+		if json.UnmarshalFromString(trim, &nested) != nil {
+			return data
+		}
+		str, _ := json.MarshalToString(redact(nested))
+		return str
+	}
+	return data
+}
+
+// sensitiveKey 判断字段是否需要脱敏
+func sensitiveKey(key string) bool {
+	key = strings.ReplaceAll(strings.ToLower(key), "-", "_")
+	if key == "context" {
+		return true
+	}
+	for _, item := range auditRedactKeys {
+		if strings.Contains(key, item) {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmpty(value any) bool {
+	return value == nil || value == ""
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	json "github.com/json-iterator/go"
+	"testing"
+)
+
+func TestSensitiveKey(t *testing.T) {
+	cases := map[string]bool{
+		"password":      true,
+		"db_Password":   true,
+		"X-Api-Key":     true,
+		"Authorization": true,
+		"access_token":  true,
+		"Set-Cookie":    true,
+		"private_key":   true,
+		"context":       true,
+		"name":          false,
+		"key":           false,
+		"contexts":      false,
+	}
+	for key, want := range cases {
+		if got := sensitiveKey(key); got != want {
+			t.Errorf("sensitiveKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestAuditData(t *testing.T) {
+	rule, _ := json.MarshalToString(map[string]any{
+		"components": []any{map[string]any{"header": map[string]any{"Authorization": "Bearer x", "Accept": "json"}}},
+	})
+	data := map[string]any{
+		"name":     "user",
+		"password": "123456",
+		"token":    "",
+		"list":     []any{map[string]any{"api_key": "k", "id": 1}},
+		"rule":     rule,
+		"text":     "{not json",
+		"data":     map[string]any{"user": "admin"},
+		"mocks":    nil,
+		"payload":  map[string]any{"approver": "admin"},
+	}
+
+	got := map[string]any{}
+	if err := json.UnmarshalFromString(auditData(data), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	for key, want := range map[string]any{
+		"name":     "user",
+		"password": auditRedacted,
+		"token":    "", // 空值不需要脱敏，保留原值便于排查
+		"text":     "{not json",
+		"data":     auditRedacted,
+		"mocks":    nil,
+		"payload":  auditRedacted,
+	} {
+		if got[key] != want {
+			t.Errorf("%v = %v, want %v", key, got[key], want)
+		}
+	}
+	if item := got["list"].([]any)[0].(map[string]any); item["api_key"] != auditRedacted || item["id"] != float64(1) {
+		t.Errorf("list = %v", item)
+	}
+
+	// json格式的字符串解析之后脱敏
+	nested := map[string]any{}
+	if err := json.UnmarshalFromString(got["rule"].(string), &nested); err != nil {
+		t.Fatal(err)
+	}
+	header := nested["components"].([]any)[0].(map[string]any)["header"].(map[string]any)
+	if header["Authorization"] != auditRedacted || header["Accept"] != "json" {
+		t.Errorf("rule header = %v", header)
+	}
+
+	// 原始数据不被修改
+	if data["password"] != "123456" {
+		t.Error("source data redacted")
+	}
+}
+
+func TestAuditDataEmpty(t *testing.T) {
+	for _, data := range []any{nil, map[string]any{}, struct{}{}} {
+		if got := auditData(data); got != "" {
+			t.Errorf("auditData(%v) = %q, want empty", data, got)
+		}
+	}
+	if got := auditData([]any{map[string]any{"secret_token": "x"}}); got != `[{"secret_token":"******"}]` {
+		t.Errorf("auditData(list) = %v", got)
+	}
+}
+
+func TestAuditEntityKey(t *testing.T) {
+	cases := []struct {
+		data map[string]any
+		want string
+	}{
+		{map[string]any{"id": float64(12), "name": "user"}, "12"},
+		{map[string]any{"id": float64(0), "name": "user", "method": "get"}, "0"},
+		{map[string]any{"id": "", "name": "user", "method": "get"}, "user:GET"},
+		{map[string]any{"name": "user.js"}, "user.js"},
+		{map[string]any{"version": "v1", "trx": "t1"}, "v1"},
+		{map[string]any{"trx": "t1"}, "t1"},
+		{map[string]any{}, ""},
+	}
+	for _, c := range cases {
+		if got := auditEntityKey(c.data); got != c.want {
+			t.Errorf("auditEntityKey(%v) = %q, want %q", c.data, got, c.want)
+		}
+	}
+}
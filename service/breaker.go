@@ -0,0 +1,35 @@
+package service
+
+import (
+	"github.com/limeschool/gin"
+	"ps-go/errors"
+	"ps-go/tools/breaker"
+	"ps-go/types"
+	"strings"
+)
+
+func ListBreaker(ctx *gin.Context, in *types.ListBreakerRequest) ([]*breaker.State, error) {
+	states, err := breaker.List(ctx)
+	if err != nil {
+		return nil, errors.NewF("获取熔断状态失败:%v", err.Error())
+	}
+
+	list := make([]*breaker.State, 0)
+	for _, item := range states {
+		if in.Host != "" && !strings.Contains(item.Host, in.Host) {
+			continue
+		}
+		if in.State != "" && item.State != in.State {
+			continue
+		}
+		list = append(list, item)
+	}
+	return list, nil
+}
+
+func ResetBreaker(ctx *gin.Context, in *types.ResetBreakerRequest) error {
+	if err := breaker.Reset(ctx, in.Host); err != nil {
+		return errors.NewF("重置熔断状态失败:%v", err.Error())
+	}
+	return nil
+}
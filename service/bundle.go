@@ -0,0 +1,264 @@
+package service
+
+import (
+	"fmt"
+	json "github.com/json-iterator/go"
+	"github.com/limeschool/gin"
+	"github.com/robertkrimen/otto/parser"
+	"go.uber.org/zap"
+	"ps-go/consts"
+	"ps-go/engine"
+	"ps-go/errors"
+	"ps-go/model"
+	"ps-go/types"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ExportRule 导出规则，以及规则引用的脚本的启用中版本、引用的密钥名
+func ExportRule(ctx *gin.Context, in *types.ExportRuleRequest) (*types.Bundle, error) {
+	info, err := GetRule(ctx, &types.GetRuleRequest{ID: in.ID, Name: in.Name, Method: in.Method, Version: in.Version})
+	if err != nil {
+		return nil, err
+	}
+
+	rule := engine.Rule{}
+	if err = json.UnmarshalFromString(info.Rule, &rule); err != nil {
+		return nil, errors.NewF("规则格式错误：%v", err.Error())
+	}
+	names, secrets := engine.RuleReferences(&rule)
+
+	bundle := &types.Bundle{
+		Format:     consts.BundleFormat,
+		ExportedAt: time.Now().Unix(),
+		Rule:       types.BundleRule{Name: info.Name, Method: info.Method, Version: info.Version, Rule: info.Rule},
+		Scripts:    make([]types.BundleScript, 0, len(names)),
+	}
+	for _, name := range names {
+		script := model.Script{}
+		if err = script.OneByName(ctx, name); err != nil {
+			return nil, errors.NewF("规则引用的脚本%v不存在", name)
+		}
+		bundle.Scripts = append(bundle.Scripts, types.BundleScript{Name: name, Version: script.Version, Script: script.Script})
+		secrets = append(secrets, engine.ScriptSecrets(script.Script)...)
+	}
+	bundle.Secrets = uniqueStrings(secrets)
+	return bundle, nil
+}
+
+// ImportRule 导入规则，校验不通过或者存在缺失的密钥时不写入任何数据，只返回校验结果。
+// 导入的版本为草稿状态，需要经过审核之后发布，脚本内容与启用中的版本一致时不重复创建。
+// activate为true时，导入的版本以导入人员的身份审核通过并立即发布。导入失败时删除已经创建的版本
+func ImportRule(ctx *gin.Context, in *types.ImportRuleRequest) (*types.ImportRuleResponse, error) {
+	bundle := in.Bundle
+	if bundle.Format != consts.BundleFormat {
+		return nil, errors.NewF("不支持的导出包格式%v", bundle.Format)
+	}
+
+	rule := engine.Rule{}
+	if err := json.UnmarshalFromString(bundle.Rule.Rule, &rule); err != nil {
+		return nil, errors.NewF("规则格式错误：%v", err.Error())
+	}
+	names, secrets := engine.RuleReferences(&rule)
+
+	scripts := map[string]string{}
+	for _, item := range bundle.Scripts {
+		if _, ok := scripts[item.Name]; ok {
+			return nil, errors.NewF("导出包中的脚本%v重复", item.Name)
+		}
+		if _, err := parser.ParseFile(nil, item.Name, item.Script, 0); err != nil {
+			return nil, errors.NewF("脚本%v语法错误：%v", item.Name, err.Error())
+		}
+		scripts[item.Name] = item.Script
+		secrets = append(secrets, engine.ScriptSecrets(item.Script)...)
+	}
+	for _, name := range names {
+		if _, ok := scripts[name]; !ok {
+			return nil, errors.NewF("导出包中缺少规则引用的脚本%v", name)
+		}
+	}
+
+	resp := &types.ImportRuleResponse{
+		MissingSecrets: make([]string, 0),
+		Diagnostics:    engine.Get().LintRuleWithScripts(ctx, bundle.Rule.Rule, scripts),
+		Rule:           types.BundleRule{Name: bundle.Rule.Name, Method: bundle.Rule.Method},
+		Scripts:        make([]types.BundleScript, 0, len(bundle.Scripts)),
+	}
+	for _, name := range uniqueStrings(append(secrets, bundle.Secrets...)) {
+		secret := model.Secret{}
+		if secret.OneByName(ctx, name) != nil {
+			resp.MissingSecrets = append(resp.MissingSecrets, name)
+		}
+	}
+	if len(resp.MissingSecrets) != 0 || engine.LintError(resp.Diagnostics) != nil {
+		return resp, nil
+	}
+
+	// 先创建脚本，再创建引用脚本的规则
+	imp := &importer{ctx: ctx, in: in}
+	for _, item := range bundle.Scripts {
+		version, err := imp.script(item)
+		if err != nil {
+			imp.rollback()
+			return nil, err
+		}
+		resp.Scripts = append(resp.Scripts, types.BundleScript{Name: item.Name, Version: version})
+	}
+
+	version, err := imp.rule(bundle.Rule)
+	if err != nil {
+		imp.rollback()
+		return nil, err
+	}
+	resp.Imported = true
+	resp.Rule.Method = strings.ToUpper(bundle.Rule.Method)
+	resp.Rule.Version = version
+
+	if in.Activate {
+		if err = imp.activate(); err != nil {
+			return nil, errors.NewF("规则已导入为版本%v，启用失败：%v", version, err.Error())
+		}
+	}
+	return resp, nil
+}
+
+// importer 导入过程中创建的版本，导入失败时按照创建的倒序删除，防止遗留不完整的版本
+type importer struct {
+	ctx     *gin.Context
+	in      *types.ImportRuleRequest
+	reviews []*model.VersionReview
+	undo    []func() error
+}
+
+// script 导入单个脚本，返回导入之后的版本
+func (i *importer) script(item types.BundleScript) (string, error) {
+	current := model.Script{}
+	if current.OneByName(i.ctx, item.Name) == nil && current.Script == item.Script {
+		return current.Version, nil
+	}
+
+	script := model.Script{
+		Name:       item.Name,
+		Script:     item.Script,
+		Operator:   i.in.Operator,
+		OperatorID: i.in.OperatorID,
+	}
+	if err := script.Create(i.ctx); err != nil {
+		return "", err
+	}
+	undo := func() error {
+		return (&model.Script{DeleteModel: gin.DeleteModel{ID: script.ID}}).Revoke(i.ctx)
+	}
+
+	review := &model.VersionReview{
+		Kind:     model.ReviewKindScript,
+		Name:     script.Name,
+		Version:  script.Version,
+		Author:   i.in.Operator,
+		AuthorID: i.in.OperatorID,
+	}
+	if err := createReview(i.ctx, review, *script.Status, importComment(item.Version), undo); err != nil {
+		return "", err
+	}
+	i.created(review, undo)
+	return script.Version, nil
+}
+
+// rule 导入规则，返回导入之后的版本
+func (i *importer) rule(item types.BundleRule) (string, error) {
+	rule := model.Rule{
+		Name:       item.Name,
+		Method:     item.Method,
+		Rule:       item.Rule,
+		Operator:   i.in.Operator,
+		OperatorID: i.in.OperatorID,
+	}
+	if err := rule.Create(i.ctx); err != nil {
+		return "", err
+	}
+	undo := func() error {
+		return (&model.Rule{DeleteModel: gin.DeleteModel{ID: rule.ID}}).Revoke(i.ctx)
+	}
+
+	review := &model.VersionReview{
+		Kind:     model.ReviewKindRule,
+		Name:     rule.Name,
+		Method:   rule.Method,
+		Version:  rule.Version,
+		Author:   i.in.Operator,
+		AuthorID: i.in.OperatorID,
+	}
+	if err := createReview(i.ctx, review, *rule.Status, importComment(item.Version), undo); err != nil {
+		return "", err
+	}
+	i.created(review, undo)
+	return rule.Version, nil
+}
+
+// created 记录创建的版本以及审核状态，回滚时一并删除
+func (i *importer) created(review *model.VersionReview, undo func() error) {
+	i.reviews = append(i.reviews, review)
+	i.undo = append(i.undo, func() error {
+		if err := review.DeleteByVersion(i.ctx); err != nil {
+			return err
+		}
+		return undo()
+	})
+}
+
+// rollback 按照创建的倒序删除已经创建的版本
+func (i *importer) rollback() {
+	for index := len(i.undo) - 1; index >= 0; index-- {
+		if err := i.undo[index](); err != nil {
+			i.ctx.Log.Error("导入的版本删除失败", zap.Any("version", i.reviews[index].Version), zap.Any("err", err))
+		}
+	}
+}
+
+// activate 以导入人员的身份审核通过导入的草稿版本，并按照脚本、规则的顺序发布，首个版本已经直接启用
+func (i *importer) activate() error {
+	for _, review := range i.reviews {
+		if review.State != model.ReviewDraft {
+			continue
+		}
+		err := review.Transit(i.ctx, model.ReviewDraft, map[string]any{
+			"reviewer":    i.in.Operator,
+			"reviewer_id": i.in.OperatorID,
+		}, &model.VersionReviewLog{
+			ToState:    model.ReviewApproved,
+			Comment:    "导入时启用，跳过审核",
+			Operator:   i.in.Operator,
+			OperatorID: i.in.OperatorID,
+		})
+		if err != nil {
+			return err
+		}
+		if err = publishVersion(i.ctx, review, i.in.Force, "导入时启用", i.in.Operator, i.in.OperatorID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importComment 导入版本的审核日志，记录导出时的原版本
+func importComment(version string) string {
+	if version == "" {
+		return "导入版本"
+	}
+	return fmt.Sprintf("导入版本，原版本为%v", version)
+}
+
+// uniqueStrings 去除空值以及重复值并排序
+func uniqueStrings(list []string) []string {
+	m := map[string]bool{}
+	res := make([]string, 0, len(list))
+	for _, item := range list {
+		if item != "" && !m[item] {
+			m[item] = true
+			res = append(res, item)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"github.com/limeschool/gin"
+	"go.uber.org/zap"
+	"ps-go/consts"
+	"ps-go/errors"
+	"ps-go/model"
+	"ps-go/types"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestImportRuleValidate(t *testing.T) {
+	rule := `{"components":[[{"name":"a","type":"script","url":"a.js"}]]}`
+	cases := []struct {
+		name   string
+		bundle types.Bundle
+		err    string
+	}{
+		{"格式版本", types.Bundle{Format: "ps.bundle.v0", Rule: types.BundleRule{Rule: rule}}, "不支持的导出包格式"},
+		{"规则格式", types.Bundle{Format: consts.BundleFormat, Rule: types.BundleRule{Rule: "{"}}, "规则格式错误"},
+		{"脚本重复", types.Bundle{Format: consts.BundleFormat, Rule: types.BundleRule{Rule: rule}, Scripts: []types.BundleScript{
+			{Name: "a.js", Script: "var a = 1"}, {Name: "a.js", Script: "var a = 2"},
+		}}, "重复"},
+		{"脚本语法", types.Bundle{Format: consts.BundleFormat, Rule: types.BundleRule{Rule: rule}, Scripts: []types.BundleScript{
+			{Name: "a.js", Script: "function ("},
+		}}, "语法错误"},
+		{"缺少脚本", types.Bundle{Format: consts.BundleFormat, Rule: types.BundleRule{Rule: rule}, Scripts: []types.BundleScript{
+			{Name: "b.js", Script: "var b = 1"},
+		}}, "缺少规则引用的脚本a.js"},
+	}
+
+	// 导出包本身不完整时直接返回错误，不会写入任何数据
+	for _, c := range cases {
+		resp, err := ImportRule(nil, &types.ImportRuleRequest{Bundle: c.bundle})
+		if resp != nil || err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%v: ImportRule = %v, %v, want %v", c.name, resp, err, c.err)
+		}
+	}
+}
+
+func TestImporterRollback(t *testing.T) {
+	imp := &importer{ctx: &gin.Context{Log: zap.NewNop()}}
+
+	var order []string
+	for _, version := range []string{"script1", "script2", "rule"} {
+		version := version
+		imp.reviews = append(imp.reviews, &model.VersionReview{Version: version})
+		imp.undo = append(imp.undo, func() error {
+			order = append(order, version)
+			if version == "script2" {
+				return errors.New("delete failed")
+			}
+			return nil
+		})
+	}
+
+	// 按照创建的倒序删除，单个版本删除失败时继续删除其他版本
+	imp.rollback()
+	if want := []string{"rule", "script2", "script1"}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("rollback order = %v, want %v", order, want)
+	}
+}
+
+func TestImportHelpers(t *testing.T) {
+	if got := uniqueStrings([]string{"b", "", "a", "b"}); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Fatalf("uniqueStrings = %v", got)
+	}
+	if got := importComment(""); got != "导入版本" {
+		t.Fatalf("importComment = %v", got)
+	}
+	if got := importComment("v1"); !strings.Contains(got, "v1") {
+		t.Fatalf("importComment = %v", got)
+	}
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"github.com/limeschool/gin"
+	"ps-go/engine"
+	"ps-go/errors"
+	"ps-go/types"
+)
+
+func ListCache(ctx *gin.Context, in *types.ListCacheRequest) ([]engine.CacheEntry, error) {
+	prefix := engine.CachePrefix(engine.RuleKey(in.Method, in.Name), in.Component)
+	list, err := engine.ListCache(ctx, prefix)
+	if err != nil {
+		return nil, errors.NewF("查询缓存失败:%v", err.Error())
+	}
+	return list, nil
+}
+
+// PurgeCache 清理流程或者指定组件的缓存，返回清理的数量
+func PurgeCache(ctx *gin.Context, in *types.PurgeCacheRequest) (int, error) {
+	prefix := engine.CachePrefix(engine.RuleKey(in.Method, in.Name), in.Component)
+	count, err := engine.PurgeCache(ctx, prefix)
+	if err != nil {
+		return count, errors.NewF("清理缓存失败:%v", err.Error())
+	}
+	return count, nil
+}
@@ -0,0 +1,12 @@
+package service
+
+import (
+	"github.com/limeschool/gin"
+	"ps-go/model"
+	"ps-go/types"
+)
+
+func PageNotifyLog(ctx *gin.Context, in *types.PageNotifyLogRequest) ([]model.NotifyLog, int64, error) {
+	log := model.NotifyLog{}
+	return log.Page(ctx, in.Page, in.Count, in)
+}
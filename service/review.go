@@ -0,0 +1,289 @@
+package service
+
+import (
+	"fmt"
+	"github.com/limeschool/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"ps-go/errors"
+	"ps-go/model"
+	"ps-go/tools"
+	"ps-go/tools/lock"
+	"ps-go/types"
+	"time"
+)
+
+const reviewSystemOperator = "system"
+
+// GetReview 查询版本的审核状态以及变更记录
+func GetReview(ctx *gin.Context, in *types.GetReviewRequest) (model.VersionReview, []model.VersionReviewLog, error) {
+	review := model.VersionReview{}
+	if err := review.OneByVersion(ctx, in.Kind, in.Version); err != nil {
+		return review, nil, err
+	}
+
+	logs, err := (&model.VersionReviewLog{}).AllByVersion(ctx, in.Kind, in.Version)
+	return review, logs, err
+}
+
+func PageReview(ctx *gin.Context, in *types.PageReviewRequest) ([]model.VersionReview, int64, error) {
+	review := model.VersionReview{}
+	return review.Page(ctx, in.Page, in.Count, in)
+}
+
+// SubmitReview 提交草稿版本，等待审核。没有审核记录的历史版本提交时先记录为草稿，提交人员作为版本作者
+func SubmitReview(ctx *gin.Context, in *types.ReviewRequest) error {
+	review := model.VersionReview{}
+	if err := review.OneByVersion(ctx, in.Kind, in.Version); err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if review, err = draftReview(ctx, in); err != nil {
+			return err
+		}
+	}
+	if review.State != model.ReviewDraft {
+		return errors.NewF("只能提交草稿状态的版本，当前状态为%v", review.State)
+	}
+
+	return review.Transit(ctx, model.ReviewDraft, map[string]any{}, &model.VersionReviewLog{
+		ToState:    model.ReviewSubmitted,
+		Comment:    in.Comment,
+		Operator:   in.Operator,
+		OperatorID: in.OperatorID,
+	})
+}
+
+// draftReview 为没有审核记录的版本创建草稿状态
+func draftReview(ctx *gin.Context, in *types.ReviewRequest) (model.VersionReview, error) {
+	review := model.VersionReview{
+		Kind:     in.Kind,
+		Version:  in.Version,
+		Author:   in.Operator,
+		AuthorID: in.OperatorID,
+	}
+	if in.Kind == model.ReviewKindScript {
+		script := model.Script{}
+		if err := script.OneByVersion(ctx, in.Version); err != nil {
+			return review, errors.NewF("不存在脚本版本：%v", in.Version)
+		}
+		review.Name = script.Name
+	} else {
+		rule := model.Rule{}
+		if err := rule.OneByVersion(ctx, in.Version); err != nil {
+			return review, errors.NewF("不存在规则版本：%v", in.Version)
+		}
+		review.Name, review.Method = rule.Name, rule.Method
+	}
+
+	return review, review.Create(ctx, "历史版本提交审核")
+}
+
+// ApproveReview 审核通过，审核人员不能是版本的作者
+func ApproveReview(ctx *gin.Context, in *types.ReviewRequest) error {
+	review, err := reviewing(ctx, in)
+	if err != nil {
+		return err
+	}
+
+	return review.Transit(ctx, model.ReviewSubmitted, map[string]any{
+		"reviewer":    in.Operator,
+		"reviewer_id": in.OperatorID,
+	}, &model.VersionReviewLog{
+		ToState:    model.ReviewApproved,
+		Comment:    in.Comment,
+		Operator:   in.Operator,
+		OperatorID: in.OperatorID,
+	})
+}
+
+// RejectReview 审核不通过，版本退回草稿状态，需要填写原因
+func RejectReview(ctx *gin.Context, in *types.ReviewRequest) error {
+	if in.Comment == "" {
+		return errors.New("审核不通过时需要填写原因")
+	}
+
+	review, err := reviewing(ctx, in)
+	if err != nil {
+		return err
+	}
+
+	return review.Transit(ctx, model.ReviewSubmitted, map[string]any{
+		"reviewer":    in.Operator,
+		"reviewer_id": in.OperatorID,
+	}, &model.VersionReviewLog{
+		ToState:    model.ReviewDraft,
+		Comment:    in.Comment,
+		Operator:   in.Operator,
+		OperatorID: in.OperatorID,
+	})
+}
+
+// reviewing 获取等待审核的版本，并校验审核人员
+func reviewing(ctx *gin.Context, in *types.ReviewRequest) (*model.VersionReview, error) {
+	review := model.VersionReview{}
+	if err := review.OneByVersion(ctx, in.Kind, in.Version); err != nil {
+		return nil, err
+	}
+	if review.State != model.ReviewSubmitted {
+		return nil, errors.NewF("只能审核已提交的版本，当前状态为%v", review.State)
+	}
+	if review.AuthorID == in.OperatorID {
+		return nil, errors.New("审核人员不能是版本的作者")
+	}
+	return &review, nil
+}
+
+// PublishReview 发布审核通过的版本，publish_at大于当前时间时在指定时间发布
+func PublishReview(ctx *gin.Context, in *types.PublishReviewRequest) error {
+	review := model.VersionReview{}
+	if err := review.OneByVersion(ctx, in.Kind, in.Version); err != nil {
+		return err
+	}
+	if review.State != model.ReviewApproved {
+		return errors.NewF("只能发布审核通过的版本，当前状态为%v", review.State)
+	}
+
+	if in.PublishAt > time.Now().Unix() {
+		comment := fmt.Sprintf("定时发布：%v", time.Unix(in.PublishAt, 0).Format("2006-01-02 15:04:05"))
+		if in.Comment != "" {
+			comment = fmt.Sprintf("%v，%v", comment, in.Comment)
+		}
+		return review.Transit(ctx, model.ReviewApproved, map[string]any{"publish_at": in.PublishAt}, &model.VersionReviewLog{
+			ToState:    model.ReviewApproved,
+			Comment:    comment,
+			Operator:   in.Operator,
+			OperatorID: in.OperatorID,
+		})
+	}
+
+	return publishVersion(ctx, &review, in.Force, in.Comment, in.Operator, in.OperatorID)
+}
+
+// PublishDueReview 发布已经到达定时发布时间的版本，发布失败时取消定时发布并记录原因。
+// 多实例部署时，通过分布式锁保证每个版本只会被一个实例发布
+func PublishDueReview(ctx *gin.Context) {
+	review := model.VersionReview{}
+	list, err := review.DueList(ctx, time.Now().Unix(), 100)
+	if err != nil {
+		ctx.Log.Error("定时发布版本加载失败", zap.Any("err", err))
+		return
+	}
+
+	for index := range list {
+		item := list[index]
+		key := fmt.Sprintf("review_publish_%v_%v", item.ID, item.PublishAt)
+		if !lock.NewLockWithDuration(ctx, key, 2*time.Minute).TryAcquire() {
+			continue
+		}
+
+		if err = publishVersion(ctx, &item, false, "定时发布", reviewSystemOperator, 0); err == nil {
+			continue
+		}
+
+		ctx.Log.Error("版本定时发布失败", zap.Any("version", item.Version), zap.Any("err", err))
+		_ = item.Transit(ctx, model.ReviewApproved, map[string]any{"publish_at": 0}, &model.VersionReviewLog{
+			ToState:  model.ReviewApproved,
+			Comment:  fmt.Sprintf("定时发布失败：%v", err.Error()),
+			Operator: reviewSystemOperator,
+		})
+	}
+}
+
+// publishVersion 切换到指定版本，切换成功之后更新审核状态
+func publishVersion(ctx *gin.Context, review *model.VersionReview, force bool, comment, operator string, operatorID int64) error {
+	if review.Kind == model.ReviewKindScript {
+		script := model.Script{}
+		if err := script.OneByVersion(ctx, review.Version); err != nil {
+			return errors.NewF("不存在脚本版本：%v", review.Version)
+		}
+		return SwitchVersionScript(ctx, &types.SwitchVersionScriptRequest{
+			ID:         script.ID,
+			Comment:    comment,
+			Operator:   operator,
+			OperatorID: operatorID,
+		})
+	}
+
+	rule := model.Rule{}
+	if err := rule.OneByVersion(ctx, review.Version); err != nil {
+		return errors.NewF("不存在规则版本：%v", review.Version)
+	}
+	return SwitchVersionRule(ctx, &types.SwitchVersionRuleRequest{
+		ID:         rule.ID,
+		Force:      force,
+		Comment:    comment,
+		Operator:   operator,
+		OperatorID: operatorID,
+	})
+}
+
+// createReview 版本创建之后记录审核状态，记录失败时删除该版本，防止未审核的版本被发布。
+// 首个版本创建之后直接启用，记录为已发布状态，记录失败时视为审核流程上线之前的版本，不删除
+func createReview(ctx *gin.Context, review *model.VersionReview, active bool, comment string, rollback func() error) error {
+	if active {
+		review.State = model.ReviewPublished
+		comment = "首个版本直接启用"
+	}
+
+	if err := review.Create(ctx, comment); err != nil {
+		if active {
+			ctx.Log.Error("版本审核状态记录失败", zap.Any("version", review.Version), zap.Any("err", err))
+			return nil
+		}
+		if e := rollback(); e != nil {
+			ctx.Log.Error("版本删除失败", zap.Any("version", review.Version), zap.Any("err", e))
+		}
+		return err
+	}
+	return nil
+}
+
+// checkReview 校验版本是否可以发布，审核通过以及已经发布过的版本（回滚）可以发布。
+// 不存在审核状态时只允许发布第一个版本：第一个版本创建时直接启用，审核状态记录失败时不会删除该版本
+func checkReview(ctx *gin.Context, kind, version string, isFirst func() (bool, error)) error {
+	review := model.VersionReview{}
+	if err := review.OneByVersion(ctx, kind, version); err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		first, err := isFirst()
+		if err != nil {
+			return err
+		}
+		if !first {
+			return errors.NewF("版本%v不存在审核记录，需要审核通过之后才能发布", version)
+		}
+		return nil
+	}
+	if !tools.InList([]string{model.ReviewApproved, model.ReviewPublished}, review.State) {
+		return errors.NewF("版本%v未审核通过，当前状态为%v", version, review.State)
+	}
+	return nil
+}
+
+// finishReview 版本切换成功之后更新审核状态为已发布，重新发布的版本只记录变更日志
+func finishReview(ctx *gin.Context, kind, version, comment, operator string, operatorID int64) {
+	review := model.VersionReview{}
+	if review.OneByVersion(ctx, kind, version) != nil {
+		return
+	}
+
+	log := &model.VersionReviewLog{
+		ToState:    model.ReviewPublished,
+		Comment:    comment,
+		Operator:   operator,
+		OperatorID: operatorID,
+	}
+
+	var err error
+	if review.State == model.ReviewPublished {
+		log.Kind, log.Version, log.FromState = kind, version, review.State
+		err = log.Create(ctx)
+	} else {
+		err = review.Transit(ctx, review.State, map[string]any{"publish_at": 0}, log)
+	}
+	if err != nil {
+		ctx.Log.Error("版本审核状态更新失败", zap.Any("version", version), zap.Any("err", err))
+	}
+}
@@ -2,10 +2,16 @@ package service
 
 import (
 	"github.com/jinzhu/copier"
+	json "github.com/json-iterator/go"
 	"github.com/limeschool/gin"
+	"ps-go/consts"
+	"ps-go/engine"
 	"ps-go/errors"
 	"ps-go/model"
+	"ps-go/tools"
+	"ps-go/tools/pool"
 	"ps-go/types"
+	"time"
 )
 
 func GetRule(ctx *gin.Context, in *types.GetRuleRequest) (model.Rule, error) {
@@ -32,25 +38,192 @@ func PageRule(ctx *gin.Context, in *types.PageRuleRequest) ([]model.Rule, int64,
 }
 
 func AddRule(ctx *gin.Context, in *types.AddRuleRequest) error {
+	if err := engine.LintError(engine.Get().LintRule(ctx, in.Rule)); err != nil {
+		return err
+	}
+
 	rule := model.Rule{}
 	if copier.Copy(&rule, in) != nil {
 		return errors.AssignError
 	}
-	return rule.Create(ctx)
+	if err := rule.Create(ctx); err != nil {
+		return err
+	}
+	auditAfter(ctx, rule)
+
+	return createReview(ctx, &model.VersionReview{
+		Kind:     model.ReviewKindRule,
+		Name:     rule.Name,
+		Method:   rule.Method,
+		Version:  rule.Version,
+		Author:   in.Operator,
+		AuthorID: in.OperatorID,
+	}, *rule.Status, "创建版本", func() error {
+		return (&model.Rule{DeleteModel: gin.DeleteModel{ID: rule.ID}}).DeleteByID(ctx)
+	})
 }
 
+// SwitchVersionRule 发布规则版本，目标版本需要审核通过，存在未通过的测试用例时拒绝切换，force为true时跳过测试用例
 func SwitchVersionRule(ctx *gin.Context, in *types.SwitchVersionRuleRequest) error {
+	info := model.Rule{}
+	if err := info.OneByID(ctx, in.ID); err != nil {
+		return err
+	}
+	if *info.Status {
+		return nil
+	}
+	if err := checkReview(ctx, model.ReviewKindRule, info.Version, func() (bool, error) {
+		return info.IsFirstVersion(ctx)
+	}); err != nil {
+		return err
+	}
+
+	if !in.Force {
+		if err := checkRuleCases(ctx, in.ID); err != nil {
+			return err
+		}
+	}
+
+	// 记录切换前启用中的版本
+	active := model.Rule{}
+	if active.OneByNameMethod(ctx, info.Name, info.Method) == nil {
+		auditBefore(ctx, gin.H{"name": active.Name, "method": active.Method, "version": active.Version})
+	}
+
 	rule := model.Rule{}
 	if copier.Copy(&rule, in) != nil {
 		return errors.AssignError
 	}
-	return rule.SwitchVersion(ctx)
+	if err := rule.SwitchVersion(ctx); err != nil {
+		return err
+	}
+	auditAfter(ctx, gin.H{"name": info.Name, "method": info.Method, "version": info.Version})
+
+	finishReview(ctx, model.ReviewKindRule, info.Version, in.Comment, in.Operator, in.OperatorID)
+	return nil
 }
 
 func DeleteRule(ctx *gin.Context, in *types.DeleteRuleRequest) error {
 	rule := model.Rule{}
+	if err := rule.OneByID(ctx, in.ID); err != nil {
+		return err
+	}
+
+	// 灰度中的版本需要先中止灰度
+	rc := model.RuleCanary{}
+	if rc.OneByNameMethod(ctx, rule.Name, rule.Method) == nil && rc.Version == rule.Version {
+		return errors.New("灰度中的版本不允许删除")
+	}
+
+	auditBefore(ctx, rule)
+
+	rule = model.Rule{}
 	if copier.Copy(&rule, in) != nil {
 		return errors.AssignError
 	}
 	return rule.DeleteByID(ctx)
 }
+
+// DiffRule 对比同一个规则的两个版本，返回组件、请求参数、返回数据等的差异
+func DiffRule(ctx *gin.Context, in *types.DiffRuleRequest) (*engine.RuleDiff, error) {
+	from, to := model.Rule{}, model.Rule{}
+	if err := from.OneByVersion(ctx, in.From); err != nil {
+		return nil, err
+	}
+	if err := to.OneByVersion(ctx, in.To); err != nil {
+		return nil, err
+	}
+	if from.Name != to.Name || from.Method != to.Method {
+		return nil, errors.New("只能对比同一个规则的不同版本")
+	}
+
+	fromRule, toRule := engine.Rule{}, engine.Rule{}
+	if err := json.UnmarshalFromString(from.Rule, &fromRule); err != nil {
+		return nil, errors.NewF("版本%v的规则格式错误：%v", from.Version, err.Error())
+	}
+	if err := json.UnmarshalFromString(to.Rule, &toRule); err != nil {
+		return nil, errors.NewF("版本%v的规则格式错误：%v", to.Version, err.Error())
+	}
+	return engine.DiffRule(&fromRule, &toRule), nil
+}
+
+// ValidateRule 校验规则，返回发现的全部问题，以及是否可以保存
+func ValidateRule(ctx *gin.Context, in *types.ValidateRuleRequest) ([]engine.Diagnostic, bool) {
+	list := engine.Get().LintRule(ctx, in.Rule)
+	return list, engine.LintError(list) == nil
+}
+
+// DryRunRule 试运行规则，api组件使用传入的模拟数据作为返回，不写入执行日志、挂起任务等记录。
+// 返回流程的最终返回数据以及完整的执行日志
+func DryRunRule(ctx *gin.Context, in *types.DryRunRuleRequest) (any, any, error) {
+	info := model.Rule{Name: in.Name, Method: in.Method, Rule: in.Rule}
+	if in.Rule == "" {
+		var err error
+		if in.Version != "" {
+			err = info.OneByVersion(ctx, in.Version)
+		} else {
+			err = info.OneByID(ctx, in.ID)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := engine.LintError(engine.Get().LintRule(ctx, info.Rule)); err != nil {
+		return nil, nil, err
+	}
+
+	res, log, err := dryRun(ctx, &info, in.Request, in.Mocks)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res.Response, log, nil
+}
+
+// dryRun 同步试运行规则，等待流程执行完成之后返回执行结果以及完整的执行日志
+func dryRun(ctx *gin.Context, info *model.Rule, in types.DryRunRequest, mocks map[string]any) (*engine.AssertResult, any, error) {
+	startTime := time.Now()
+	ctx.Set(consts.ProcessScheduleTrx, tools.NewTrx())
+
+	method := info.Method
+	if method == "" {
+		method = "POST"
+	}
+
+	rule := engine.Rule{Version: info.Version}
+	if err := json.UnmarshalFromString(info.Rule, &rule); err != nil {
+		return nil, nil, errors.NewF("规则格式错误：%v", err.Error())
+	}
+
+	// 校验参数
+	eg := engine.Get()
+	requestInfo, err := eg.NewValidate(rule.Request).BindRequest(in.Query, in.Header, in.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	runStore := eg.NewRunStore()
+	runStore.SetData("request", requestInfo)
+
+	runner := eg.NewRunner(ctx, &rule, runStore)
+	runner.SetMethodAndPath(method, info.Name)
+	runner.SetDryRun(mocks)
+	defer runner.Release()
+
+	runner.NewLogger()
+	runner.SetRequestLog(startTime, requestInfo)
+
+	// 同步执行，等待流程执行完成之后再获取完整的执行日志
+	_ = pool.Get().Invoke(runner)
+	go runner.WaitError()
+	runner.WaitResponse()
+	runner.Wait()
+
+	res := &engine.AssertResult{Status: runner.Status(), Store: runStore}
+	if runner.ResponseType() == consts.RespXml {
+		res.Response = runner.ResponseXml()
+	} else {
+		res.Response = runner.Response()
+	}
+	return res, runner.Log(), nil
+}
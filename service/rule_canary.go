@@ -0,0 +1,99 @@
+package service
+
+import (
+	"github.com/jinzhu/copier"
+	json "github.com/json-iterator/go"
+	"github.com/limeschool/gin"
+	"ps-go/engine"
+	"ps-go/errors"
+	"ps-go/model"
+	"ps-go/types"
+	"strings"
+)
+
+// GetRuleCanary 获取规则的灰度配置
+func GetRuleCanary(ctx *gin.Context, in *types.GetRuleCanaryRequest) (model.RuleCanary, error) {
+	rc := model.RuleCanary{}
+	return rc, rc.OneByNameMethod(ctx, in.Name, in.Method)
+}
+
+// UpdateRuleCanary 设置规则的灰度版本，灰度版本必须为同一规则下审核通过、未启用的版本
+func UpdateRuleCanary(ctx *gin.Context, in *types.UpdateRuleCanaryRequest) error {
+	if err := checkCanaryDriver(); err != nil {
+		return err
+	}
+
+	canary := engine.Canary{Version: in.Version, Weight: in.Weight, StickyKey: in.StickyKey}
+	str, _ := json.MarshalToString(in.Matches)
+	if err := json.UnmarshalFromString(str, &canary.Matches); err != nil {
+		return errors.NewF("灰度条件格式错误:%v", err.Error())
+	}
+	if err := canary.Validate(); err != nil {
+		return err
+	}
+
+	rule := model.Rule{}
+	if err := rule.OneByVersion(ctx, in.Version); err != nil {
+		return errors.NewF("不存在规则版本：%v", in.Version)
+	}
+	if rule.Name != in.Name || rule.Method != strings.ToUpper(in.Method) {
+		return errors.NewF("版本%v不属于规则%v->%v", in.Version, in.Method, in.Name)
+	}
+	if rule.Status != nil && *rule.Status {
+		return errors.NewF("版本%v已经是启用中的版本", in.Version)
+	}
+	if err := checkReview(ctx, model.ReviewKindRule, in.Version, func() (bool, error) {
+		return rule.IsFirstVersion(ctx)
+	}); err != nil {
+		return err
+	}
+
+	rc := model.RuleCanary{}
+	if copier.Copy(&rc, in) != nil {
+		return errors.AssignError
+	}
+	rc.Matches, _ = json.MarshalToString(canary.Matches)
+	return rc.Save(ctx)
+}
+
+// PromoteRuleCanary 将灰度版本切换为启用中的版本，并结束灰度
+func PromoteRuleCanary(ctx *gin.Context, in *types.PromoteRuleCanaryRequest) error {
+	if err := checkCanaryDriver(); err != nil {
+		return err
+	}
+
+	rc := model.RuleCanary{}
+	if err := rc.OneByNameMethod(ctx, in.Name, in.Method); err != nil {
+		return errors.NewF("规则%v->%v不存在灰度版本", in.Method, in.Name)
+	}
+
+	rule := model.Rule{}
+	if err := rule.OneByVersion(ctx, rc.Version); err != nil {
+		return errors.NewF("不存在规则版本：%v", rc.Version)
+	}
+
+	err := SwitchVersionRule(ctx, &types.SwitchVersionRuleRequest{
+		ID:         rule.ID,
+		Force:      in.Force,
+		Operator:   in.Operator,
+		OperatorID: in.OperatorID,
+	})
+	if err != nil {
+		return err
+	}
+	return rc.DeleteByNameMethod(ctx, in.Name, in.Method)
+}
+
+// DeleteRuleCanary 中止灰度，全部流量恢复使用启用中的版本
+func DeleteRuleCanary(ctx *gin.Context, in *types.DeleteRuleCanaryRequest) error {
+	rc := model.RuleCanary{}
+	return rc.DeleteByNameMethod(ctx, in.Name, in.Method)
+}
+
+// checkCanaryDriver 使用文件存储时规则直接从文件加载，灰度配置不会生效
+func checkCanaryDriver() error {
+	if engine.Get().Driver() == engine.StoreDriverFile {
+		return errors.New("使用文件存储时不支持灰度发布")
+	}
+	return nil
+}
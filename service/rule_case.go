@@ -0,0 +1,209 @@
+package service
+
+import (
+	"github.com/jinzhu/copier"
+	json "github.com/json-iterator/go"
+	"github.com/limeschool/gin"
+	"go.uber.org/zap"
+	"ps-go/consts"
+	"ps-go/engine"
+	"ps-go/errors"
+	"ps-go/model"
+	"ps-go/types"
+	"strings"
+)
+
+func PageRuleCase(ctx *gin.Context, in *types.PageRuleCaseRequest) ([]model.RuleCase, int64, error) {
+	rc := model.RuleCase{}
+	return rc.Page(ctx, in.Page, in.Count, in)
+}
+
+func AddRuleCase(ctx *gin.Context, in *types.AddRuleCaseRequest) error {
+	rc := model.RuleCase{}
+	if copier.Copy(&rc, in) != nil {
+		return errors.AssignError
+	}
+
+	var err error
+	if rc.Assertions, err = marshalAssertions(in.Assertions); err != nil {
+		return err
+	}
+	if in.Mocks == nil {
+		in.Mocks = map[string]any{}
+	}
+	rc.Request, _ = json.MarshalToString(in.Request)
+	rc.Mocks, _ = json.MarshalToString(in.Mocks)
+	return rc.Create(ctx)
+}
+
+func UpdateRuleCase(ctx *gin.Context, in *types.UpdateRuleCaseRequest) error {
+	rc := model.RuleCase{}
+	if copier.Copy(&rc, in) != nil {
+		return errors.AssignError
+	}
+
+	if in.Assertions != nil {
+		var err error
+		if rc.Assertions, err = marshalAssertions(in.Assertions); err != nil {
+			return err
+		}
+	}
+	if in.Request != nil {
+		rc.Request, _ = json.MarshalToString(in.Request)
+	}
+	if in.Mocks != nil {
+		rc.Mocks, _ = json.MarshalToString(in.Mocks)
+	}
+	return rc.Update(ctx)
+}
+
+func DeleteRuleCase(ctx *gin.Context, in *types.DeleteRuleCaseRequest) error {
+	rc := model.RuleCase{}
+	if copier.Copy(&rc, in) != nil {
+		return errors.AssignError
+	}
+	return rc.DeleteByID(ctx)
+}
+
+// RunRuleCase 在指定的规则版本上执行全部测试用例，并存储执行结果
+func RunRuleCase(ctx *gin.Context, in *types.RunRuleCaseRequest) ([]model.RuleCaseResult, error) {
+	rule := model.Rule{}
+	if err := rule.OneByVersion(ctx, in.Version); err != nil {
+		return nil, err
+	}
+	return runRuleCases(ctx, &rule)
+}
+
+func PageRuleCaseResult(ctx *gin.Context, in *types.PageRuleCaseResultRequest) ([]model.RuleCaseResult, int64, error) {
+	result := model.RuleCaseResult{}
+	return result.Page(ctx, in.Page, in.Count, in)
+}
+
+// marshalAssertions 校验断言配置，并转换为存储格式
+func marshalAssertions(in []map[string]any) (string, error) {
+	str, _ := json.MarshalToString(in)
+	list := make([]engine.Assertion, 0)
+	if err := json.UnmarshalFromString(str, &list); err != nil {
+		return "", errors.NewF("断言格式错误：%v", err.Error())
+	}
+
+	for _, item := range list {
+		if err := item.Validate(); err != nil {
+			return "", errors.New(err.Error())
+		}
+	}
+
+	str, _ = json.MarshalToString(list)
+	return str, nil
+}
+
+// checkRuleCases 切换版本之前执行目标版本的测试用例，存在未通过的用例时返回错误
+func checkRuleCases(ctx *gin.Context, id int64) error {
+	rule := model.Rule{}
+	if err := rule.OneByID(ctx, id); err != nil {
+		return err
+	}
+
+	// 已经是使用中的版本，不需要重新执行
+	if rule.Status != nil && *rule.Status {
+		return nil
+	}
+
+	results, err := runRuleCases(ctx, &rule)
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, item := range results {
+		if !item.Passed {
+			failed = append(failed, item.Title)
+		}
+	}
+	if len(failed) != 0 {
+		return errors.NewF("版本%v存在未通过的测试用例：%v", rule.Version, strings.Join(failed, "，"))
+	}
+	return nil
+}
+
+// runRuleCases 试运行规则的全部测试用例，组件使用用例中的模拟数据，执行结果按版本存储
+func runRuleCases(ctx *gin.Context, rule *model.Rule) ([]model.RuleCaseResult, error) {
+	list, err := (&model.RuleCase{}).AllByNameMethod(ctx, rule.Name, rule.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]model.RuleCaseResult, 0, len(list))
+	if len(list) == 0 {
+		return results, nil
+	}
+
+	// 规则本身无法通过校验时，不再执行测试用例
+	if err = engine.LintError(engine.Get().LintRule(ctx, rule.Rule)); err != nil {
+		return nil, err
+	}
+
+	for _, item := range list {
+		result := runRuleCase(ctx, rule, &item)
+		if err = result.Create(ctx); err != nil {
+			ctx.Log.Error("测试用例执行结果存储失败", zap.Any("case_id", item.ID), zap.Any("err", err))
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// runRuleCase 执行单个测试用例，并对执行结果进行断言
+func runRuleCase(ctx *gin.Context, rule *model.Rule, rc *model.RuleCase) model.RuleCaseResult {
+	result := model.RuleCaseResult{
+		CaseID:  rc.ID,
+		Name:    rule.Name,
+		Method:  rule.Method,
+		Version: rule.Version,
+		Title:   rc.Title,
+	}
+
+	failures := make([]string, 0)
+	defer func() {
+		result.Passed = len(failures) == 0
+		result.Failures, _ = json.MarshalToString(failures)
+	}()
+
+	request := types.DryRunRequest{}
+	mocks := map[string]any{}
+	var assertions []engine.Assertion
+
+	if err := json.UnmarshalFromString(rc.Request, &request); err != nil {
+		failures = append(failures, "用例request格式错误："+err.Error())
+		return result
+	}
+	if err := json.UnmarshalFromString(rc.Mocks, &mocks); err != nil {
+		failures = append(failures, "用例mocks格式错误："+err.Error())
+		return result
+	}
+	if err := json.UnmarshalFromString(rc.Assertions, &assertions); err != nil {
+		failures = append(failures, "用例assertions格式错误："+err.Error())
+		return result
+	}
+
+	// 未设置断言时，要求流程成功执行
+	if len(assertions) == 0 {
+		assertions = append(assertions, engine.Assertion{Path: "status", Value: engine.RunSuccess})
+	}
+
+	res, _, err := dryRun(ctx, rule, request, mocks)
+	result.Trx = ctx.GetString(consts.ProcessScheduleTrx)
+	if err != nil {
+		failures = append(failures, err.Error())
+		return result
+	}
+
+	result.Status = res.Status
+	result.Response, _ = json.MarshalToString(res.Response)
+	for _, item := range assertions {
+		if err = item.Check(res); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+	return result
+}
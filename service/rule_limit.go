@@ -0,0 +1,55 @@
+package service
+
+import (
+	"github.com/jinzhu/copier"
+	json "github.com/json-iterator/go"
+	"github.com/limeschool/gin"
+	"gorm.io/gorm"
+	"ps-go/engine"
+	"ps-go/errors"
+	"ps-go/model"
+	"ps-go/types"
+)
+
+// GetRuleLimit 获取规则当前生效的限流配置，override表示是否为单独设置的限流配置
+func GetRuleLimit(ctx *gin.Context, in *types.GetRuleLimitRequest) (*engine.Limit, bool, error) {
+	eg := engine.Get()
+	rule, err := eg.LoadRule(ctx, in.Method, in.Name)
+	if err != nil {
+		return nil, false, err
+	}
+
+	rl := model.RuleLimit{}
+	if err = rl.OneByNameMethod(ctx, in.Name, in.Method); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+
+	limit, err := eg.LoadLimit(ctx, in.Method, in.Name, rule)
+	return limit, rl.ID != 0, err
+}
+
+// UpdateRuleLimit 单独设置规则的限流配置，立即生效，不需要发布新的规则版本
+func UpdateRuleLimit(ctx *gin.Context, in *types.UpdateRuleLimitRequest) error {
+	if _, err := engine.Get().LoadRule(ctx, in.Method, in.Name); err != nil {
+		return err
+	}
+
+	str, _ := json.MarshalToString(in.Limit)
+	limit := engine.Limit{}
+	if err := json.UnmarshalFromString(str, &limit); err != nil {
+		return errors.NewF("限流配置格式错误:%v", err.Error())
+	}
+
+	rl := model.RuleLimit{}
+	if copier.Copy(&rl, in) != nil {
+		return errors.AssignError
+	}
+	rl.Config, _ = json.MarshalToString(limit)
+	return rl.Save(ctx)
+}
+
+// DeleteRuleLimit 删除单独设置的限流配置，删除之后使用规则中的limit配置
+func DeleteRuleLimit(ctx *gin.Context, in *types.DeleteRuleLimitRequest) error {
+	rl := model.RuleLimit{}
+	return rl.DeleteByNameMethod(ctx, in.Name, in.Method)
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"github.com/jinzhu/copier"
+	"github.com/limeschool/gin"
+	"ps-go/consts"
+	"ps-go/errors"
+	"ps-go/model"
+	"ps-go/types"
+)
+
+// GetRuleRetention 获取规则保留的历史版本数量
+func GetRuleRetention(ctx *gin.Context, in *types.GetRuleRetentionRequest) int {
+	rr := model.RuleRetention{}
+	return rr.Count(ctx, in.Name, in.Method)
+}
+
+// UpdateRuleRetention 设置规则保留的历史版本数量，超出数量的未启用版本在下次发布新版本时清理
+func UpdateRuleRetention(ctx *gin.Context, in *types.UpdateRuleRetentionRequest) error {
+	if in.HistoryCount < consts.RuleHistoryMinCount || in.HistoryCount > consts.RuleHistoryMaxCount {
+		return errors.NewF("保留的版本数量范围为%v-%v", consts.RuleHistoryMinCount, consts.RuleHistoryMaxCount)
+	}
+
+	rule := model.Rule{}
+	if err := rule.OneByNameMethod(ctx, in.Name, in.Method); err != nil {
+		return err
+	}
+
+	rr := model.RuleRetention{}
+	if copier.Copy(&rr, in) != nil {
+		return errors.AssignError
+	}
+	return rr.Save(ctx)
+}
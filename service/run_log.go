@@ -1,6 +1,7 @@
 package service
 
 import (
+	json "github.com/json-iterator/go"
 	"github.com/limeschool/gin"
 	"ps-go/model"
 	"ps-go/types"
@@ -10,3 +11,36 @@ func GetRunLog(ctx *gin.Context, in *types.GetRunLogRequest) (model.RunLog, erro
 	suspend := model.RunLog{}
 	return suspend, suspend.OneByTrx(ctx, in.Trx)
 }
+
+func GetRunStatus(ctx *gin.Context, in *types.GetRunStatusRequest) (model.RunStatus, error) {
+	status := model.RunStatus{}
+	if err := status.OneByTrx(ctx, in.Trx); err == nil {
+		return status, nil
+	}
+
+	// 执行进度过期之后，从执行日志中获取
+	log := model.RunLog{}
+	if err := log.OneByTrx(ctx, in.Trx); err != nil {
+		return status, err
+	}
+
+	msg := struct {
+		Response any    `json:"response"`
+		Error    string `json:"error"`
+	}{}
+	_ = json.UnmarshalFromString(log.Msg, &msg)
+
+	return model.RunStatus{
+		Trx:       log.Trx,
+		Method:    log.Method,
+		Path:      log.Path,
+		Version:   log.Version,
+		State:     model.RunStateDone,
+		Status:    log.Status,
+		Step:      log.Step,
+		CurStep:   log.CurStep,
+		Error:     msg.Error,
+		Response:  msg.Response,
+		UpdatedAt: log.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"github.com/jinzhu/copier"
+	json "github.com/json-iterator/go"
+	"github.com/limeschool/gin"
+	"go.uber.org/zap"
+	"ps-go/consts"
+	"ps-go/engine"
+	"ps-go/errors"
+	"ps-go/model"
+	"ps-go/tools"
+	"ps-go/tools/pool"
+	"ps-go/types"
+	"time"
+)
+
+func PageSchedule(ctx *gin.Context, in *types.PageScheduleRequest) ([]model.Schedule, int64, error) {
+	schedule := model.Schedule{}
+	return schedule.Page(ctx, in.Page, in.Count, in)
+}
+
+func AddSchedule(ctx *gin.Context, in *types.AddScheduleRequest) error {
+	// 绑定的规则必须存在
+	if _, err := engine.Get().LoadRule(ctx, in.Method, in.Name); err != nil {
+		return err
+	}
+
+	schedule := model.Schedule{}
+	if copier.Copy(&schedule, in) != nil {
+		return errors.AssignError
+	}
+
+	if in.Request == nil {
+		in.Request = map[string]any{}
+	}
+	schedule.Request, _ = json.MarshalToString(in.Request)
+
+	status := true
+	schedule.Status = &status
+	return schedule.Create(ctx)
+}
+
+func DeleteSchedule(ctx *gin.Context, in *types.DeleteScheduleRequest) error {
+	schedule := model.Schedule{}
+	if copier.Copy(&schedule, in) != nil {
+		return errors.AssignError
+	}
+	return schedule.DeleteByID(ctx)
+}
+
+func PauseSchedule(ctx *gin.Context, in *types.ScheduleStatusRequest) error {
+	schedule := model.Schedule{}
+	schedule.ID = in.ID
+	return schedule.UpdateStatus(ctx, false)
+}
+
+func ResumeSchedule(ctx *gin.Context, in *types.ScheduleStatusRequest) error {
+	schedule := model.Schedule{}
+	schedule.ID = in.ID
+	return schedule.UpdateStatus(ctx, true)
+}
+
+// TriggerSchedule 手动触发定时任务，任务在后台执行，直接返回trx
+func TriggerSchedule(ctx *gin.Context, in *types.TriggerScheduleRequest) (string, error) {
+	schedule := model.Schedule{}
+	if err := schedule.OneByID(ctx, in.ID); err != nil {
+		return "", err
+	}
+
+	trx := tools.NewTrx()
+	go RunSchedule(tools.NewBackgroundContext(), &schedule, trx)
+	return trx, nil
+}
+
+// RunSchedule 执行定时任务绑定的规则，执行结果记录到run_log中
+func RunSchedule(ctx *gin.Context, schedule *model.Schedule, trx string) {
+	startTime := time.Now()
+	ctx.Set(consts.ProcessScheduleTrx, trx)
+
+	if err := schedule.UpdateFire(ctx, trx, startTime); err != nil {
+		ctx.Log.Error("定时任务触发信息更新失败", zap.Any("trx", trx), zap.Any("err", err))
+	}
+
+	if err := runSchedule(ctx, schedule, startTime); err != nil {
+		ctx.Log.Error("定时任务执行失败", zap.Any("trx", trx), zap.Any("err", err))
+
+		// 规则未开始执行时，同样记录触发记录
+		msg, _ := json.MarshalToString(map[string]any{
+			"start_time": startTime.Format(engine.LogDatetimeFormat),
+			"error":      err.Error(),
+		})
+		log := model.RunLog{
+			Trx:    trx,
+			LogID:  ctx.TraceID,
+			Method: schedule.Method,
+			Path:   schedule.Name,
+			Msg:    msg,
+			Status: engine.RunBreak,
+		}
+		if err = log.Create(ctx); err != nil {
+			ctx.Log.Error("定时任务执行记录存储失败", zap.Any("trx", trx), zap.Any("err", err))
+		}
+	}
+}
+
+func runSchedule(ctx *gin.Context, schedule *model.Schedule, startTime time.Time) error {
+	eg := engine.Get()
+
+	rule, err := eg.LoadRule(ctx, schedule.Method, schedule.Name)
+	if err != nil {
+		return err
+	}
+
+	body := map[string]any{}
+	if schedule.Request != "" {
+		if err = json.UnmarshalFromString(schedule.Request, &body); err != nil {
+			return errors.NewF("定时任务request格式错误:%v", err.Error())
+		}
+	}
+
+	// 校验参数
+	requestInfo, err := eg.NewValidate(rule.Request).BindData(body)
+	if err != nil {
+		return err
+	}
+
+	// 定时任务的每次触发都需要记录
+	rule.Record = true
+
+	runStore := eg.NewRunStore()
+	runStore.SetData("request", requestInfo)
+
+	runner := eg.NewRunner(ctx, rule, runStore)
+	runner.SetMethodAndPath(schedule.Method, schedule.Name)
+	defer runner.Release()
+
+	runner.NewLogger()
+	runner.SetRequestLog(startTime, requestInfo)
+
+	_ = pool.Get().Invoke(runner)
+	go runner.WaitError()
+	runner.WaitResponse()
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/limeschool/gin"
 	"ps-go/errors"
 	"ps-go/model"
+	"ps-go/tools"
 	"ps-go/types"
 )
 
@@ -36,21 +37,80 @@ func AddScript(ctx *gin.Context, in *types.AddScriptRequest) error {
 	if copier.Copy(&script, in) != nil {
 		return errors.AssignError
 	}
-	return script.Create(ctx)
+	if err := script.Create(ctx); err != nil {
+		return err
+	}
+	auditAfter(ctx, script)
+
+	return createReview(ctx, &model.VersionReview{
+		Kind:     model.ReviewKindScript,
+		Name:     script.Name,
+		Version:  script.Version,
+		Author:   in.Operator,
+		AuthorID: in.OperatorID,
+	}, *script.Status, "创建版本", func() error {
+		return (&model.Script{DeleteModel: gin.DeleteModel{ID: script.ID}}).DeleteByID(ctx)
+	})
 }
 
+// SwitchVersionScript 发布脚本版本，目标版本需要审核通过
 func SwitchVersionScript(ctx *gin.Context, in *types.SwitchVersionScriptRequest) error {
+	info := model.Script{}
+	if err := info.OneByID(ctx, in.ID); err != nil {
+		return err
+	}
+	if *info.Status {
+		return nil
+	}
+	if err := checkReview(ctx, model.ReviewKindScript, info.Version, func() (bool, error) {
+		return info.IsFirstVersion(ctx)
+	}); err != nil {
+		return err
+	}
+
+	// 记录切换前启用中的版本
+	active := model.Script{}
+	if active.OneByName(ctx, info.Name) == nil {
+		auditBefore(ctx, gin.H{"name": active.Name, "version": active.Version})
+	}
+
 	script := model.Script{}
 	if copier.Copy(&script, in) != nil {
 		return errors.AssignError
 	}
-	return script.SwitchVersion(ctx)
+	if err := script.SwitchVersion(ctx); err != nil {
+		return err
+	}
+	auditAfter(ctx, gin.H{"name": info.Name, "version": info.Version})
+
+	finishReview(ctx, model.ReviewKindScript, info.Version, in.Comment, in.Operator, in.OperatorID)
+	return nil
 }
 
 func DeleteScript(ctx *gin.Context, in *types.DeleteScriptRequest) error {
+	before := model.Script{}
+	if before.OneByID(ctx, in.ID) == nil {
+		auditBefore(ctx, before)
+	}
+
 	script := model.Script{}
 	if copier.Copy(&script, in) != nil {
 		return errors.AssignError
 	}
 	return script.DeleteByID(ctx)
 }
+
+// DiffScript 逐行对比同一个脚本的两个版本
+func DiffScript(ctx *gin.Context, in *types.DiffScriptRequest) ([]tools.DiffLine, error) {
+	from, to := model.Script{}, model.Script{}
+	if err := from.OneByVersion(ctx, in.From); err != nil {
+		return nil, err
+	}
+	if err := to.OneByVersion(ctx, in.To); err != nil {
+		return nil, err
+	}
+	if from.Name != to.Name {
+		return nil, errors.New("只能对比同一个脚本的不同版本")
+	}
+	return tools.DiffLines(from.Script, to.Script)
+}
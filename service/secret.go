@@ -32,18 +32,40 @@ func AddSecret(ctx *gin.Context, in *types.AddSecretRequest) error {
 	if copier.Copy(&Secret, in) != nil {
 		return errors.AssignError
 	}
-	return Secret.Create(ctx)
+	if err := Secret.Create(ctx); err != nil {
+		return err
+	}
+	auditAfter(ctx, Secret)
+	return nil
 }
 
 func UpdateSecret(ctx *gin.Context, in *types.UpdateSecretRequest) error {
+	before := model.Secret{}
+	if before.OneByID(ctx, in.ID) == nil {
+		auditBefore(ctx, before)
+	}
+
 	Secret := model.Secret{}
 	if copier.Copy(&Secret, in) != nil {
 		return errors.AssignError
 	}
-	return Secret.Update(ctx)
+	if err := Secret.Update(ctx); err != nil {
+		return err
+	}
+
+	after := model.Secret{}
+	if after.OneByID(ctx, in.ID) == nil {
+		auditAfter(ctx, after)
+	}
+	return nil
 }
 
 func DeleteSecret(ctx *gin.Context, in *types.DeleteSecretRequest) error {
+	before := model.Secret{}
+	if before.OneByID(ctx, in.ID) == nil {
+		auditBefore(ctx, before)
+	}
+
 	Secret := model.Secret{}
 	if copier.Copy(&Secret, in) != nil {
 		return errors.AssignError
@@ -35,6 +35,7 @@ func SuspendRecover(ctx *gin.Context, in *types.SuspendRecoverRequest) (any, err
 	if err := suspend.OneByTrx(ctx, in.Trx); err != nil {
 		return nil, err
 	}
+	auditBefore(ctx, suspend)
 
 	// 通过trx获取对应日志信息
 	log := model.RunLog{}
@@ -45,6 +46,7 @@ func SuspendRecover(ctx *gin.Context, in *types.SuspendRecoverRequest) (any, err
 	var data = make(map[string]any) // 执行上下文数据
 	var rule engine.Rule            //执行规则
 	var names []string              //所在层需要重试的组件名
+	var finishes []string           //已经完成的组件
 
 	if err := json.UnmarshalFromString(suspend.Rule, &rule); err != nil {
 		return nil, errors.NewF("任务重启失败，rule格式错误:%v", err.Error())
@@ -58,6 +60,12 @@ func SuspendRecover(ctx *gin.Context, in *types.SuspendRecoverRequest) (any, err
 		return nil, errors.NewF("任务重启失败，err_component格式错误:%v", err.Error())
 	}
 
+	if suspend.FinishComponent != "" {
+		if err := json.UnmarshalFromString(suspend.FinishComponent, &finishes); err != nil {
+			return nil, errors.NewF("任务重启失败，finish_component格式错误:%v", err.Error())
+		}
+	}
+
 	// 将重启的data参数载入
 	for key, val := range in.Data {
 		data[key] = val
@@ -73,15 +81,32 @@ func SuspendRecover(ctx *gin.Context, in *types.SuspendRecoverRequest) (any, err
 
 	// 创建运行器
 	runner := eg.NewRunner(ctx, &rule, runStore)
+	defer runner.Release()
 	runner.SetMethodAndPath(suspend.Method, suspend.Path)
 	runner.NewLoggerFromString(log.Msg)
 
-	// 设置恢复重试从第几层开始
-	runner.SetStep(suspend.CurStep - 1)
-	if err := runner.SetStepComponentRetry(suspend.CurStep-1, names); err != nil {
+	// 补偿失败的任务，从失败的组件开始继续补偿
+	if suspend.Type == model.SuspendTypeCompensate {
+		if err := suspend.DeleteByTrx(ctx, suspend.Trx); err != nil {
+			return nil, err
+		}
+
+		err := runner.Compensate(finishes)
+		runner.SaveLog()
 		return nil, err
 	}
 
+	// 按照依赖图恢复，只执行未完成的组件
+	if suspend.FinishComponent != "" {
+		runner.SetFinishComponents(finishes)
+	} else {
+		// 设置恢复重试从第几层开始
+		runner.SetStep(suspend.CurStep - 1)
+		if err := runner.SetStepComponentRetry(suspend.CurStep-1, names); err != nil {
+			return nil, err
+		}
+	}
+
 	// 删除中断信息
 	if err := suspend.DeleteByTrx(ctx, suspend.Trx); err != nil {
 		return nil, err
@@ -100,21 +125,42 @@ func SuspendRecover(ctx *gin.Context, in *types.SuspendRecoverRequest) (any, err
 }
 
 func UpdateSuspend(ctx *gin.Context, in *types.UpdateSuspendRequest) error {
+	before := model.SuspendLog{}
+	if err := before.OneByID(ctx, in.ID); err != nil {
+		return err
+	}
+	auditBefore(ctx, before)
+
 	suspend := model.SuspendLog{}
+	suspend.ID = in.ID
 	suspend.CurStep = in.CurStep
 
 	if in.ErrNames != nil {
 		suspend.ErrComponent, _ = json.MarshalToString(in.ErrNames)
 	}
 
+	if in.FinishNames != nil {
+		suspend.FinishComponent, _ = json.MarshalToString(in.FinishNames)
+	}
+
 	if in.Data != nil {
 		suspend.Data, _ = json.MarshalToString(in.Data)
 	}
 
 	if in.Rule != nil {
-		// todo ruleCheck
 		suspend.Rule, _ = json.MarshalToString(in.Rule)
+		if err := engine.LintError(engine.Get().LintRule(ctx, suspend.Rule)); err != nil {
+			return err
+		}
 	}
 
-	return suspend.Update(ctx)
+	if err := suspend.Update(ctx); err != nil {
+		return err
+	}
+
+	after := model.SuspendLog{}
+	if after.OneByID(ctx, in.ID) == nil {
+		auditAfter(ctx, after)
+	}
+	return nil
 }
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"fmt"
+	json "github.com/json-iterator/go"
+	"github.com/limeschool/gin"
+	"go.uber.org/zap"
+	"ps-go/consts"
+	"ps-go/engine"
+	"ps-go/errors"
+	"ps-go/model"
+	"ps-go/tools/lock"
+	"ps-go/tools/pool"
+	"ps-go/types"
+	"time"
+)
+
+var waitResumingError = errors.New("流程正在恢复执行，请稍后重试")
+
+func PageWait(ctx *gin.Context, in *types.PageWaitRequest) ([]model.WaitLog, int64, error) {
+	wait := model.WaitLog{}
+	return wait.Page(ctx, in.Page, in.Count, in)
+}
+
+// Signal 向等待中的流程发送信号，信号数据合并到流程上下文之后继续执行
+func Signal(ctx *gin.Context, in *types.SignalRequest) (any, error) {
+	wait := model.WaitLog{}
+	if err := wait.OneByTrx(ctx, in.Trx); err != nil {
+		return nil, err
+	}
+
+	var waits []engine.WaitNode
+	if err := json.UnmarshalFromString(wait.Waits, &waits); err != nil {
+		return nil, errors.NewF("流程恢复失败，waits格式错误:%v", err.Error())
+	}
+
+	for _, item := range waits {
+		if item.Signal == in.Signal {
+			return resumeWait(ctx, in.Trx, &engine.WaitSignal{
+				Key:   item.Key,
+				Data:  in.Payload,
+				Waits: waits,
+			})
+		}
+	}
+
+	return nil, errors.NewF("流程%v没有等待信号%v", in.Trx, in.Signal)
+}
+
+// WaitTimeout 处理等待超时的流程，超时的组件跳转到超时目标或者中断流程
+func WaitTimeout(ctx *gin.Context, wait *model.WaitLog) {
+	var waits []engine.WaitNode
+	if err := json.UnmarshalFromString(wait.Waits, &waits); err != nil {
+		ctx.Log.Error("等待流程waits格式错误", zap.Any("trx", wait.Trx), zap.Any("err", err))
+		return
+	}
+
+	now := time.Now().Unix()
+	for _, item := range waits {
+		if item.TimeoutAt == 0 || item.TimeoutAt > now {
+			continue
+		}
+
+		_, err := resumeWait(ctx, wait.Trx, &engine.WaitSignal{
+			Key:     item.Key,
+			Timeout: true,
+			Waits:   waits,
+		})
+		if err != nil && !errors.Is(err, waitResumingError) {
+			ctx.Log.Error("等待超时处理失败", zap.Any("trx", wait.Trx), zap.Any("err", err))
+		}
+		return
+	}
+}
+
+// resumeWait 恢复等待中的流程
+func resumeWait(ctx *gin.Context, trx string, signal *engine.WaitSignal) (any, error) {
+	// 加锁，防止信号与超时同时恢复同一个流程
+	rl := lock.NewLock(ctx, fmt.Sprintf("wait_%v", trx))
+	if !rl.TryAcquire() {
+		return nil, waitResumingError
+	}
+
+	// 获取锁之后重新查询，防止流程已经被恢复
+	wait := model.WaitLog{}
+	if err := wait.OneByTrx(ctx, trx); err != nil {
+		rl.Release()
+		return nil, err
+	}
+
+	var data = make(map[string]any) // 执行上下文数据
+	var rule engine.Rule            // 执行规则
+	var finishes []string           // 已经完成的组件
+
+	if err := json.UnmarshalFromString(wait.Rule, &rule); err != nil {
+		rl.Release()
+		return nil, errors.NewF("流程恢复失败，rule格式错误:%v", err.Error())
+	}
+
+	if err := json.UnmarshalFromString(wait.Data, &data); err != nil {
+		rl.Release()
+		return nil, errors.NewF("流程恢复失败，data格式错误:%v", err.Error())
+	}
+
+	if err := json.UnmarshalFromString(wait.FinishComponent, &finishes); err != nil {
+		rl.Release()
+		return nil, errors.NewF("流程恢复失败，finish_component格式错误:%v", err.Error())
+	}
+
+	// 将信号数据合并到上下文
+	if payload, ok := signal.Data.(map[string]any); ok {
+		for key, val := range payload {
+			data[key] = val
+		}
+	}
+
+	// 通过trx获取对应日志信息，未开启记录时重新创建日志
+	log := model.RunLog{}
+	logErr := log.OneByTrx(ctx, trx)
+
+	// 删除等待信息，之后的信号不会重复恢复
+	if err := wait.DeleteByTrx(ctx, trx); err != nil {
+		rl.Release()
+		return nil, err
+	}
+	rl.Release()
+
+	eg := engine.Get()
+	ctx.Set(consts.ProcessScheduleTrx, trx)
+
+	runStore := eg.NewRunStoreByData(data)
+	runner := eg.NewRunner(ctx, &rule, runStore)
+	defer runner.Release()
+	runner.SetMethodAndPath(wait.Method, wait.Path)
+
+	if logErr != nil {
+		runner.NewLogger()
+		runner.SetRequestLog(time.Now(), data["request"])
+	} else {
+		runner.NewLoggerFromString(log.Msg)
+	}
+
+	// 只执行未完成的组件
+	runner.SetFinishComponents(finishes)
+	runner.SetSignal(signal)
+
+	// 执行服务
+	_ = pool.Get().Invoke(runner)
+
+	// 异步监听错误信息
+	go runner.WaitError()
+	// 同步等待返回结果
+	runner.WaitResponse()
+
+	return runner.Response(), nil
+}
@@ -0,0 +1,361 @@
+package breaker
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"github.com/limeschool/gin"
+	"go.uber.org/zap"
+	"net/url"
+	"ps-go/consts"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	StateClosed   = "closed"    //关闭，正常请求
+	StateOpen     = "open"      //打开，直接拒绝请求
+	StateHalfOpen = "half_open" //半开，只放行少量探测请求
+
+	SourceRedis = "redis" //状态保存在redis中，多实例共享
+	SourceLocal = "local" //redis不可用时保存在当前实例中
+
+	keyPrefix = "breaker_"
+)
+
+// Config 熔断配置
+type Config struct {
+	FailureThreshold int `json:"failureThreshold"` //统计窗口内失败多少次之后熔断，默认5
+	Window           int `json:"window"`           //失败次数的统计窗口，单位秒，默认60
+	OpenTimeout      int `json:"openTimeout"`      //熔断持续时长，之后进入半开状态，单位秒，默认30
+	HalfOpenProbes   int `json:"halfOpenProbes"`   //半开状态放行的探测请求数量，全部成功后关闭熔断，默认1
+}
+
+// State 上游服务的熔断状态
+type State struct {
+	Host        string `json:"host"`
+	State       string `json:"state"`
+	Failures    int    `json:"failures"`     //统计窗口内的失败次数
+	WindowStart int64  `json:"window_start"` //统计窗口的开始时间
+	OpenedAt    int64  `json:"opened_at"`    //熔断开始时间
+	Probes      int    `json:"probes"`       //半开状态已放行的探测请求数量
+	Successes   int    `json:"successes"`    //半开状态探测成功的数量
+	Source      string `json:"source"`
+}
+
+func (c Config) withDefault() Config {
+	if c.FailureThreshold <= 0 {
+		c.FailureThreshold = 5
+	}
+	if c.Window <= 0 {
+		c.Window = 60
+	}
+	if c.OpenTimeout <= 0 {
+		c.OpenTimeout = 30
+	}
+	if c.HalfOpenProbes <= 0 {
+		c.HalfOpenProbes = 1
+	}
+	return c
+}
+
+// expire 状态在redis中的保存时长，半开状态的探测请求异常退出时，过期后自动恢复
+func (c Config) expire() time.Duration {
+	return time.Duration(c.Window+c.OpenTimeout*2) * time.Second
+}
+
+// Host 获取url对应的上游服务标志
+func Host(rawUrl string) string {
+	u, err := url.Parse(rawUrl)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return u.Host
+}
+
+// allowScript 判断是否放行请求，0拒绝 1放行 2放行探测请求
+var allowScript = redis.NewScript(`
+local state = redis.call('HGET', KEYS[1], 'state')
+if not state or state == 'closed' then
+	return 1
+end
+if state == 'open' then
+	local opened = tonumber(redis.call('HGET', KEYS[1], 'opened_at') or '0')
+	if tonumber(ARGV[1]) - opened < tonumber(ARGV[2]) then
+		return 0
+	end
+	redis.call('HSET', KEYS[1], 'state', 'half_open', 'probes', 0, 'successes', 0)
+	redis.call('PEXPIRE', KEYS[1], ARGV[4])
+end
+if redis.call('HINCRBY', KEYS[1], 'probes', 1) > tonumber(ARGV[3]) then
+	redis.call('HINCRBY', KEYS[1], 'probes', -1)
+	return 0
+end
+return 2
+`)
+
+// reportScript 上报请求结果，返回上报之后的状态
+var reportScript = redis.NewScript(`
+local state = redis.call('HGET', KEYS[1], 'state') or 'closed'
+local now = tonumber(ARGV[2])
+if state == 'half_open' then
+	if ARGV[1] == '1' then
+		if redis.call('HINCRBY', KEYS[1], 'successes', 1) >= tonumber(ARGV[5]) then
+			redis.call('DEL', KEYS[1])
+			return 'closed'
+		end
+		return state
+	end
+	redis.call('HSET', KEYS[1], 'state', 'open', 'opened_at', now, 'probes', 0, 'successes', 0)
+	redis.call('PEXPIRE', KEYS[1], ARGV[6])
+	return 'open'
+end
+if state == 'open' or ARGV[1] == '1' then
+	return state
+end
+local start = tonumber(redis.call('HGET', KEYS[1], 'window_start') or '0')
+if now - start >= tonumber(ARGV[4]) then
+	redis.call('HSET', KEYS[1], 'state', 'closed', 'failures', 0, 'window_start', now)
+end
+if redis.call('HINCRBY', KEYS[1], 'failures', 1) >= tonumber(ARGV[3]) then
+	redis.call('HSET', KEYS[1], 'state', 'open', 'opened_at', now, 'probes', 0, 'successes', 0)
+	state = 'open'
+end
+redis.call('PEXPIRE', KEYS[1], ARGV[6])
+return state
+`)
+
+// releaseScript 归还半开状态的探测名额
+var releaseScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'state') == 'half_open' and tonumber(redis.call('HGET', KEYS[1], 'probes') or '0') > 0 then
+	redis.call('HINCRBY', KEYS[1], 'probes', -1)
+end
+return 1
+`)
+
+var local = &localStore{states: map[string]*State{}}
+
+// Allow 判断是否允许请求上游服务，probe为true时表示放行的是半开状态的探测请求。
+// 放行之后需要调用Report上报请求结果，探测请求无法得出结果时（如请求被取消）需要调用Release归还探测名额
+func Allow(ctx *gin.Context, host string, conf Config) (allowed bool, probe bool) {
+	conf = conf.withDefault()
+	now := time.Now().Unix()
+
+	if client := ctx.Redis(consts.ProcessScheduleCache); client != nil {
+		res, err := allowScript.Run(context.TODO(), client, []string{keyPrefix + host},
+			now, conf.OpenTimeout, conf.HalfOpenProbes, conf.expire().Milliseconds()).Int()
+		if err == nil {
+			return res != 0, res == 2
+		}
+		ctx.Log.Warn("熔断状态redis不可用，使用本地状态", zap.Any("host", host), zap.Any("err", err))
+	}
+	return local.allow(host, now, conf)
+}
+
+// Release 归还探测请求占用的探测名额，不记录请求结果，其他请求可以重新探测
+func Release(ctx *gin.Context, host string) {
+	if client := ctx.Redis(consts.ProcessScheduleCache); client != nil {
+		err := releaseScript.Run(context.TODO(), client, []string{keyPrefix + host}).Err()
+		if err == nil {
+			return
+		}
+		ctx.Log.Warn("熔断状态redis不可用，使用本地状态", zap.Any("host", host), zap.Any("err", err))
+	}
+	local.release(host)
+}
+
+// Report 上报请求结果，返回上报之后的熔断状态
+func Report(ctx *gin.Context, host string, conf Config, success bool) string {
+	conf = conf.withDefault()
+	now := time.Now().Unix()
+
+	if client := ctx.Redis(consts.ProcessScheduleCache); client != nil {
+		flag := 0
+		if success {
+			flag = 1
+		}
+		res, err := reportScript.Run(context.TODO(), client, []string{keyPrefix + host},
+			flag, now, conf.FailureThreshold, conf.Window, conf.HalfOpenProbes, conf.expire().Milliseconds()).Text()
+		if err == nil {
+			return res
+		}
+		ctx.Log.Warn("熔断状态redis不可用，使用本地状态", zap.Any("host", host), zap.Any("err", err))
+	}
+	return local.report(host, now, conf, success)
+}
+
+// List 获取全部熔断状态，包括redis以及当前实例中保存的状态
+func List(ctx *gin.Context) ([]*State, error) {
+	list := local.list()
+
+	if client := ctx.Redis(consts.ProcessScheduleCache); client != nil {
+		redisList, err := listRedis(client)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, redisList...)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Host < list[j].Host
+	})
+	return list, nil
+}
+
+func listRedis(client *redis.Client) ([]*State, error) {
+	var list []*State
+	iter := client.Scan(context.TODO(), 0, keyPrefix+"*", 100).Iterator()
+	for iter.Next(context.TODO()) {
+		key := iter.Val()
+		values, err := client.HGetAll(context.TODO(), key).Result()
+		if err != nil {
+			return nil, err
+		}
+		if len(values) == 0 {
+			continue
+		}
+		list = append(list, parseState(strings.TrimPrefix(key, keyPrefix), values))
+	}
+	return list, iter.Err()
+}
+
+// Reset 手动关闭熔断
+func Reset(ctx *gin.Context, host string) error {
+	local.reset(host)
+	if client := ctx.Redis(consts.ProcessScheduleCache); client != nil {
+		return client.Del(context.TODO(), keyPrefix+host).Err()
+	}
+	return nil
+}
+
+func parseState(host string, values map[string]string) *State {
+	atoi := func(key string) int {
+		v, _ := strconv.Atoi(values[key])
+		return v
+	}
+	state := &State{
+		Host:        host,
+		State:       values["state"],
+		Failures:    atoi("failures"),
+		WindowStart: int64(atoi("window_start")),
+		OpenedAt:    int64(atoi("opened_at")),
+		Probes:      atoi("probes"),
+		Successes:   atoi("successes"),
+		Source:      SourceRedis,
+	}
+	if state.State == "" {
+		state.State = StateClosed
+	}
+	return state
+}
+
+// localStore redis不可用时使用的进程内熔断状态
+type localStore struct {
+	lock   sync.Mutex
+	states map[string]*State
+}
+
+func (l *localStore) get(host string, now int64, conf Config) *State {
+	// 与redis保持一致，超过保存时长之后恢复为关闭状态
+	s, ok := l.states[host]
+	if ok {
+		last := s.WindowStart
+		if s.OpenedAt > last {
+			last = s.OpenedAt
+		}
+		ok = now-last < int64(conf.expire().Seconds())
+	}
+	if !ok {
+		s = &State{Host: host, State: StateClosed, WindowStart: now, Source: SourceLocal}
+		l.states[host] = s
+	}
+	return s
+}
+
+func (l *localStore) allow(host string, now int64, conf Config) (bool, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	s := l.get(host, now, conf)
+	switch s.State {
+	case StateClosed:
+		return true, false
+	case StateOpen:
+		if now-s.OpenedAt < int64(conf.OpenTimeout) {
+			return false, false
+		}
+		s.State, s.Probes, s.Successes = StateHalfOpen, 0, 0
+	}
+
+	if s.Probes >= conf.HalfOpenProbes {
+		return false, false
+	}
+	s.Probes++
+	return true, true
+}
+
+func (l *localStore) release(host string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if s, ok := l.states[host]; ok && s.State == StateHalfOpen && s.Probes > 0 {
+		s.Probes--
+	}
+}
+
+func (l *localStore) report(host string, now int64, conf Config, success bool) string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	s := l.get(host, now, conf)
+	switch s.State {
+	case StateHalfOpen:
+		if success {
+			s.Successes++
+			if s.Successes >= conf.HalfOpenProbes {
+				delete(l.states, host)
+				return StateClosed
+			}
+			return s.State
+		}
+		s.State, s.OpenedAt, s.Probes, s.Successes = StateOpen, now, 0, 0
+		return s.State
+	case StateOpen:
+		return s.State
+	}
+
+	if success {
+		return s.State
+	}
+	if now-s.WindowStart >= int64(conf.Window) {
+		s.Failures, s.WindowStart = 0, now
+	}
+	s.Failures++
+	if s.Failures >= conf.FailureThreshold {
+		s.State, s.OpenedAt, s.Probes, s.Successes = StateOpen, now, 0, 0
+	}
+	return s.State
+}
+
+func (l *localStore) list() []*State {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	list := make([]*State, 0)
+	for _, s := range l.states {
+		if s.State == StateClosed && s.Failures == 0 {
+			continue
+		}
+		item := *s
+		list = append(list, &item)
+	}
+	return list
+}
+
+func (l *localStore) reset(host string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	delete(l.states, host)
+}
@@ -0,0 +1,193 @@
+package breaker
+
+import (
+	"testing"
+)
+
+func newLocal() *localStore {
+	return &localStore{states: map[string]*State{}}
+}
+
+func TestLocalOpenAfterThreshold(t *testing.T) {
+	l := newLocal()
+	conf := Config{FailureThreshold: 3, Window: 60, OpenTimeout: 30, HalfOpenProbes: 1}
+	now := int64(1000)
+
+	for i := 0; i < 2; i++ {
+		if state := l.report("a", now, conf, false); state != StateClosed {
+			t.Fatalf("failure %v: state = %v, want closed", i+1, state)
+		}
+	}
+	// 成功的请求不重置统计窗口内的失败次数
+	if state := l.report("a", now, conf, true); state != StateClosed {
+		t.Fatalf("success: state = %v", state)
+	}
+	if state := l.report("a", now, conf, false); state != StateOpen {
+		t.Fatalf("threshold: state = %v, want open", state)
+	}
+
+	// 熔断期间拒绝请求，不影响其他上游服务
+	if allowed, _ := l.allow("a", now+29, conf); allowed {
+		t.Fatal("open breaker allowed request")
+	}
+	if allowed, probe := l.allow("b", now, conf); !allowed || probe {
+		t.Fatalf("other host: allowed = %v, probe = %v", allowed, probe)
+	}
+}
+
+func TestLocalWindowReset(t *testing.T) {
+	l := newLocal()
+	conf := Config{FailureThreshold: 2, Window: 10, OpenTimeout: 30, HalfOpenProbes: 1}
+
+	l.report("a", 1000, conf, false)
+	// 超过统计窗口之后重新计算失败次数
+	if state := l.report("a", 1010, conf, false); state != StateClosed {
+		t.Fatalf("state = %v, want closed", state)
+	}
+	if state := l.report("a", 1011, conf, false); state != StateOpen {
+		t.Fatalf("state = %v, want open", state)
+	}
+}
+
+func TestLocalHalfOpen(t *testing.T) {
+	conf := Config{FailureThreshold: 1, Window: 60, OpenTimeout: 30, HalfOpenProbes: 2}
+
+	open := func() *localStore {
+		l := newLocal()
+		if state := l.report("a", 1000, conf, false); state != StateOpen {
+			t.Fatalf("state = %v, want open", state)
+		}
+		return l
+	}
+
+	// 熔断时长结束之后只放行指定数量的探测请求
+	l := open()
+	for i := 0; i < 2; i++ {
+		if allowed, probe := l.allow("a", 1030, conf); !allowed || !probe {
+			t.Fatalf("probe %v: allowed = %v, probe = %v", i+1, allowed, probe)
+		}
+	}
+	if allowed, _ := l.allow("a", 1030, conf); allowed {
+		t.Fatal("allowed more probes than halfOpenProbes")
+	}
+
+	// 探测请求全部成功之后关闭熔断
+	if state := l.report("a", 1031, conf, true); state != StateHalfOpen {
+		t.Fatalf("first success: state = %v, want half_open", state)
+	}
+	if state := l.report("a", 1031, conf, true); state != StateClosed {
+		t.Fatalf("all success: state = %v, want closed", state)
+	}
+	if allowed, probe := l.allow("a", 1031, conf); !allowed || probe {
+		t.Fatalf("closed: allowed = %v, probe = %v", allowed, probe)
+	}
+
+	// 探测请求失败时重新熔断
+	l = open()
+	l.allow("a", 1030, conf)
+	if state := l.report("a", 1031, conf, false); state != StateOpen {
+		t.Fatalf("probe failure: state = %v, want open", state)
+	}
+	if allowed, _ := l.allow("a", 1060, conf); allowed {
+		t.Fatal("reopened breaker allowed request before openTimeout")
+	}
+	if allowed, probe := l.allow("a", 1061, conf); !allowed || !probe {
+		t.Fatalf("after reopen: allowed = %v, probe = %v", allowed, probe)
+	}
+}
+
+func TestLocalRelease(t *testing.T) {
+	l := newLocal()
+	conf := Config{FailureThreshold: 1, Window: 60, OpenTimeout: 30, HalfOpenProbes: 1}
+	l.report("a", 1000, conf, false)
+
+	if allowed, _ := l.allow("a", 1030, conf); !allowed {
+		t.Fatal("probe not allowed")
+	}
+	if allowed, _ := l.allow("a", 1030, conf); allowed {
+		t.Fatal("probe slot not taken")
+	}
+
+	// 归还探测名额之后其他请求可以重新探测，且不计入探测结果
+	l.release("a")
+	if allowed, probe := l.allow("a", 1030, conf); !allowed || !probe {
+		t.Fatalf("after release: allowed = %v, probe = %v", allowed, probe)
+	}
+	if s := l.states["a"]; s.Successes != 0 || s.Probes != 1 {
+		t.Fatalf("state = %+v", s)
+	}
+
+	// 非半开状态时归还不产生影响
+	l.release("b")
+	if _, ok := l.states["b"]; ok {
+		t.Fatal("release created state")
+	}
+}
+
+func TestLocalExpire(t *testing.T) {
+	l := newLocal()
+	conf := Config{FailureThreshold: 1, Window: 10, OpenTimeout: 5, HalfOpenProbes: 1}
+	l.report("a", 1000, conf, false)
+	l.allow("a", 1005, conf)
+
+	// 探测请求异常退出未上报结果时，超过保存时长之后恢复为关闭状态
+	if allowed, _ := l.allow("a", 1019, conf); allowed {
+		t.Fatal("half open probe slot released before expire")
+	}
+	if allowed, probe := l.allow("a", 1020, conf); !allowed || probe {
+		t.Fatalf("after expire: allowed = %v, probe = %v", allowed, probe)
+	}
+}
+
+func TestLocalListReset(t *testing.T) {
+	l := newLocal()
+	conf := Config{FailureThreshold: 2}
+	l.report("a", 1000, conf.withDefault(), false)
+	l.report("b", 1000, conf.withDefault(), true)
+
+	// 没有失败记录的关闭状态不返回
+	list := l.list()
+	if len(list) != 1 || list[0].Host != "a" || list[0].Failures != 1 || list[0].Source != SourceLocal {
+		t.Fatalf("list = %+v", list)
+	}
+	list[0].Failures = 10
+	if l.states["a"].Failures != 1 {
+		t.Fatal("list returned internal state")
+	}
+
+	l.reset("a")
+	if len(l.list()) != 0 {
+		t.Fatal("state not reset")
+	}
+}
+
+func TestConfigDefault(t *testing.T) {
+	conf := Config{}.withDefault()
+	if conf != (Config{FailureThreshold: 5, Window: 60, OpenTimeout: 30, HalfOpenProbes: 1}) {
+		t.Fatalf("default = %+v", conf)
+	}
+	if conf.expire().Seconds() != 120 {
+		t.Fatalf("expire = %v", conf.expire())
+	}
+}
+
+func TestHost(t *testing.T) {
+	cases := map[string]string{
+		"http://127.0.0.1:8080/api?a=1": "127.0.0.1:8080",
+		"https://example.com/path":      "example.com",
+		"/api/v1":                       "",
+		"://bad":                        "",
+	}
+	for raw, want := range cases {
+		if got := Host(raw); got != want {
+			t.Errorf("Host(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestParseState(t *testing.T) {
+	s := parseState("a", map[string]string{"failures": "2", "window_start": "1000", "probes": "1"})
+	if s.State != StateClosed || s.Failures != 2 || s.WindowStart != 1000 || s.Probes != 1 || s.Source != SourceRedis {
+		t.Fatalf("state = %+v", s)
+	}
+}
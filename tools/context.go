@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"github.com/limeschool/gin"
+	"net/http/httptest"
+	"sync"
+)
+
+var (
+	baseOnce    sync.Once
+	baseContext *gin.Context
+)
+
+// CopyContext 复制请求上下文，用于请求返回之后仍需要继续执行的异步任务
+func CopyContext(ctx *gin.Context) *gin.Context {
+	cp := ctx.Copy()
+	cp.Log = ctx.Log
+	cp.TraceID = ctx.TraceID
+	cp.Config = ctx.Config
+	cp.ServiceName = ctx.ServiceName
+	return cp
+}
+
+// NewBackgroundContext 创建不依赖请求的上下文，用于定时任务等后台任务。
+// gin.NewContext创建的上下文不存在engine，作为context.Context使用时会panic，所以基于测试上下文进行复制
+func NewBackgroundContext() *gin.Context {
+	baseOnce.Do(func() {
+		baseContext, _ = gin.CreateTestContext(httptest.NewRecorder())
+	})
+
+	ctx := gin.NewContext()
+	cp := baseContext.Copy()
+	cp.Log = ctx.Log
+	cp.TraceID = ctx.TraceID
+	cp.Config = ctx.Config
+	cp.ServiceName = ctx.ServiceName
+	return cp
+}
+
+// NewTrx 生成请求唯一标志
+func NewTrx() string {
+	return "TRX" + UUID()
+}
@@ -0,0 +1,163 @@
+package cron
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func mustParse(t *testing.T, spec string) *Schedule {
+	t.Helper()
+	s, err := Parse(spec)
+	if err != nil {
+		t.Fatalf("Parse(%q) error: %v", spec, err)
+	}
+	return s
+}
+
+func TestParseError(t *testing.T) {
+	specs := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"1-2-3 * * * *",
+		"*/2/3 * * * *",
+	}
+	for _, spec := range specs {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) expected error", spec)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	utc := time.UTC
+	cases := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"每分钟", "* * * * *", time.Date(2023, 1, 1, 10, 0, 30, 0, utc), time.Date(2023, 1, 1, 10, 1, 0, 0, utc)},
+		{"整分钟时取下一分钟", "* * * * *", time.Date(2023, 1, 1, 10, 0, 0, 0, utc), time.Date(2023, 1, 1, 10, 1, 0, 0, utc)},
+		{"每天", "@daily", time.Date(2023, 1, 1, 10, 0, 0, 0, utc), time.Date(2023, 1, 2, 0, 0, 0, 0, utc)},
+		{"每小时", "@hourly", time.Date(2023, 1, 1, 10, 0, 0, 0, utc), time.Date(2023, 1, 1, 11, 0, 0, 0, utc)},
+		{"每年", "@yearly", time.Date(2023, 6, 1, 0, 0, 0, 0, utc), time.Date(2024, 1, 1, 0, 0, 0, 0, utc)},
+		{"每周日", "@weekly", time.Date(2023, 1, 2, 0, 0, 0, 0, utc), time.Date(2023, 1, 8, 0, 0, 0, 0, utc)},
+		{"列表", "0 8,20 * * *", time.Date(2023, 1, 1, 9, 0, 0, 0, utc), time.Date(2023, 1, 1, 20, 0, 0, 0, utc)},
+		{"范围", "0 9-11 * * *", time.Date(2023, 1, 1, 11, 30, 0, 0, utc), time.Date(2023, 1, 2, 9, 0, 0, 0, utc)},
+		{"步长", "*/15 * * * *", time.Date(2023, 1, 1, 10, 16, 0, 0, utc), time.Date(2023, 1, 1, 10, 30, 0, 0, utc)},
+		{"范围加步长", "10-40/10 * * * *", time.Date(2023, 1, 1, 10, 41, 0, 0, utc), time.Date(2023, 1, 1, 11, 10, 0, 0, utc)},
+		{"起点加步长", "50/5 * * * *", time.Date(2023, 1, 1, 10, 56, 0, 0, utc), time.Date(2023, 1, 1, 11, 50, 0, 0, utc)},
+		{"跨年", "0 0 1 1 *", time.Date(2023, 12, 31, 23, 59, 0, 0, utc), time.Date(2024, 1, 1, 0, 0, 0, 0, utc)},
+		{"31号跳过小月", "0 0 31 * *", time.Date(2023, 4, 1, 0, 0, 0, 0, utc), time.Date(2023, 5, 31, 0, 0, 0, 0, utc)},
+		{"闰年2月29日", "0 0 29 2 *", time.Date(2023, 3, 1, 0, 0, 0, 0, utc), time.Date(2024, 2, 29, 0, 0, 0, 0, utc)},
+		{"周日可以使用7", "0 0 * * 7", time.Date(2023, 1, 2, 0, 0, 0, 0, utc), time.Date(2023, 1, 8, 0, 0, 0, 0, utc)},
+		{"周末范围包含7", "0 0 * * 6-7", time.Date(2023, 1, 2, 0, 0, 0, 0, utc), time.Date(2023, 1, 7, 0, 0, 0, 0, utc)},
+		{"日为*时只匹配周", "0 0 * * 1", time.Date(2023, 1, 3, 0, 0, 0, 0, utc), time.Date(2023, 1, 9, 0, 0, 0, 0, utc)},
+		{"周为*时只匹配日", "0 0 15 * *", time.Date(2023, 1, 3, 0, 0, 0, 0, utc), time.Date(2023, 1, 15, 0, 0, 0, 0, utc)},
+		// 日和周同时设置时满足其中一个即可：2023-01-09是周一，早于1月15日
+		{"日和周取或-周先满足", "0 0 15 * 1", time.Date(2023, 1, 3, 0, 0, 0, 0, utc), time.Date(2023, 1, 9, 0, 0, 0, 0, utc)},
+		// 2023-01-15是周日，早于下一个周一1月16日
+		{"日和周取或-日先满足", "0 0 15 * 1", time.Date(2023, 1, 10, 0, 0, 0, 0, utc), time.Date(2023, 1, 15, 0, 0, 0, 0, utc)},
+		{"不存在的日期", "0 0 30 2 *", time.Date(2023, 1, 1, 0, 0, 0, 0, utc), time.Time{}},
+	}
+
+	for _, c := range cases {
+		got := mustParse(t, c.spec).Next(c.from)
+		if !got.Equal(c.want) {
+			t.Errorf("%v: Next(%q, %v) = %v, want %v", c.name, c.spec, c.from, got, c.want)
+		}
+	}
+}
+
+func TestNextDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 2023-03-12 02:00 EST 直接跳到 03:00 EDT，不存在的时间当天不执行
+	got := mustParse(t, "30 2 * * *").Next(time.Date(2023, 3, 12, 0, 0, 0, 0, loc))
+	want := time.Date(2023, 3, 13, 2, 30, 0, 0, loc)
+	if !got.Equal(want) {
+		t.Errorf("spring forward: got %v, want %v", got, want)
+	}
+
+	// 跳过的小时之后正常执行
+	got = mustParse(t, "0 * * * *").Next(time.Date(2023, 3, 12, 1, 30, 0, 0, loc))
+	want = time.Date(2023, 3, 12, 3, 0, 0, 0, loc)
+	if !got.Equal(want) || got.Sub(time.Date(2023, 3, 12, 1, 30, 0, 0, loc)) != 30*time.Minute {
+		t.Errorf("spring forward hourly: got %v, want %v", got, want)
+	}
+
+	// 2023-11-05 02:00 EDT 回拨到 01:00 EST，重复的时间按照墙上时间匹配，两次都会执行
+	first := mustParse(t, "30 1 * * *").Next(time.Date(2023, 11, 5, 0, 0, 0, 0, loc))
+	if first.Hour() != 1 || first.Minute() != 30 {
+		t.Fatalf("fall back first: got %v", first)
+	}
+	second := mustParse(t, "30 1 * * *").Next(first)
+	if second.Hour() != 1 || second.Minute() != 30 || second.Sub(first) != time.Hour {
+		t.Errorf("fall back second: got %v after %v", second, first)
+	}
+}
+
+// TestNextMatchesBruteForce Next的结果与逐分钟调用Match得到的结果保持一致
+func TestNextMatchesBruteForce(t *testing.T) {
+	specs := []string{
+		"* * * * *",
+		"*/7 * * * *",
+		"0 */5 * * *",
+		"15 10 * * 1-5",
+		"0 0 1,15 * *",
+		"0 0 13 * 5",
+		"30 23 31 * *",
+		"0 12 * 2 0",
+		"0 0 29 2 *",
+		"5-10/2 3 * * 6,7",
+	}
+	// America/Santiago 在零点切换夏令时，零点本身不存在
+	starts := []struct {
+		zone string
+		from time.Time
+	}{
+		{"Asia/Shanghai", time.Date(2023, 12, 30, 22, 17, 0, 0, time.UTC)},
+		{"America/New_York", time.Date(2023, 3, 11, 22, 17, 0, 0, time.UTC)},
+		{"America/New_York", time.Date(2023, 11, 4, 22, 17, 0, 0, time.UTC)},
+		{"America/Santiago", time.Date(2023, 9, 1, 22, 17, 0, 0, time.UTC)},
+	}
+
+	for _, start := range starts {
+		loc, err := time.LoadLocation(start.zone)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, spec := range append(specs, "0 0 * * *", "*/20 0-3 * * *") {
+			s := mustParse(t, spec)
+			from := start.from.In(loc)
+			for i := 0; i < 10; i++ {
+				next := s.Next(from)
+				if next.IsZero() {
+					t.Fatalf("Next(%q, %v) returned zero", spec, from)
+				}
+
+				expect := from.Truncate(time.Minute).Add(time.Minute)
+				for !s.Match(expect) {
+					expect = expect.Add(time.Minute)
+				}
+				if !next.Equal(expect) {
+					t.Fatalf("Next(%q, %v) = %v, brute force = %v", spec, from, next, expect)
+				}
+				from = next
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"ps-go/consts"
+	"ps-go/errors"
+	"strings"
+)
+
+const (
+	LineEqual   = "="
+	LineAdded   = "+"
+	LineRemoved = "-"
+)
+
+// DiffLine 逐行对比的结果，from、to为该行在对比前后文本中的行号，从1开始，不存在时为0
+type DiffLine struct {
+	Op   string `json:"op"`
+	From int    `json:"from,omitempty"`
+	To   int    `json:"to,omitempty"`
+	Line string `json:"line"`
+}
+
+// DiffLines 基于最长公共子序列逐行对比文本，返回从from到to的变更
+func DiffLines(from, to string) ([]DiffLine, error) {
+	a, b := splitLines(from), splitLines(to)
+	if len(a) > consts.DiffMaxLines || len(b) > consts.DiffMaxLines {
+		return nil, errors.NewF("文本行数超过对比上限%v", consts.DiffMaxLines)
+	}
+
+	// 去除相同的首尾，减少需要计算的范围
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	// lcs[i][j] 为 ma[i:] 与 mb[j:] 的最长公共子序列长度
+	lcs := make([][]int, len(ma)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(mb)+1)
+	}
+	for i := len(ma) - 1; i >= 0; i-- {
+		for j := len(mb) - 1; j >= 0; j-- {
+			if ma[i] == mb[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	list := make([]DiffLine, 0, len(a)+len(b)-prefix-suffix)
+	for i := 0; i < prefix; i++ {
+		list = append(list, DiffLine{Op: LineEqual, From: i + 1, To: i + 1, Line: a[i]})
+	}
+
+	i, j := 0, 0
+	for i < len(ma) || j < len(mb) {
+		switch {
+		case i < len(ma) && j < len(mb) && ma[i] == mb[j]:
+			list = append(list, DiffLine{Op: LineEqual, From: prefix + i + 1, To: prefix + j + 1, Line: ma[i]})
+			i++
+			j++
+		case j == len(mb) || (i < len(ma) && lcs[i+1][j] >= lcs[i][j+1]):
+			list = append(list, DiffLine{Op: LineRemoved, From: prefix + i + 1, Line: ma[i]})
+			i++
+		default:
+			list = append(list, DiffLine{Op: LineAdded, To: prefix + j + 1, Line: mb[j]})
+			j++
+		}
+	}
+
+	for k := 0; k < suffix; k++ {
+		list = append(list, DiffLine{
+			Op:   LineEqual,
+			From: len(a) - suffix + k + 1,
+			To:   len(b) - suffix + k + 1,
+			Line: a[len(a)-suffix+k],
+		})
+	}
+	return list, nil
+}
+
+// splitLines 按行拆分文本，兼容\r\n换行
+func splitLines(str string) []string {
+	if str == "" {
+		return nil
+	}
+	str = strings.ReplaceAll(str, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(str, "\n"), "\n")
+}
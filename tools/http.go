@@ -1,12 +1,16 @@
 package tools
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"github.com/valyala/fasthttp"
+	"net"
 	"ps-go/consts"
 	"ps-go/errors"
 	"strings"
+	"sync"
+	"time"
 	"unsafe"
 )
 
@@ -19,6 +23,7 @@ type HttpRequest struct {
 	Url          string            `json:"url"`
 	Method       string            `json:"method"`
 	Body         any               `json:"body"`
+	RawBody      []byte            `json:"-"` //原始请求数据，设置时按原样发送，不再对body进行序列化
 	Header       map[string]string `json:"header"`
 	Auth         []string          `json:"auth"`
 	ContentType  string            `json:"content_type"`
@@ -27,6 +32,7 @@ type HttpRequest struct {
 	Timeout      int               `json:"timeout"`
 	ResponseType string            `json:"response_type"`
 	Tls          *Tls              `json:"-"`
+	Ctx          context.Context   `json:"-"` //请求上下文，取消时立即结束请求
 	// 返回数据
 	respHeader  map[string]string
 	respCode    int
@@ -34,6 +40,43 @@ type HttpRequest struct {
 	respCookies map[string]string
 }
 
+// ctxConn 绑定了上下文的连接，上下文取消时关闭连接
+type ctxConn struct {
+	net.Conn
+	once sync.Once
+	stop chan struct{}
+}
+
+func (c *ctxConn) Close() error {
+	c.once.Do(func() {
+		close(c.stop)
+	})
+	return c.Conn.Close()
+}
+
+// dialContext 创建连接时绑定上下文，上下文取消之后不再建立新的连接
+func dialContext(ctx context.Context) fasthttp.DialFunc {
+	return func(addr string) (net.Conn, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		conn, err := fasthttp.Dial(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		c := &ctxConn{Conn: conn, stop: make(chan struct{})}
+		go func() {
+			select {
+			case <-ctx.Done():
+				_ = c.Close()
+			case <-c.stop:
+			}
+		}()
+		return c, nil
+	}
+}
+
 func (r *HttpRequest) ResponseHeader() map[string]string {
 	return r.respHeader
 }
@@ -50,6 +93,14 @@ func (r *HttpRequest) ResponseCookies() map[string]string {
 	return r.respCookies
 }
 
+// SetMockResponse 设置模拟的返回数据，不发送请求，用于流程试运行
+func (r *HttpRequest) SetMockResponse(body any) {
+	r.respCode = 200
+	r.respHeader = map[string]string{}
+	r.respCookies = map[string]string{}
+	r.respBody = body
+}
+
 func (r *HttpRequest) Result() (any, error) {
 	err := r.Do()
 	return r.respBody, err
@@ -94,7 +145,9 @@ func (r *HttpRequest) Do() error {
 
 	// 处理请求body
 	var data []byte
-	if r.Body != nil {
+	if r.RawBody != nil {
+		data = r.RawBody
+	} else if r.Body != nil {
 		if r.Method == "GET" {
 			r.Url += "?" + r.bodyToQuery()
 		} else {
@@ -109,9 +162,16 @@ func (r *HttpRequest) Do() error {
 		}
 	}
 
-	// 用完需要释放资源
+	// 用完需要释放资源，请求被取消时，等待请求真正结束之后再释放
 	req := fasthttp.AcquireRequest()
-	defer fasthttp.ReleaseRequest(req)
+	resp := fasthttp.AcquireResponse()
+	release := true
+	defer func() {
+		if release {
+			fasthttp.ReleaseRequest(req)
+			fasthttp.ReleaseResponse(resp)
+		}
+	}()
 
 	// 设置请求信息
 	if len(r.Auth) == 2 {
@@ -133,14 +193,41 @@ func (r *HttpRequest) Do() error {
 	req.Header.SetMethod(r.Method)
 	req.SetBody(data)
 
-	// 用完需要释放资源
-	resp := fasthttp.AcquireResponse()
-	defer fasthttp.ReleaseResponse(resp)
-
-	// 发起请求
+	// 发起请求，上下文取消时关闭连接，使进行中的请求立即结束，不再占用连接
 	client := fasthttp.Client{TLSConfig: tlsc}
-	if err = client.Do(req, resp); err != nil {
-		return err
+	if r.Ctx != nil {
+		client.Dial = dialContext(r.Ctx)
+	}
+	timeout := time.Duration(r.Timeout) * time.Second
+	if r.Ctx == nil {
+		if err = client.DoTimeout(req, resp, timeout); err != nil {
+			return err
+		}
+	} else {
+		// 请求超时时间不超过上下文的截止时间
+		if deadline, ok := r.Ctx.Deadline(); ok && time.Until(deadline) < timeout {
+			timeout = time.Until(deadline)
+		}
+
+		done := make(chan error, 1)
+		go func() {
+			done <- client.DoTimeout(req, resp, timeout)
+		}()
+
+		select {
+		case err = <-done:
+			if err != nil {
+				return err
+			}
+		case <-r.Ctx.Done():
+			release = false
+			go func() {
+				<-done
+				fasthttp.ReleaseRequest(req)
+				fasthttp.ReleaseResponse(resp)
+			}()
+			return errors.NewF("request canceled:%v", r.Ctx.Err())
+		}
 	}
 
 	// 获取返回信息
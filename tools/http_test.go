@@ -0,0 +1,53 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHttpRequestCancel(t *testing.T) {
+	closed := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// 客户端关闭连接之后请求的上下文才会结束
+		<-req.Context().Done()
+		close(closed)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	req := HttpRequest{Url: srv.URL, Method: "GET", RequestType: "json", ResponseType: "json", Timeout: 30, Ctx: ctx}
+	start := time.Now()
+	if err := req.Do(); err == nil {
+		t.Fatal("expected canceled error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Do returned after %v", elapsed)
+	}
+
+	// 取消之后连接同时被关闭，而不是等到请求超时
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("connection not closed after cancel")
+	}
+}
+
+func TestHttpRequestCanceledBeforeDial(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		t.Error("request should not be sent")
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req := HttpRequest{Url: srv.URL, Method: "GET", RequestType: "json", ResponseType: "json", Timeout: 30, Ctx: ctx}
+	if err := req.Do(); err == nil {
+		t.Fatal("expected canceled error")
+	}
+}
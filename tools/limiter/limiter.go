@@ -0,0 +1,165 @@
+package limiter
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"github.com/limeschool/gin"
+	"go.uber.org/zap"
+	"math"
+	"ps-go/consts"
+	"sync"
+	"time"
+)
+
+const (
+	rateKeyPrefix        = "limit_rate_"
+	concurrencyKeyPrefix = "limit_concurrency_"
+)
+
+// rateScript 令牌桶限流，按照时间补充令牌，令牌不足时拒绝，1放行 0拒绝
+var rateScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local tokens = tonumber(redis.call('HGET', KEYS[1], 'tokens'))
+local last = tonumber(redis.call('HGET', KEYS[1], 'last'))
+if not tokens or not last then
+	tokens = burst
+	last = now
+end
+tokens = math.min(burst, tokens + math.max(0, now - last) * rate / 1000)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'last', now)
+redis.call('PEXPIRE', KEYS[1], ARGV[4])
+return allowed
+`)
+
+// concurrencyScript 获取并发许可，先清理已经过期的许可，1放行 0拒绝
+var concurrencyScript = redis.NewScript(`
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])
+if redis.call('ZCARD', KEYS[1]) >= tonumber(ARGV[2]) then
+	return 0
+end
+redis.call('ZADD', KEYS[1], ARGV[3], ARGV[4])
+redis.call('PEXPIRE', KEYS[1], ARGV[5])
+return 1
+`)
+
+var local = &localStore{
+	buckets: map[string]*bucket{},
+	holders: map[string]map[string]int64{},
+}
+
+// AllowRate 令牌桶限流，rate为每秒补充的令牌数量，burst为令牌桶的容量
+func AllowRate(ctx *gin.Context, key string, rate float64, burst int) bool {
+	if burst <= 0 {
+		burst = int(math.Ceil(rate))
+	}
+	now := time.Now().UnixMilli()
+	// 令牌桶补满之后不再需要保存
+	expire := int64(float64(burst)/rate*1000) + 1000
+
+	if client := ctx.Redis(consts.ProcessScheduleCache); client != nil {
+		res, err := rateScript.Run(context.TODO(), client, []string{rateKeyPrefix + key}, rate, burst, now, expire).Int()
+		if err == nil {
+			return res == 1
+		}
+		ctx.Log.Warn("限流redis不可用，使用本地限流", zap.Any("key", key), zap.Any("err", err))
+	}
+	return local.allowRate(key, rate, burst, now)
+}
+
+// Acquire 获取并发许可，id为本次执行的唯一标志。
+// 许可在expire之后自动失效，防止实例异常退出时许可无法释放
+func Acquire(ctx *gin.Context, key, id string, max int, expire time.Duration) bool {
+	now := time.Now().UnixMilli()
+
+	if client := ctx.Redis(consts.ProcessScheduleCache); client != nil {
+		res, err := concurrencyScript.Run(context.TODO(), client, []string{concurrencyKeyPrefix + key},
+			now-expire.Milliseconds(), max, now, id, expire.Milliseconds()).Int()
+		if err == nil {
+			return res == 1
+		}
+		ctx.Log.Warn("并发控制redis不可用，使用本地并发控制", zap.Any("key", key), zap.Any("err", err))
+	}
+	return local.acquire(key, id, max, now, expire.Milliseconds())
+}
+
+// Release 释放并发许可
+func Release(ctx *gin.Context, key, id string) {
+	local.release(key, id)
+	if client := ctx.Redis(consts.ProcessScheduleCache); client != nil {
+		client.ZRem(context.TODO(), concurrencyKeyPrefix+key, id)
+	}
+}
+
+type bucket struct {
+	tokens float64
+	last   int64
+}
+
+// localStore redis不可用时使用的进程内限流状态
+type localStore struct {
+	lock    sync.Mutex
+	buckets map[string]*bucket
+	holders map[string]map[string]int64
+}
+
+func (l *localStore) allowRate(key string, rate float64, burst int, now int64) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(burst), last: now}
+		l.buckets[key] = b
+	}
+
+	if now > b.last {
+		b.tokens = math.Min(float64(burst), b.tokens+float64(now-b.last)*rate/1000)
+		b.last = now
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (l *localStore) acquire(key, id string, max int, now, expire int64) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	holders, ok := l.holders[key]
+	if !ok {
+		holders = map[string]int64{}
+		l.holders[key] = holders
+	}
+
+	for item, t := range holders {
+		if now-t > expire {
+			delete(holders, item)
+		}
+	}
+	if len(holders) >= max {
+		return false
+	}
+	holders[id] = now
+	return true
+}
+
+func (l *localStore) release(key, id string) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if holders, ok := l.holders[key]; ok {
+		delete(holders, id)
+		if len(holders) == 0 {
+			delete(l.holders, key)
+		}
+	}
+}
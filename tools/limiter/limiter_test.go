@@ -0,0 +1,109 @@
+package limiter
+
+import (
+	"testing"
+)
+
+func newLocal() *localStore {
+	return &localStore{
+		buckets: map[string]*bucket{},
+		holders: map[string]map[string]int64{},
+	}
+}
+
+func TestLocalAllowRate(t *testing.T) {
+	l := newLocal()
+	now := int64(1000000)
+
+	// 初始时令牌桶是满的，可以处理burst个突发请求
+	for i := 0; i < 3; i++ {
+		if !l.allowRate("a", 2, 3, now) {
+			t.Fatalf("burst request %v rejected", i+1)
+		}
+	}
+	if l.allowRate("a", 2, 3, now) {
+		t.Fatal("request allowed after burst")
+	}
+
+	// 每秒补充rate个令牌
+	if l.allowRate("a", 2, 3, now+499) {
+		t.Fatal("request allowed before token refilled")
+	}
+	if !l.allowRate("a", 2, 3, now+500) {
+		t.Fatal("request rejected after token refilled")
+	}
+
+	// 补充的令牌不超过令牌桶容量
+	later := now + 60000
+	for i := 0; i < 3; i++ {
+		if !l.allowRate("a", 2, 3, later) {
+			t.Fatalf("refilled request %v rejected", i+1)
+		}
+	}
+	if l.allowRate("a", 2, 3, later) {
+		t.Fatal("tokens exceeded burst")
+	}
+
+	// 不同的key互不影响
+	if !l.allowRate("b", 2, 3, now) {
+		t.Fatal("other key rejected")
+	}
+}
+
+func TestLocalAllowRateClockBack(t *testing.T) {
+	l := newLocal()
+	l.allowRate("a", 1, 1, 2000)
+
+	// 时间回拨时不补充令牌
+	if l.allowRate("a", 1, 1, 1000) {
+		t.Fatal("request allowed after clock moved back")
+	}
+	if !l.allowRate("a", 1, 1, 3000) {
+		t.Fatal("request rejected after refill")
+	}
+}
+
+func TestLocalAcquireRelease(t *testing.T) {
+	l := newLocal()
+	now := int64(1000000)
+
+	if !l.acquire("a", "1", 2, now, 1000) || !l.acquire("a", "2", 2, now, 1000) {
+		t.Fatal("acquire rejected under max")
+	}
+	if l.acquire("a", "3", 2, now, 1000) {
+		t.Fatal("acquire allowed over max")
+	}
+
+	// 释放之后其他执行可以获取许可
+	l.release("a", "1")
+	if !l.acquire("a", "3", 2, now, 1000) {
+		t.Fatal("acquire rejected after release")
+	}
+
+	// 全部释放之后清理key
+	l.release("a", "2")
+	l.release("a", "3")
+	if _, ok := l.holders["a"]; ok {
+		t.Fatal("holders not cleaned")
+	}
+
+	// 释放不存在的许可不产生影响
+	l.release("b", "1")
+}
+
+func TestLocalAcquireExpire(t *testing.T) {
+	l := newLocal()
+	now := int64(1000000)
+	l.acquire("a", "1", 1, now, 1000)
+
+	// 许可过期之后自动失效，防止未释放的许可一直占用
+	if l.acquire("a", "2", 1, now+1000, 1000) {
+		t.Fatal("acquire allowed before expire")
+	}
+	if !l.acquire("a", "2", 1, now+1001, 1000) {
+		t.Fatal("acquire rejected after expire")
+	}
+	if _, ok := l.holders["a"]["1"]; ok {
+		t.Fatal("expired holder not removed")
+	}
+}
@@ -52,3 +52,25 @@ func getMapByAny(key string, data any) any {
 		return nil
 	}
 }
+
+// CopyData 深拷贝map以及slice数据，其他类型直接返回
+func CopyData(data any) any {
+	switch data.(type) {
+	case map[string]any:
+		temp := data.(map[string]any)
+		resp := make(map[string]any, len(temp))
+		for key, val := range temp {
+			resp[key] = CopyData(val)
+		}
+		return resp
+	case []any:
+		temp := data.([]any)
+		resp := make([]any, len(temp))
+		for key, val := range temp {
+			resp[key] = CopyData(val)
+		}
+		return resp
+	default:
+		return data
+	}
+}
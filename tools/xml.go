@@ -16,7 +16,7 @@ func AnyToJsonString(data any) string {
 
 func StrToAny(str string, data any) error {
 	j := gjson.New(str)
-	return j.Scan(&data)
+	return j.Scan(data)
 }
 
 func XmlToAny(xml string, data any) error {
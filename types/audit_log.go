@@ -0,0 +1,18 @@
+package types
+
+type GetAuditLogRequest struct {
+	ID int64 `json:"id" form:"id" binding:"required"`
+}
+
+type PageAuditLogRequest struct {
+	Page  int `json:"page" form:"page" binding:"required" sql:"-"`
+	Count int `json:"count" form:"count"  binding:"required,max=50"  sql:"-"`
+
+	Entity     string `json:"entity" form:"entity"`
+	EntityKey  string `json:"entity_key" form:"entity_key"`
+	Action     string `json:"action" form:"action"`
+	Operator   string `json:"operator" form:"operator"`
+	OperatorID int64  `json:"operator_id" form:"operator_id"`
+	Start      int64  `json:"start" form:"start" sql:"> ?" field:"created_at"`
+	End        int64  `json:"end" form:"end" sql:"< ?" field:"created_at"`
+}
@@ -0,0 +1,10 @@
+package types
+
+type ListBreakerRequest struct {
+	Host  string `json:"host" form:"host"`
+	State string `json:"state" form:"state"`
+}
+
+type ResetBreakerRequest struct {
+	Host string `json:"host" binding:"required"`
+}
@@ -0,0 +1,48 @@
+package types
+
+import "ps-go/engine"
+
+type ExportRuleRequest struct {
+	ID      int64  `json:"id" form:"id"`
+	Name    string `json:"name" form:"name"`
+	Method  string `json:"method" form:"method"`
+	Version string `json:"version" form:"version"`
+}
+
+// Bundle 规则的导出包，包含规则、规则引用的全部脚本以及引用的密钥名，不包含密钥内容
+type Bundle struct {
+	Format     string         `json:"format" binding:"required"`
+	ExportedAt int64          `json:"exported_at"`
+	Rule       BundleRule     `json:"rule" binding:"required"`
+	Scripts    []BundleScript `json:"scripts"`
+	Secrets    []string       `json:"secrets"`
+}
+
+type BundleRule struct {
+	Name    string `json:"name" binding:"required"`
+	Method  string `json:"method" binding:"required"`
+	Version string `json:"version"`
+	Rule    string `json:"rule" binding:"required"`
+}
+
+type BundleScript struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Script  string `json:"script,omitempty"`
+}
+
+type ImportRuleRequest struct {
+	Bundle     Bundle `json:"bundle" binding:"required"`
+	Activate   bool   `json:"activate"` //导入之后跳过审核直接发布
+	Force      bool   `json:"force"`    //发布规则时跳过测试用例
+	Operator   string `json:"operator" binding:"required"`
+	OperatorID int64  `json:"operator_id" binding:"required"`
+}
+
+type ImportRuleResponse struct {
+	Imported       bool                `json:"imported"`
+	MissingSecrets []string            `json:"missing_secrets"`
+	Diagnostics    []engine.Diagnostic `json:"diagnostics"`
+	Rule           BundleRule          `json:"rule"`
+	Scripts        []BundleScript      `json:"scripts"`
+}
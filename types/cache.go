@@ -0,0 +1,13 @@
+package types
+
+type ListCacheRequest struct {
+	Name      string `json:"name" form:"name" binding:"required"`
+	Method    string `json:"method" form:"method" binding:"required"`
+	Component string `json:"component" form:"component"`
+}
+
+type PurgeCacheRequest struct {
+	Name      string `json:"name" binding:"required"`
+	Method    string `json:"method" binding:"required"`
+	Component string `json:"component"`
+}
@@ -0,0 +1,13 @@
+package types
+
+type PageNotifyLogRequest struct {
+	Page  int `json:"page" form:"page" binding:"required" sql:"-"`
+	Count int `json:"count" form:"count"  binding:"required,max=50"  sql:"-"`
+
+	Trx     string `json:"trx" form:"trx"`
+	Name    string `json:"name" form:"name"`
+	Event   string `json:"event" form:"event"`
+	Success *bool  `json:"success" form:"success"`
+	Start   int64  `json:"start" form:"start" sql:"> ?" field:"created_at"`
+	End     int64  `json:"end" form:"end" sql:"< ?" field:"created_at"`
+}
@@ -0,0 +1,34 @@
+package types
+
+type GetReviewRequest struct {
+	Kind    string `json:"kind" form:"kind" binding:"required,oneof=rule script"`
+	Version string `json:"version" form:"version" binding:"required"`
+}
+
+type PageReviewRequest struct {
+	Page     int    `json:"page" form:"page" binding:"required" sql:"-"`
+	Count    int    `json:"count" form:"count"  binding:"required,max=50"  sql:"-"`
+	Kind     string `json:"kind" form:"kind"`
+	State    string `json:"state" form:"state"`
+	Name     string `json:"name" form:"name"`
+	Method   string `json:"method" form:"method"`
+	AuthorID int64  `json:"author_id" form:"author_id"`
+}
+
+type ReviewRequest struct {
+	Kind       string `json:"kind" binding:"required,oneof=rule script"`
+	Version    string `json:"version" binding:"required"`
+	Comment    string `json:"comment"`
+	Operator   string `json:"operator" binding:"required"`
+	OperatorID int64  `json:"operator_id" binding:"required"`
+}
+
+type PublishReviewRequest struct {
+	Kind       string `json:"kind" binding:"required,oneof=rule script"`
+	Version    string `json:"version" binding:"required"`
+	PublishAt  int64  `json:"publish_at"` //定时发布的时间戳/s，为空时立即发布
+	Force      bool   `json:"force"`      //发布规则时是否跳过测试用例
+	Comment    string `json:"comment"`
+	Operator   string `json:"operator" binding:"required"`
+	OperatorID int64  `json:"operator_id" binding:"required"`
+}
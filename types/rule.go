@@ -26,8 +26,30 @@ type AddRuleRequest struct {
 	OperatorID int64  `json:"operator_id" binding:"required"`
 }
 
+type ValidateRuleRequest struct {
+	Rule string `json:"rule" binding:"required"`
+}
+
+type DryRunRuleRequest struct {
+	ID      int64          `json:"id"`
+	Version string         `json:"version"`
+	Rule    string         `json:"rule"`
+	Name    string         `json:"name"`
+	Method  string         `json:"method"`
+	Request DryRunRequest  `json:"request"`
+	Mocks   map[string]any `json:"mocks"`
+}
+
+type DryRunRequest struct {
+	Query  map[string]any `json:"query"`
+	Header map[string]any `json:"header"`
+	Body   map[string]any `json:"body"`
+}
+
 type SwitchVersionRuleRequest struct {
 	ID         int64  `json:"id" binding:"required"`
+	Force      bool   `json:"force"`
+	Comment    string `json:"comment"`
 	Operator   string `json:"operator"`
 	OperatorID int64  `json:"operator_id"`
 }
@@ -37,3 +59,72 @@ type DeleteRuleRequest struct {
 	Operator   string `json:"operator" binding:"required"`
 	OperatorID int64  `json:"operator_id" binding:"required"`
 }
+
+type GetRuleLimitRequest struct {
+	Name   string `json:"name" form:"name" binding:"required"`
+	Method string `json:"method" form:"method" binding:"required"`
+}
+
+type UpdateRuleLimitRequest struct {
+	Name       string         `json:"name" binding:"required"`
+	Method     string         `json:"method" binding:"required"`
+	Limit      map[string]any `json:"limit" binding:"required" copier:"-"`
+	Operator   string         `json:"operator" binding:"required"`
+	OperatorID int64          `json:"operator_id" binding:"required"`
+}
+
+type DeleteRuleLimitRequest struct {
+	Name       string `json:"name" binding:"required"`
+	Method     string `json:"method" binding:"required"`
+	Operator   string `json:"operator" binding:"required"`
+	OperatorID int64  `json:"operator_id" binding:"required"`
+}
+
+type GetRuleCanaryRequest struct {
+	Name   string `json:"name" form:"name" binding:"required"`
+	Method string `json:"method" form:"method" binding:"required"`
+}
+
+type UpdateRuleCanaryRequest struct {
+	Name       string           `json:"name" binding:"required"`
+	Method     string           `json:"method" binding:"required"`
+	Version    string           `json:"version" binding:"required"`
+	Weight     int              `json:"weight" binding:"min=0,max=100"`
+	Matches    []map[string]any `json:"matches" copier:"-"`
+	StickyKey  string           `json:"sticky_key"`
+	Operator   string           `json:"operator" binding:"required"`
+	OperatorID int64            `json:"operator_id" binding:"required"`
+}
+
+type PromoteRuleCanaryRequest struct {
+	Name       string `json:"name" binding:"required"`
+	Method     string `json:"method" binding:"required"`
+	Force      bool   `json:"force"`
+	Operator   string `json:"operator" binding:"required"`
+	OperatorID int64  `json:"operator_id" binding:"required"`
+}
+
+type DeleteRuleCanaryRequest struct {
+	Name       string `json:"name" binding:"required"`
+	Method     string `json:"method" binding:"required"`
+	Operator   string `json:"operator" binding:"required"`
+	OperatorID int64  `json:"operator_id" binding:"required"`
+}
+
+type DiffRuleRequest struct {
+	From string `json:"from" form:"from" binding:"required"`
+	To   string `json:"to" form:"to" binding:"required"`
+}
+
+type GetRuleRetentionRequest struct {
+	Name   string `json:"name" form:"name" binding:"required"`
+	Method string `json:"method" form:"method" binding:"required"`
+}
+
+type UpdateRuleRetentionRequest struct {
+	Name         string `json:"name" binding:"required"`
+	Method       string `json:"method" binding:"required"`
+	HistoryCount int    `json:"history_count" binding:"required"`
+	Operator     string `json:"operator" binding:"required"`
+	OperatorID   int64  `json:"operator_id" binding:"required"`
+}
@@ -0,0 +1,49 @@
+package types
+
+type PageRuleCaseRequest struct {
+	Page   int    `json:"page" form:"page" binding:"required" sql:"-"`
+	Count  int    `json:"count" form:"count"  binding:"required,max=50"  sql:"-"`
+	Name   string `json:"name" form:"name"`
+	Method string `json:"method" form:"method"`
+}
+
+type AddRuleCaseRequest struct {
+	Name       string           `json:"name" binding:"required"`
+	Method     string           `json:"method" binding:"required"`
+	Title      string           `json:"title" binding:"required"`
+	Request    DryRunRequest    `json:"request" copier:"-"`
+	Mocks      map[string]any   `json:"mocks" copier:"-"`
+	Assertions []map[string]any `json:"assertions" copier:"-"`
+	Operator   string           `json:"operator" binding:"required"`
+	OperatorID int64            `json:"operator_id" binding:"required"`
+}
+
+type UpdateRuleCaseRequest struct {
+	ID         int64            `json:"id" binding:"required"`
+	Title      string           `json:"title"`
+	Request    *DryRunRequest   `json:"request" copier:"-"`
+	Mocks      map[string]any   `json:"mocks" copier:"-"`
+	Assertions []map[string]any `json:"assertions" copier:"-"`
+	Operator   string           `json:"operator" binding:"required"`
+	OperatorID int64            `json:"operator_id" binding:"required"`
+}
+
+type DeleteRuleCaseRequest struct {
+	ID         int64  `json:"id" binding:"required"`
+	Operator   string `json:"operator" binding:"required"`
+	OperatorID int64  `json:"operator_id" binding:"required"`
+}
+
+type RunRuleCaseRequest struct {
+	Version string `json:"version" binding:"required"`
+}
+
+type PageRuleCaseResultRequest struct {
+	Page    int    `json:"page" form:"page" binding:"required" sql:"-"`
+	Count   int    `json:"count" form:"count"  binding:"required,max=50"  sql:"-"`
+	Name    string `json:"name" form:"name"`
+	Method  string `json:"method" form:"method"`
+	Version string `json:"version" form:"version"`
+	CaseID  int64  `json:"case_id" form:"case_id"`
+	Passed  *bool  `json:"passed" form:"passed"`
+}
@@ -3,3 +3,7 @@ package types
 type GetRunLogRequest struct {
 	Trx string `json:"trx" form:"trx" binding:"required"`
 }
+
+type GetRunStatusRequest struct {
+	Trx string `json:"trx" form:"trx" binding:"required"`
+}
@@ -0,0 +1,36 @@
+package types
+
+type PageScheduleRequest struct {
+	Page  int `json:"page" form:"page" binding:"required" sql:"-"`
+	Count int `json:"count" form:"count"  binding:"required,max=50"  sql:"-"`
+
+	Name   string `json:"name" form:"name"`
+	Method string `json:"method" form:"method"`
+	Status *bool  `json:"status" form:"status"`
+	Start  int64  `json:"start" form:"start" sql:"> ?" field:"created_at"`
+	End    int64  `json:"end" form:"end" sql:"< ?" field:"created_at"`
+}
+
+type AddScheduleRequest struct {
+	Name        string         `json:"name" binding:"required"`
+	Method      string         `json:"method" binding:"required"`
+	Cron        string         `json:"cron" binding:"required"`
+	Request     map[string]any `json:"request" copier:"-"`
+	Description string         `json:"description" binding:"required"`
+	Operator    string         `json:"operator" binding:"required"`
+	OperatorID  int64          `json:"operator_id" binding:"required"`
+}
+
+type DeleteScheduleRequest struct {
+	ID         int64  `json:"id" binding:"required"`
+	Operator   string `json:"operator" binding:"required"`
+	OperatorID int64  `json:"operator_id" binding:"required"`
+}
+
+type ScheduleStatusRequest struct {
+	ID int64 `json:"id" binding:"required"`
+}
+
+type TriggerScheduleRequest struct {
+	ID int64 `json:"id" binding:"required"`
+}
@@ -26,6 +26,7 @@ type AddScriptRequest struct {
 
 type SwitchVersionScriptRequest struct {
 	ID         int64  `json:"id" binding:"required"`
+	Comment    string `json:"comment"`
 	Operator   string `json:"operator"`
 	OperatorID int64  `json:"operator_id"`
 }
@@ -35,3 +36,8 @@ type DeleteScriptRequest struct {
 	Operator   string `json:"operator" binding:"required"`
 	OperatorID int64  `json:"operator_id" binding:"required"`
 }
+
+type DiffScriptRequest struct {
+	From string `json:"from" form:"from" binding:"required"`
+	To   string `json:"to" form:"to" binding:"required"`
+}
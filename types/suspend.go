@@ -10,6 +10,7 @@ type PageSuspendRequest struct {
 	Count int `json:"count" form:"count"  binding:"required,max=50"  sql:"-"`
 
 	Trx     string `json:"trx" form:"trx"`
+	Type    string `json:"type" form:"type"`
 	Method  string `json:"method" form:"method"`
 	Path    string `json:"path" form:"path"`
 	Version string `json:"version" form:"version"`
@@ -19,8 +20,10 @@ type PageSuspendRequest struct {
 }
 
 type SuspendRecoverRequest struct {
-	Trx  string         `json:"trx"`
-	Data map[string]any `json:"data"`
+	Trx        string         `json:"trx"`
+	Data       map[string]any `json:"data"`
+	Operator   string         `json:"operator"`
+	OperatorID int64          `json:"operator_id"`
 }
 
 type UpdateSuspendRequest struct {
@@ -29,4 +32,8 @@ type UpdateSuspendRequest struct {
 	Rule     map[string]any `json:"rule"`
 	CurStep  int            `json:"cur_step"`
 	ErrNames []string       `json:"err_names"`
+
+	FinishNames []string `json:"finish_names"`
+	Operator    string   `json:"operator"`
+	OperatorID  int64    `json:"operator_id"`
 }
@@ -0,0 +1,19 @@
+package types
+
+type PageWaitRequest struct {
+	Page  int `json:"page" form:"page" binding:"required" sql:"-"`
+	Count int `json:"count" form:"count"  binding:"required,max=50"  sql:"-"`
+
+	Trx    string `json:"trx" form:"trx"`
+	Method string `json:"method" form:"method"`
+	Path   string `json:"path" form:"path"`
+	LogID  string `json:"log_id" form:"log_id"`
+	Start  int64  `json:"start" form:"start" sql:"> ?" field:"created_at"`
+	End    int64  `json:"end" form:"end" sql:"< ?" field:"created_at"`
+}
+
+type SignalRequest struct {
+	Trx     string         `json:"trx" binding:"required"`
+	Signal  string         `json:"signal" binding:"required"`
+	Payload map[string]any `json:"payload"`
+}